@@ -0,0 +1,138 @@
+package bls
+
+import (
+	"errors"
+
+	"scrypto/ecc/bls381"
+	"scrypto/ecc/bls381Utils"
+)
+
+var (
+	errEmptyAggregate   = errors.New("nothing to aggregate")
+	errInvalidSignature = errors.New("invalid signature")
+	errInvalidPublicKey = errors.New("invalid public key")
+)
+
+// Aggregate returns the sum of signatures, each one must decode to a point of
+// the signature group
+func (s *Scheme) Aggregate(signatures [][]byte) ([]byte, error) {
+	if len(signatures) == 0 {
+		return nil, errEmptyAggregate
+	}
+	if s.minPk {
+		var sum bls381Utils.G2
+		for i, sig := range signatures {
+			p, ok := decodeG2(sig)
+			if !ok {
+				return nil, errInvalidSignature
+			}
+			if i == 0 {
+				sum.Set(p)
+			} else {
+				sum.Add(bls381Utils.BLSCurve, p)
+			}
+		}
+		var res bls381.G2Affine
+		return sum.ToAffineFromJac(&res).Bytes(), nil
+	}
+	var sum bls381Utils.G1
+	for i, sig := range signatures {
+		p, ok := decodeG1(sig)
+		if !ok {
+			return nil, errInvalidSignature
+		}
+		if i == 0 {
+			sum.Set(p)
+		} else {
+			sum.Add(bls381Utils.BLSCurve, p)
+		}
+	}
+	var res bls381.G1Affine
+	return sum.ToAffineFromJac(&res).Bytes(), nil
+}
+
+// aggregatePublicKeys returns the sum of the public keys in the public key
+// group, in the first result for MinPk and in the second for MinSig
+func (s *Scheme) aggregatePublicKeys(pks [][]byte) (*bls381Utils.G1, *bls381Utils.G2, error) {
+	if len(pks) == 0 {
+		return nil, nil, errEmptyAggregate
+	}
+	pkG1, pkG2, ok := s.publicKeys(pks)
+	if !ok {
+		return nil, nil, errInvalidPublicKey
+	}
+	if s.minPk {
+		sum := new(bls381Utils.G1).Set(pkG1[0])
+		for _, p := range pkG1[1:] {
+			sum.Add(bls381Utils.BLSCurve, p)
+		}
+		return sum, nil, nil
+	}
+	sum := new(bls381Utils.G2).Set(pkG2[0])
+	for _, p := range pkG2[1:] {
+		sum.Add(bls381Utils.BLSCurve, p)
+	}
+	return nil, sum, nil
+}
+
+// AggregatePublicKeys returns the sum of pks, each one must pass KeyValidate.
+// The sum verifies signatures aggregated from the same message, it is only
+// sound if every key comes with a proof of possession of its secret key, so
+// it fails on the basic scheme.
+func (s *Scheme) AggregatePublicKeys(pks [][]byte) ([]byte, error) {
+	if !s.HasPop() {
+		return nil, errNoPop
+	}
+	g1, g2, err := s.aggregatePublicKeys(pks)
+	if err != nil {
+		return nil, err
+	}
+	if s.minPk {
+		var res bls381.G1Affine
+		return g1.ToAffineFromJac(&res).Bytes(), nil
+	}
+	var res bls381.G2Affine
+	return g2.ToAffineFromJac(&res).Bytes(), nil
+}
+
+// AggregateVerify reports whether signature aggregates the signatures of
+// messages[i] under pks[i], the basic scheme requires distinct messages, the
+// proof of possession scheme does not
+func (s *Scheme) AggregateVerify(pks, messages [][]byte, signature []byte) bool {
+	if len(pks) == 0 || len(pks) != len(messages) {
+		return false
+	}
+	if s.popID != "" {
+		return s.verify(pks, messages, signature, s.id)
+	}
+	seen := make(map[string]struct{}, len(messages))
+	for _, m := range messages {
+		if _, ok := seen[string(m)]; ok {
+			return false
+		}
+		seen[string(m)] = struct{}{}
+	}
+	return s.verify(pks, messages, signature, s.id)
+}
+
+// FastAggregateVerify reports whether signature aggregates the signatures of
+// message under every key of pks, with one hash and a two pairings check on
+// the sum of pks. As AggregatePublicKeys, it is only sound with proofs of
+// possession of the keys, see Registry, and it is false on the basic scheme.
+func (s *Scheme) FastAggregateVerify(pks [][]byte, message, signature []byte) bool {
+	if !s.HasPop() {
+		return false
+	}
+	g1, g2, err := s.aggregatePublicKeys(pks)
+	if err != nil {
+		return false
+	}
+	hG1, hG2, err := s.hashes([][]byte{message}, s.id)
+	if err != nil {
+		return false
+	}
+	if s.minPk {
+		return s.check([]*bls381Utils.G1{g1}, nil, nil, hG2, signature)
+	}
+	return s.check(nil, []*bls381Utils.G2{g2}, hG1, nil, signature)
+}
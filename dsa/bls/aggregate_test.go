@@ -0,0 +1,215 @@
+package bls
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+)
+
+// signers returns n secret keys, their public keys, n distinct messages and
+// the signatures of the messages, and the signatures of one common message
+func signers(s *Scheme, n int) (pks, messages, sigs, sameSigs [][]byte, same []byte) {
+	same = []byte("block")
+	for i := 0; i < n; i++ {
+		ikm := make([]byte, 32)
+		binary.BigEndian.PutUint64(ikm, uint64(i))
+		sk, err := KeyGen(ikm, nil)
+		if err != nil {
+			panic(err)
+		}
+		m := []byte("message " + big.NewInt(int64(i)).String())
+		sig, err := s.Sign(sk, m)
+		if err != nil {
+			panic(err)
+		}
+		sameSig, err := s.Sign(sk, same)
+		if err != nil {
+			panic(err)
+		}
+		pks = append(pks, s.SkToPk(sk))
+		messages = append(messages, m)
+		sigs = append(sigs, sig)
+		sameSigs = append(sameSigs, sameSig)
+	}
+	return pks, messages, sigs, sameSigs, same
+}
+
+func TestScheme_AggregateVerify(t *testing.T) {
+	for _, s := range []*Scheme{MinPk, MinSig} {
+		pks, messages, sigs, _, _ := signers(s, 10)
+		agg, err := s.Aggregate(sigs)
+		if err != nil {
+			panic(err)
+		}
+		if !s.AggregateVerify(pks, messages, agg) {
+			t.Errorf("%s: aggregate signature not verified", s.ID())
+		}
+		if s.AggregateVerify(pks[1:], messages[1:], agg) {
+			t.Errorf("%s: aggregate signature verified without a signer", s.ID())
+		}
+		swapped := append([][]byte{messages[1], messages[0]}, messages[2:]...)
+		if s.AggregateVerify(pks, swapped, agg) {
+			t.Errorf("%s: aggregate signature verified with swapped messages", s.ID())
+		}
+		// a repeated message is rejected even with a valid aggregate
+		dup := append([][]byte{messages[0]}, messages[:len(messages)-1]...)
+		dupSigs := append([][]byte{sigs[0]}, sigs[:len(sigs)-1]...)
+		dupAgg, err := s.Aggregate(dupSigs)
+		if err != nil {
+			panic(err)
+		}
+		if s.AggregateVerify(append([][]byte{pks[0]}, pks[:len(pks)-1]...), dup, dupAgg) {
+			t.Errorf("%s: repeated messages accepted", s.ID())
+		}
+		if s.AggregateVerify(pks, messages[1:], agg) || s.AggregateVerify(nil, nil, agg) {
+			t.Errorf("%s: bad lengths accepted", s.ID())
+		}
+		single, err := s.Aggregate(sigs[:1])
+		if err != nil {
+			panic(err)
+		}
+		if !s.Verify(pks[0], messages[0], single) {
+			t.Errorf("%s: aggregate of one signature should be the signature", s.ID())
+		}
+	}
+}
+
+func TestScheme_FastAggregateVerify(t *testing.T) {
+	for _, s := range []*Scheme{MinPkPop, MinSigPop} {
+		pks, _, _, sigs, m := signers(s, 10)
+		agg, err := s.Aggregate(sigs)
+		if err != nil {
+			panic(err)
+		}
+		if !s.FastAggregateVerify(pks, m, agg) {
+			t.Errorf("%s: aggregate signature not verified", s.ID())
+		}
+		if s.FastAggregateVerify(pks[1:], m, agg) {
+			t.Errorf("%s: aggregate signature verified without a signer", s.ID())
+		}
+		if s.FastAggregateVerify(pks, []byte("other"), agg) {
+			t.Errorf("%s: aggregate signature of another message verified", s.ID())
+		}
+		aggPk, err := s.AggregatePublicKeys(pks)
+		if err != nil {
+			panic(err)
+		}
+		if !s.Verify(aggPk, m, agg) {
+			t.Errorf("%s: aggregate signature not verified under the aggregate key", s.ID())
+		}
+		if s.FastAggregateVerify(nil, m, agg) {
+			t.Errorf("%s: no public key accepted", s.ID())
+		}
+	}
+	// the basic scheme has no proofs of possession to aggregate keys safely
+	for _, s := range []*Scheme{MinPk, MinSig} {
+		pks, _, _, sigs, m := signers(s, 2)
+		agg, err := s.Aggregate(sigs)
+		if err != nil {
+			panic(err)
+		}
+		if s.FastAggregateVerify(pks, m, agg) {
+			t.Errorf("%s: fast aggregate verification in the basic scheme", s.ID())
+		}
+		if _, err := s.AggregatePublicKeys(pks); err == nil {
+			t.Errorf("%s: public keys aggregated in the basic scheme", s.ID())
+		}
+	}
+}
+
+func TestScheme_Aggregate(t *testing.T) {
+	for _, s := range []*Scheme{MinPk, MinSig} {
+		pks, _, sigs, _, _ := signers(s, 2)
+		if _, err := s.Aggregate(nil); err == nil {
+			t.Errorf("%s: empty aggregate should fail", s.ID())
+		}
+		bad := append([]byte{}, sigs[1]...)
+		bad[len(bad)-1] ^= 1
+		if _, err := s.Aggregate([][]byte{sigs[0], bad}); err == nil {
+			t.Errorf("%s: invalid signature should fail", s.ID())
+		}
+		// a public key is not a signature
+		if _, err := s.Aggregate(pks); err == nil {
+			t.Errorf("%s: public keys aggregated as signatures", s.ID())
+		}
+		if _, _, err := s.aggregatePublicKeys(sigs); err == nil {
+			t.Errorf("%s: signatures aggregated as public keys", s.ID())
+		}
+	}
+}
+
+const benchSigners = 1000
+
+func benchmarkSchemes(b *testing.B, run func(b *testing.B, s *Scheme, pks, messages, sigs, sameSigs [][]byte, same []byte)) {
+	benchmarkOn(b, []*Scheme{MinPk, MinSig}, run)
+}
+
+func benchmarkOn(b *testing.B, schemes []*Scheme, run func(b *testing.B, s *Scheme, pks, messages, sigs, sameSigs [][]byte, same []byte)) {
+	for _, s := range schemes {
+		pks, messages, sigs, sameSigs, same := signers(s, benchSigners)
+		name := "MinPk"
+		if !s.minPk {
+			name = "MinSig"
+		}
+		if s.HasPop() {
+			name += "Pop"
+		}
+		b.Run(name, func(b *testing.B) {
+			run(b, s, pks, messages, sigs, sameSigs, same)
+		})
+	}
+}
+
+func BenchmarkScheme_Aggregate1k(b *testing.B) {
+	benchmarkSchemes(b, func(b *testing.B, s *Scheme, _, _, sigs, _ [][]byte, _ []byte) {
+		for i := 0; i < b.N; i++ {
+			if _, err := s.Aggregate(sigs); err != nil {
+				panic(err)
+			}
+		}
+	})
+}
+
+func BenchmarkScheme_AggregateVerify1k(b *testing.B) {
+	benchmarkSchemes(b, func(b *testing.B, s *Scheme, pks, messages, sigs, _ [][]byte, _ []byte) {
+		agg, err := s.Aggregate(sigs)
+		if err != nil {
+			panic(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if !s.AggregateVerify(pks, messages, agg) {
+				b.Fatal("aggregate signature not verified")
+			}
+		}
+	})
+}
+
+func BenchmarkScheme_FastAggregateVerify1k(b *testing.B) {
+	benchmarkOn(b, []*Scheme{MinPkPop, MinSigPop}, func(b *testing.B, s *Scheme, pks, _, _, sameSigs [][]byte, same []byte) {
+		agg, err := s.Aggregate(sameSigs)
+		if err != nil {
+			panic(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if !s.FastAggregateVerify(pks, same, agg) {
+				b.Fatal("aggregate signature not verified")
+			}
+		}
+	})
+}
+
+// BenchmarkScheme_Verify1k verifies the 1k signatures one by one, the
+// baseline of AggregateVerify
+func BenchmarkScheme_Verify1k(b *testing.B) {
+	benchmarkSchemes(b, func(b *testing.B, s *Scheme, pks, messages, sigs, _ [][]byte, _ []byte) {
+		for i := 0; i < b.N; i++ {
+			for j := range sigs {
+				if !s.Verify(pks[j], messages[j], sigs[j]) {
+					b.Fatal("signature not verified")
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package bls
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"scrypto/ecc"
+	"scrypto/ecc/bls381"
+	"scrypto/ecc/bls381Utils"
+)
+
+var errBatchSize = errors.New("pks, messages and signatures size not match")
+
+// batch holds the entries of BatchVerify scaled by random exponents r_i:
+// signature i is valid iff e(g1[i], g2[i]) = e(g, [r_i]signature_i), with
+// g1[i] = [r_i]pk_i and g2[i] = H(m_i) for MinPk, g1[i] = [r_i]H(m_i) and
+// g2[i] = pk_i for MinSig
+type batch struct {
+	s    *Scheme
+	g1   []*bls381Utils.G1
+	g2   []*bls381Utils.G2
+	sig1 []*bls381Utils.G1
+	sig2 []*bls381Utils.G2
+}
+
+// set decodes and scales entry i, it reports false if the public key or the
+// signature is not valid
+func (b *batch) set(i int, r uint64, pk, message, signature []byte) bool {
+	curve := bls381Utils.BLSCurve
+	if b.s.minPk {
+		p, ok := decodeG1(pk)
+		if !ok || p.Z.IsZero() {
+			return false
+		}
+		sig, ok := decodeG2(signature)
+		if !ok {
+			return false
+		}
+		h, err := bls381.HashToG2(message, []byte(b.s.id))
+		if err != nil {
+			return false
+		}
+		b.g1[i] = p.ScalarMulUint64(curve, p, r)
+		b.g2[i] = h
+		b.sig2[i] = sig.ScalarMulUint64(curve, sig, r)
+		return true
+	}
+	p, ok := decodeG2(pk)
+	if !ok || p.Z.IsZero() {
+		return false
+	}
+	sig, ok := decodeG1(signature)
+	if !ok {
+		return false
+	}
+	h, err := bls381.HashToG1(message, []byte(b.s.id))
+	if err != nil {
+		return false
+	}
+	b.g1[i] = h.ScalarMulUint64(curve, h, r)
+	b.g2[i] = p
+	b.sig1[i] = sig.ScalarMulUint64(curve, sig, r)
+	return true
+}
+
+// check reports whether the entries of indices are all valid with high
+// probability, in a single multi-pairing:
+// prod e(g1[i], g2[i]) * e(-g, sum [r_i]signature_i) = 1
+func (b *batch) check(indices []int) bool {
+	curve := bls381Utils.BLSCurve
+	g1 := make([]*bls381Utils.G1, 0, len(indices)+1)
+	g2 := make([]*bls381Utils.G2, 0, len(indices)+1)
+	for _, i := range indices {
+		g1 = append(g1, b.g1[i])
+		g2 = append(g2, b.g2[i])
+	}
+	if b.s.minPk {
+		sum := new(bls381Utils.G2).Set(b.sig2[indices[0]])
+		for _, i := range indices[1:] {
+			sum.Add(curve, b.sig2[i])
+		}
+		return bls381Utils.PairingCheck(
+			append(g1, bls381Utils.G1Neg(&bls381Utils.BaseG1)),
+			append(g2, sum))
+	}
+	sum := new(bls381Utils.G1).Set(b.sig1[indices[0]])
+	for _, i := range indices[1:] {
+		sum.Add(curve, b.sig1[i])
+	}
+	return bls381Utils.PairingCheck(
+		append(g1, sum),
+		append(g2, bls381Utils.G2Neg(&bls381Utils.BaseG2)))
+}
+
+// bisect appends to invalid the entries of indices which do not verify,
+// halving the set each time its check fails
+func (b *batch) bisect(indices []int, invalid []int) []int {
+	if b.check(indices) {
+		return invalid
+	}
+	if len(indices) == 1 {
+		return append(invalid, indices[0])
+	}
+	half := len(indices) / 2
+	invalid = b.bisect(indices[:half], invalid)
+	return b.bisect(indices[half:], invalid)
+}
+
+// BatchVerify verifies that signatures[i] is a signature of messages[i] under
+// pks[i] for every i, and returns the sorted indices of the invalid ones, none
+// if all of them verify. The signatures are scaled by random 64 bits exponents
+// and checked in one multi-pairing, a failing batch is bisected to find the
+// invalid signatures. Decoding, hashing and scaling run in parallel.
+func (s *Scheme) BatchVerify(pks, messages, signatures [][]byte) ([]int, error) {
+	n := len(signatures)
+	if len(pks) != n || len(messages) != n {
+		return nil, errBatchSize
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	exponents := make([]byte, 8*n)
+	if _, err := rand.Read(exponents); err != nil {
+		return nil, err
+	}
+	b := &batch{
+		s:  s,
+		g1: make([]*bls381Utils.G1, n),
+		g2: make([]*bls381Utils.G2, n),
+	}
+	if s.minPk {
+		b.sig2 = make([]*bls381Utils.G2, n)
+	} else {
+		b.sig1 = make([]*bls381Utils.G1, n)
+	}
+	valid := make([]bool, n)
+	ecc.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			r := binary.BigEndian.Uint64(exponents[8*i:])
+			if r == 0 {
+				r = 1
+			}
+			valid[i] = b.set(i, r, pks[i], messages[i], signatures[i])
+		}
+	})
+	var invalid, indices []int
+	for i := range valid {
+		if valid[i] {
+			indices = append(indices, i)
+		} else {
+			invalid = append(invalid, i)
+		}
+	}
+	if len(indices) > 0 {
+		invalid = b.bisect(indices, invalid)
+	}
+	sort.Ints(invalid)
+	return invalid, nil
+}
@@ -0,0 +1,88 @@
+package bls
+
+import (
+	"reflect"
+	"testing"
+
+	"scrypto/ecc/bls381"
+	"scrypto/ecc/bls381Utils"
+)
+
+// shift returns the signatures a + d and b - d, invalid ones whose sum is a + b
+func shift(s *Scheme, a, b, d []byte) ([]byte, []byte) {
+	if s.minPk {
+		pa, _ := decodeG2(a)
+		pb, _ := decodeG2(b)
+		pd, _ := decodeG2(d)
+		var ra, rb bls381.G2Affine
+		return bls381Utils.G2Add(pa, pd).ToAffineFromJac(&ra).Bytes(),
+			bls381Utils.G2Add(pb, bls381Utils.G2Neg(pd)).ToAffineFromJac(&rb).Bytes()
+	}
+	pa, _ := decodeG1(a)
+	pb, _ := decodeG1(b)
+	pd, _ := decodeG1(d)
+	var ra, rb bls381.G1Affine
+	return bls381Utils.G1Add(pa, pd).ToAffineFromJac(&ra).Bytes(),
+		bls381Utils.G1Add(pb, bls381Utils.G1Neg(pd)).ToAffineFromJac(&rb).Bytes()
+}
+
+func TestScheme_BatchVerify(t *testing.T) {
+	for _, s := range []*Scheme{MinPk, MinSig} {
+		pks, messages, sigs, _, _ := signers(s, 16)
+		invalid, err := s.BatchVerify(pks, messages, sigs)
+		if err != nil {
+			panic(err)
+		}
+		if len(invalid) != 0 {
+			t.Errorf("%s: valid batch rejected: %v", s.ID(), invalid)
+		}
+
+		bad := append([][]byte{}, sigs...)
+		// a signature of another message, a malformed signature and two
+		// signatures whose errors cancel in the plain sum
+		bad[3] = sigs[4]
+		bad[11] = append([]byte{}, sigs[11]...)
+		bad[11][len(bad[11])-1] ^= 1
+		bad[6], bad[7] = shift(s, sigs[6], sigs[7], sigs[0])
+		agg, err := s.Aggregate([][]byte{bad[6], bad[7]})
+		if err != nil {
+			panic(err)
+		}
+		if !s.AggregateVerify(pks[6:8], messages[6:8], agg) {
+			t.Fatalf("%s: shifted signatures should aggregate to a valid signature", s.ID())
+		}
+		invalid, err = s.BatchVerify(pks, messages, bad)
+		if err != nil {
+			panic(err)
+		}
+		if !reflect.DeepEqual(invalid, []int{3, 6, 7, 11}) {
+			t.Errorf("%s: invalid signatures %v, expected [3 6 7 11]", s.ID(), invalid)
+		}
+
+		// a public key of the wrong group
+		invalid, err = s.BatchVerify([][]byte{pks[0], sigs[1]}, messages[:2], sigs[:2])
+		if err != nil {
+			panic(err)
+		}
+		if !reflect.DeepEqual(invalid, []int{1}) {
+			t.Errorf("%s: invalid public key not found: %v", s.ID(), invalid)
+		}
+		if _, err := s.BatchVerify(pks, messages[1:], sigs); err == nil {
+			t.Errorf("%s: bad lengths accepted", s.ID())
+		}
+	}
+}
+
+func BenchmarkScheme_BatchVerify1k(b *testing.B) {
+	benchmarkSchemes(b, func(b *testing.B, s *Scheme, pks, messages, sigs, _ [][]byte, _ []byte) {
+		for i := 0; i < b.N; i++ {
+			invalid, err := s.BatchVerify(pks, messages, sigs)
+			if err != nil {
+				panic(err)
+			}
+			if len(invalid) != 0 {
+				b.Fatal("valid batch rejected")
+			}
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package bls
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"scrypto/ecc/bls381"
+	"scrypto/ecc/bls381Utils"
+)
+
+// Blind signatures: the user sends B = [r]H(m) for a random r, the signer
+// answers [sk]B and the user gets [1/r][sk]B = [sk]H(m), the signature of m.
+// B is a uniform point whatever m is, so the signer learns nothing about m
+// and cannot link its view (B, [sk]B) to the signature.
+
+var (
+	errBlindRequest  = errors.New("invalid blind signature request")
+	errBlindResponse = errors.New("invalid blind signature response")
+)
+
+// BlindRequest is the blinded hash of a message, sent to the signer
+type BlindRequest struct {
+	Blinded []byte
+}
+
+// BlindResponse is the signature of a blinded hash, sent back to the user
+type BlindResponse struct {
+	Signature []byte
+}
+
+// Blinding is the secret state of the user between Blind and Unblind
+type Blinding struct {
+	scheme  *Scheme
+	message []byte
+	factor  *bls381Utils.Scalar
+}
+
+// Bytes returns the encoded point of r
+func (r *BlindRequest) Bytes() []byte {
+	return append([]byte{}, r.Blinded...)
+}
+
+// Bytes returns the encoded point of r
+func (r *BlindResponse) Bytes() []byte {
+	return append([]byte{}, r.Signature...)
+}
+
+// BlindRequestFromBytes decodes a request of s, a point of the signature
+// group other than the identity
+func (s *Scheme) BlindRequestFromBytes(data []byte) (*BlindRequest, error) {
+	if !s.isSignaturePoint(data) {
+		return nil, errBlindRequest
+	}
+	return &BlindRequest{Blinded: append([]byte{}, data...)}, nil
+}
+
+// BlindResponseFromBytes decodes a response of s, a point of the signature
+// group other than the identity
+func (s *Scheme) BlindResponseFromBytes(data []byte) (*BlindResponse, error) {
+	if !s.isSignaturePoint(data) {
+		return nil, errBlindResponse
+	}
+	return &BlindResponse{Signature: append([]byte{}, data...)}, nil
+}
+
+// isSignaturePoint reports whether data encodes a point of the signature
+// group other than the identity
+func (s *Scheme) isSignaturePoint(data []byte) bool {
+	if s.minPk {
+		p, ok := decodeG2(data)
+		return ok && !p.Z.IsZero()
+	}
+	p, ok := decodeG1(data)
+	return ok && !p.Z.IsZero()
+}
+
+// mulSignaturePoint returns [k]data for an encoded point of the signature
+// group other than the identity
+func (s *Scheme) mulSignaturePoint(data []byte, k *bls381Utils.Scalar) ([]byte, bool) {
+	if s.minPk {
+		p, ok := decodeG2(data)
+		if !ok || p.Z.IsZero() {
+			return nil, false
+		}
+		var res bls381.G2Affine
+		return bls381Utils.G2Mul(p, k).ToAffineFromJac(&res).Bytes(), true
+	}
+	p, ok := decodeG1(data)
+	if !ok || p.Z.IsZero() {
+		return nil, false
+	}
+	var res bls381.G1Affine
+	return bls381Utils.G1Mul(p, k).ToAffineFromJac(&res).Bytes(), true
+}
+
+// Blind returns the request to sign message blindly and the state to unblind
+// the response, the blinding factor is drawn from random, crypto/rand if
+// random is nil
+func (s *Scheme) Blind(message []byte, random io.Reader) (*Blinding, *BlindRequest, error) {
+	r, err := bls381Utils.RandomNonZeroScalar(random)
+	if err != nil {
+		return nil, nil, err
+	}
+	var h []byte
+	hG1, hG2, err := s.hashes([][]byte{message}, s.id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.minPk {
+		var a bls381.G2Affine
+		h = bls381Utils.G2Mul(hG2[0], r).ToAffineFromJac(&a).Bytes()
+	} else {
+		var a bls381.G1Affine
+		h = bls381Utils.G1Mul(hG1[0], r).ToAffineFromJac(&a).Bytes()
+	}
+	b := &Blinding{scheme: s, message: append([]byte{}, message...), factor: r}
+	return b, &BlindRequest{Blinded: h}, nil
+}
+
+// BlindSign signs the blinded hash of request with sk, without learning the
+// message
+func (s *Scheme) BlindSign(sk *big.Int, request *BlindRequest) (*BlindResponse, error) {
+	sig, ok := s.mulSignaturePoint(request.Blinded, bls381Utils.NewScalar(sk))
+	if !ok {
+		return nil, errBlindRequest
+	}
+	return &BlindResponse{Signature: sig}, nil
+}
+
+// BlindVerify reports whether response is the signature of request under pk
+func (s *Scheme) BlindVerify(pk []byte, request *BlindRequest, response *BlindResponse) bool {
+	pkG1, pkG2, ok := s.publicKeys([][]byte{pk})
+	if !ok {
+		return false
+	}
+	if s.minPk {
+		h, ok := decodeG2(request.Blinded)
+		if !ok || h.Z.IsZero() {
+			return false
+		}
+		return s.check(pkG1, nil, nil, []*bls381Utils.G2{h}, response.Signature)
+	}
+	h, ok := decodeG1(request.Blinded)
+	if !ok || h.Z.IsZero() {
+		return false
+	}
+	return s.check(nil, pkG2, []*bls381Utils.G1{h}, nil, response.Signature)
+}
+
+// Unblind returns the signature of the message of b from the response of the
+// signer, it fails if the signature does not verify under pk
+func (b *Blinding) Unblind(pk []byte, response *BlindResponse) ([]byte, error) {
+	var inv bls381Utils.Scalar
+	inv.Inverse(b.factor)
+	sig, ok := b.scheme.mulSignaturePoint(response.Signature, &inv)
+	if !ok || !b.scheme.Verify(pk, b.message, sig) {
+		return nil, errBlindResponse
+	}
+	return sig, nil
+}
@@ -0,0 +1,139 @@
+package bls
+
+import (
+	"bytes"
+	"testing"
+
+	"scrypto/ecc/bls381"
+)
+
+// hashPoint returns the encoded hash of message in the signature group
+func (s *Scheme) hashPoint(message []byte) ([]byte, error) {
+	hG1, hG2, err := s.hashes([][]byte{message}, s.id)
+	if err != nil {
+		return nil, err
+	}
+	if s.minPk {
+		var a bls381.G2Affine
+		return hG2[0].ToAffineFromJac(&a).Bytes(), nil
+	}
+	var a bls381.G1Affine
+	return hG1[0].ToAffineFromJac(&a).Bytes(), nil
+}
+
+func TestScheme_BlindSign(t *testing.T) {
+	for _, s := range []*Scheme{MinPk, MinSig} {
+		sk, err := KeyGen([]byte("the key material of the signer 32"), nil)
+		if err != nil {
+			panic(err)
+		}
+		pk := s.SkToPk(sk)
+		m := []byte("coin 42")
+		b, req, err := s.Blind(m, nil)
+		if err != nil {
+			panic(err)
+		}
+		// the messages go through their encodings
+		req, err = s.BlindRequestFromBytes(req.Bytes())
+		if err != nil {
+			panic(err)
+		}
+		resp, err := s.BlindSign(sk, req)
+		if err != nil {
+			panic(err)
+		}
+		resp, err = s.BlindResponseFromBytes(resp.Bytes())
+		if err != nil {
+			panic(err)
+		}
+		sig, err := b.Unblind(pk, resp)
+		if err != nil {
+			panic(err)
+		}
+		if !s.Verify(pk, m, sig) {
+			t.Errorf("%s: unblinded signature not verified", s.ID())
+		}
+		// the unblinded signature is the deterministic signature of m
+		expected, err := s.Sign(sk, m)
+		if err != nil {
+			panic(err)
+		}
+		if !bytes.Equal(sig, expected) {
+			t.Errorf("%s: unblinded signature not match Sign", s.ID())
+		}
+
+		other, err := KeyGen([]byte("the key material of another signer"), nil)
+		if err != nil {
+			panic(err)
+		}
+		wrong, err := s.BlindSign(other, req)
+		if err != nil {
+			panic(err)
+		}
+		if _, err := b.Unblind(pk, wrong); err == nil {
+			t.Errorf("%s: response of another key unblinded", s.ID())
+		}
+		if _, err := s.BlindRequestFromBytes(pk); err == nil {
+			t.Errorf("%s: public key decoded as a request", s.ID())
+		}
+		if _, err := s.BlindResponseFromBytes(req.Bytes()[1:]); err == nil {
+			t.Errorf("%s: short response decoded", s.ID())
+		}
+	}
+}
+
+// TestScheme_BlindUnlinkable checks that the view of the signer, a request B
+// and its response S, is consistent with the signature of any message: the
+// signature group is cyclic so B = [r']H(m') for some r' whatever m' is, and
+// S = [sk]B = [r'][sk]H(m') is then the blinded signature of m'. Requests are
+// fresh random points, even for the same message.
+func TestScheme_BlindUnlinkable(t *testing.T) {
+	for _, s := range []*Scheme{MinPk, MinSig} {
+		sk, err := KeyGen([]byte("the key material of the signer 32"), nil)
+		if err != nil {
+			panic(err)
+		}
+		pk := s.SkToPk(sk)
+		messages := [][]byte{[]byte("coin 1"), []byte("coin 2"), []byte("coin 1")}
+		var requests, responses, sigs [][]byte
+		for _, m := range messages {
+			b, req, err := s.Blind(m, nil)
+			if err != nil {
+				panic(err)
+			}
+			resp, err := s.BlindSign(sk, req)
+			if err != nil {
+				panic(err)
+			}
+			sig, err := b.Unblind(pk, resp)
+			if err != nil {
+				panic(err)
+			}
+			requests = append(requests, req.Bytes())
+			responses = append(responses, resp.Bytes())
+			sigs = append(sigs, sig)
+		}
+		if bytes.Equal(requests[0], requests[2]) || bytes.Equal(responses[0], responses[2]) {
+			t.Errorf("%s: requests of the same message linked", s.ID())
+		}
+		for i := range requests {
+			for j := range sigs {
+				// the view of session i never shows the signature or the hash of j
+				if bytes.Equal(requests[i], sigs[j]) || bytes.Equal(responses[i], sigs[j]) {
+					t.Errorf("%s: view %d shows signature %d", s.ID(), i, j)
+				}
+				h, err := s.hashPoint(messages[j])
+				if err != nil {
+					panic(err)
+				}
+				if bytes.Equal(requests[i], h) {
+					t.Errorf("%s: view %d shows the hash of message %d", s.ID(), i, j)
+				}
+			}
+			// S = [sk]B holds for every view, which is all the signer can check
+			if !s.BlindVerify(pk, &BlindRequest{requests[i]}, &BlindResponse{responses[i]}) {
+				t.Errorf("%s: view %d not consistent", s.ID(), i)
+			}
+		}
+	}
+}
@@ -1,10 +1,9 @@
 package bls
 
 import (
-	"crypto/sha256"
 	"math/big"
+	"scrypto/ecc/bls381"
 	"scrypto/ecc/bls381Utils"
-	"scrypto/sutils"
 )
 
 func GenerateKeyPair() (k *big.Int, K *bls381Utils.G2, err error) {
@@ -12,14 +11,9 @@ func GenerateKeyPair() (k *big.Int, K *bls381Utils.G2, err error) {
 	return bls381Utils.RandomG2()
 }
 
+// G1Hash hashes message to G1 as the MinSig ciphersuite does
 func G1Hash(message []byte) (h *bls381Utils.G1, err error) {
-	mhash, err := sutils.GetHashValue(message, sha256.New)
-	if err != nil {
-		return nil, err
-	}
-	r := new(big.Int).SetBytes(mhash)
-	h = bls381Utils.G1ScalarBaseMult(r)
-	return h, nil
+	return bls381.HashToG1(message, []byte(MinSig.id))
 }
 
 func Sign(k *big.Int, message []byte) (delta *bls381Utils.G1, err error) {
@@ -38,8 +32,8 @@ func Verify(K *bls381Utils.G2, message []byte, delta *bls381Utils.G1) bool {
 	if err != nil {
 		return false
 	}
-	// e(\delta, g_2) = e(h,K)
-	p1 := bls381Utils.BLSPair(delta, &bls381Utils.BaseG2)
-	p2 := bls381Utils.BLSPair(h, K)
-	return p1.Equal(p2)
+	// e(\delta, g_2) = e(h,K), as e(\delta, -g_2) * e(h, K) = 1
+	return bls381Utils.PairingCheck(
+		[]*bls381Utils.G1{delta, h},
+		[]*bls381Utils.G2{bls381Utils.G2Neg(&bls381Utils.BaseG2), K})
 }
@@ -0,0 +1,298 @@
+package bls
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"scrypto/ecc/bls381"
+	"scrypto/ecc/bls381Utils"
+	"scrypto/sutils"
+	"scrypto/sutils/kdf"
+)
+
+// The ciphersuites of the basic and proof of possession schemes of the IETF
+// BLS signature draft (draft-irtf-cfrg-bls-signature-05) on BLS12-381: MinPk
+// puts public keys in G1 and signatures in G2, MinSig does the opposite. Keys and signatures are
+// the compressed encodings of bls381, secret keys are integers in [1, r).
+
+// SecretKeySize is the size of an encoded secret key
+const SecretKeySize = 32
+
+// keyGenSalt is the initial salt of KeyGen
+const keyGenSalt = "BLS-SIG-KEYGEN-SALT-"
+
+var (
+	errShortIKM      = errors.New("ikm should be at least 32 bytes")
+	errInvalidSecret = errors.New("secret key should be in [1, r)")
+)
+
+// Scheme is a ciphersuite of the basic or of the proof of possession scheme
+type Scheme struct {
+	// id is the ciphersuite ID, it is the domain separation tag of the hash
+	// to the signature group
+	id string
+	// popID is the domain separation tag of proofs of possession, it is empty
+	// for the basic scheme
+	popID string
+	minPk bool
+}
+
+var (
+	// MinPk is BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_, 48 bytes public
+	// keys and 96 bytes signatures
+	MinPk = &Scheme{id: "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_", minPk: true}
+	// MinSig is BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_, 96 bytes public
+	// keys and 48 bytes signatures
+	MinSig = &Scheme{id: "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_"}
+	// MinPkPop is BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_, MinPk with
+	// proofs of possession
+	MinPkPop = &Scheme{
+		id:    "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_",
+		popID: "BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_",
+		minPk: true,
+	}
+	// MinSigPop is BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_, MinSig with
+	// proofs of possession
+	MinSigPop = &Scheme{
+		id:    "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_",
+		popID: "BLS_POP_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_",
+	}
+)
+
+// ID returns the ciphersuite ID
+func (s *Scheme) ID() string {
+	return s.id
+}
+
+// PublicKeySize returns the size of a public key
+func (s *Scheme) PublicKeySize() int {
+	if s.minPk {
+		return bls381.SizeOfG1Compressed
+	}
+	return bls381.SizeOfG2Compressed
+}
+
+// SignatureSize returns the size of a signature
+func (s *Scheme) SignatureSize() int {
+	if s.minPk {
+		return bls381.SizeOfG2Compressed
+	}
+	return bls381.SizeOfG1Compressed
+}
+
+// KeyGen derives a secret key from the secret ikm of at least 32 bytes and
+// the optional keyInfo
+func KeyGen(ikm, keyInfo []byte) (sk *big.Int, err error) {
+	if len(ikm) < 32 {
+		return nil, errShortIKM
+	}
+	salt := []byte(keyGenSalt)
+	for {
+		sk, err = hkdfModR(ikm, salt, keyInfo)
+		if err != nil {
+			return nil, err
+		}
+		if sk.Sign() != 0 {
+			return sk, nil
+		}
+		h := sha256.Sum256(salt)
+		salt = h[:]
+	}
+}
+
+// hkdfModR returns OS2IP(HKDF(salt, ikm || 0, keyInfo || I2OSP(L, 2), L)) mod r
+// with L = 48, an iteration of KeyGen
+func hkdfModR(ikm, salt, keyInfo []byte) (*big.Int, error) {
+	const l = 48
+	prk, err := kdf.Extract(sutils.SHA256, append(append([]byte{}, ikm...), 0), salt)
+	if err != nil {
+		return nil, err
+	}
+	info := make([]byte, len(keyInfo)+2)
+	copy(info, keyInfo)
+	binary.BigEndian.PutUint16(info[len(keyInfo):], l)
+	okm, err := kdf.Expand(sutils.SHA256, prk, info, l)
+	if err != nil {
+		return nil, err
+	}
+	sk := new(big.Int).SetBytes(okm)
+	return sk.Mod(sk, bls381Utils.Order), nil
+}
+
+// SecretKeyToBytes encodes sk in SecretKeySize big-endian bytes
+func SecretKeyToBytes(sk *big.Int) []byte {
+	return bls381Utils.NewScalar(sk).Bytes()
+}
+
+// SecretKeyFromBytes decodes SecretKeySize bytes, it rejects 0 and values
+// not below r
+func SecretKeyFromBytes(data []byte) (*big.Int, error) {
+	s, err := new(bls381Utils.Scalar).SetBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	if s.IsZero() {
+		return nil, errInvalidSecret
+	}
+	return s.BigInt(), nil
+}
+
+// SkToPk returns the public key of sk
+func (s *Scheme) SkToPk(sk *big.Int) []byte {
+	k := bls381Utils.NewScalar(sk)
+	if s.minPk {
+		var pk bls381.G1Affine
+		return bls381Utils.G1MulBase(k).ToAffineFromJac(&pk).Bytes()
+	}
+	var pk bls381.G2Affine
+	return bls381Utils.G2MulBase(k).ToAffineFromJac(&pk).Bytes()
+}
+
+// decodeG1 decodes a point of G1, the encoding checks the subgroup
+func decodeG1(data []byte) (*bls381Utils.G1, bool) {
+	var a bls381.G1Affine
+	if err := a.SetBytes(data); err != nil {
+		return nil, false
+	}
+	return a.ToJacobian(new(bls381Utils.G1)), true
+}
+
+// decodeG2 decodes a point of G2, the encoding checks the subgroup
+func decodeG2(data []byte) (*bls381Utils.G2, bool) {
+	var a bls381.G2Affine
+	if err := a.SetBytes(data); err != nil {
+		return nil, false
+	}
+	return a.ToJacobian(new(bls381Utils.G2)), true
+}
+
+// KeyValidate reports whether pk encodes a point of the public key group
+// other than the identity
+func (s *Scheme) KeyValidate(pk []byte) bool {
+	if s.minPk {
+		p, ok := decodeG1(pk)
+		return ok && !p.Z.IsZero()
+	}
+	p, ok := decodeG2(pk)
+	return ok && !p.Z.IsZero()
+}
+
+// CoreSign returns the signature of message with sk, [sk]H(message)
+func (s *Scheme) CoreSign(sk *big.Int, message []byte) ([]byte, error) {
+	return s.sign(sk, message, s.id)
+}
+
+// sign returns [sk]H(message), H hashes to the signature group with dst
+func (s *Scheme) sign(sk *big.Int, message []byte, dst string) ([]byte, error) {
+	k := bls381Utils.NewScalar(sk)
+	if s.minPk {
+		h, err := bls381.HashToG2(message, []byte(dst))
+		if err != nil {
+			return nil, err
+		}
+		var sig bls381.G2Affine
+		return bls381Utils.G2Mul(h, k).ToAffineFromJac(&sig).Bytes(), nil
+	}
+	h, err := bls381.HashToG1(message, []byte(dst))
+	if err != nil {
+		return nil, err
+	}
+	var sig bls381.G1Affine
+	return bls381Utils.G1Mul(h, k).ToAffineFromJac(&sig).Bytes(), nil
+}
+
+// CoreVerify reports whether signature is a signature of message under pk:
+// e(pk, H(message)) = e(g, signature) up to the order of the groups
+func (s *Scheme) CoreVerify(pk, message, signature []byte) bool {
+	return s.verify([][]byte{pk}, [][]byte{message}, signature, s.id)
+}
+
+// publicKeys decodes pks into the public key group, G1 for MinPk and G2 for
+// MinSig, it fails if a key does not pass KeyValidate
+func (s *Scheme) publicKeys(pks [][]byte) (g1 []*bls381Utils.G1, g2 []*bls381Utils.G2, ok bool) {
+	for _, pk := range pks {
+		if s.minPk {
+			p, ok := decodeG1(pk)
+			if !ok || p.Z.IsZero() {
+				return nil, nil, false
+			}
+			g1 = append(g1, p)
+		} else {
+			p, ok := decodeG2(pk)
+			if !ok || p.Z.IsZero() {
+				return nil, nil, false
+			}
+			g2 = append(g2, p)
+		}
+	}
+	return g1, g2, true
+}
+
+// hashes hashes messages into the signature group with dst, G2 for MinPk and
+// G1 for MinSig
+func (s *Scheme) hashes(messages [][]byte, dst string) (g1 []*bls381Utils.G1, g2 []*bls381Utils.G2, err error) {
+	for _, m := range messages {
+		if s.minPk {
+			h, err := bls381.HashToG2(m, []byte(dst))
+			if err != nil {
+				return nil, nil, err
+			}
+			g2 = append(g2, h)
+		} else {
+			h, err := bls381.HashToG1(m, []byte(dst))
+			if err != nil {
+				return nil, nil, err
+			}
+			g1 = append(g1, h)
+		}
+	}
+	return g1, g2, nil
+}
+
+// check reports whether signature is the sum of the signatures of the hashes
+// under the public keys of the same index: prod e(pk_i, h_i) * e(-g, signature) = 1
+// in a single multi-pairing
+func (s *Scheme) check(pkG1 []*bls381Utils.G1, pkG2 []*bls381Utils.G2, hG1 []*bls381Utils.G1, hG2 []*bls381Utils.G2, signature []byte) bool {
+	if s.minPk {
+		sig, ok := decodeG2(signature)
+		if !ok {
+			return false
+		}
+		return bls381Utils.PairingCheck(
+			append(pkG1, bls381Utils.G1Neg(&bls381Utils.BaseG1)),
+			append(hG2, sig))
+	}
+	sig, ok := decodeG1(signature)
+	if !ok {
+		return false
+	}
+	return bls381Utils.PairingCheck(
+		append(hG1, sig),
+		append(pkG2, bls381Utils.G2Neg(&bls381Utils.BaseG2)))
+}
+
+// verify reports whether signature aggregates the signatures of messages[i]
+// under pks[i], the messages are hashed with dst
+func (s *Scheme) verify(pks, messages [][]byte, signature []byte, dst string) bool {
+	pkG1, pkG2, ok := s.publicKeys(pks)
+	if !ok {
+		return false
+	}
+	hG1, hG2, err := s.hashes(messages, dst)
+	if err != nil {
+		return false
+	}
+	return s.check(pkG1, pkG2, hG1, hG2, signature)
+}
+
+// Sign is CoreSign, both schemes sign messages as they are
+func (s *Scheme) Sign(sk *big.Int, message []byte) ([]byte, error) {
+	return s.CoreSign(sk, message)
+}
+
+// Verify is CoreVerify
+func (s *Scheme) Verify(pk, message, signature []byte) bool {
+	return s.CoreVerify(pk, message, signature)
+}
@@ -0,0 +1,142 @@
+package bls
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+)
+
+// testdata holds lines "message ikm signature" of the test vectors of the
+// draft (sig_g1_basic and sig_g2_basic), the key is KeyGen(ikm, "")
+func runVectors(t *testing.T, s *Scheme, file string) {
+	f, err := os.Open(file)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<16)
+	n := 0
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			panic("bad test vector line")
+		}
+		msg, err := hex.DecodeString(fields[0])
+		if err != nil {
+			panic(err)
+		}
+		ikm, err := hex.DecodeString(fields[1])
+		if err != nil {
+			panic(err)
+		}
+		sk, err := KeyGen(ikm, nil)
+		if err != nil {
+			panic(err)
+		}
+		sig, err := s.Sign(sk, msg)
+		if err != nil {
+			panic(err)
+		}
+		if hex.EncodeToString(sig) != fields[2] {
+			t.Errorf("%s: signature %d not match", s.ID(), n)
+		}
+		if !s.Verify(s.SkToPk(sk), msg, sig) {
+			t.Errorf("%s: signature %d not verified", s.ID(), n)
+		}
+		n++
+	}
+	if n == 0 {
+		t.Error("no test vector in", file)
+	}
+}
+
+func TestMinPk_Vectors(t *testing.T) {
+	runVectors(t, MinPk, "testdata/sig_g2_basic.txt")
+}
+
+func TestMinSig_Vectors(t *testing.T) {
+	runVectors(t, MinSig, "testdata/sig_g1_basic.txt")
+}
+
+func TestScheme_Verify(t *testing.T) {
+	for _, s := range []*Scheme{MinPk, MinSig} {
+		sk, err := KeyGen(bytes.Repeat([]byte{1}, 32), []byte("info"))
+		if err != nil {
+			panic(err)
+		}
+		pk := s.SkToPk(sk)
+		if len(pk) != s.PublicKeySize() || !s.KeyValidate(pk) {
+			t.Errorf("%s: invalid public key", s.ID())
+		}
+		m := []byte("hello")
+		sig, err := s.Sign(sk, m)
+		if err != nil {
+			panic(err)
+		}
+		if len(sig) != s.SignatureSize() || !s.Verify(pk, m, sig) {
+			t.Errorf("%s: signature not verified", s.ID())
+		}
+		if s.Verify(pk, []byte("hallo"), sig) {
+			t.Errorf("%s: signature of another message verified", s.ID())
+		}
+		other, err := KeyGen(bytes.Repeat([]byte{2}, 32), nil)
+		if err != nil {
+			panic(err)
+		}
+		if s.Verify(s.SkToPk(other), m, sig) {
+			t.Errorf("%s: signature verified under another key", s.ID())
+		}
+		sig[len(sig)-1] ^= 1
+		if s.Verify(pk, m, sig) {
+			t.Errorf("%s: modified signature verified", s.ID())
+		}
+		// the identity is not a valid public key
+		identity := make([]byte, s.PublicKeySize())
+		identity[0] = 0xc0
+		if s.KeyValidate(identity) {
+			t.Errorf("%s: identity public key accepted", s.ID())
+		}
+	}
+	// keys and signatures of one ciphersuite do not fit the other
+	sk, _ := KeyGen(bytes.Repeat([]byte{3}, 32), nil)
+	sig, _ := MinPk.Sign(sk, []byte("hello"))
+	if MinSig.Verify(MinPk.SkToPk(sk), []byte("hello"), sig) {
+		t.Error("MinPk signature verified by MinSig")
+	}
+}
+
+func TestKeyGen(t *testing.T) {
+	ikm := bytes.Repeat([]byte{7}, 32)
+	if _, err := KeyGen(ikm[:31], nil); err == nil {
+		t.Error("short ikm should fail")
+	}
+	sk1, err := KeyGen(ikm, nil)
+	if err != nil {
+		panic(err)
+	}
+	sk2, err := KeyGen(ikm, nil)
+	if err != nil {
+		panic(err)
+	}
+	sk3, err := KeyGen(ikm, []byte("info"))
+	if err != nil {
+		panic(err)
+	}
+	if sk1.Cmp(sk2) != 0 || sk1.Cmp(sk3) == 0 {
+		t.Error("KeyGen should depend on ikm and keyInfo only")
+	}
+	sk, err := SecretKeyFromBytes(SecretKeyToBytes(sk1))
+	if err != nil {
+		panic(err)
+	}
+	if sk.Cmp(sk1) != 0 {
+		t.Error("SecretKeyFromBytes(SecretKeyToBytes()) not match")
+	}
+	if _, err := SecretKeyFromBytes(make([]byte, SecretKeySize)); err == nil {
+		t.Error("zero secret key should fail")
+	}
+}
@@ -0,0 +1,125 @@
+package bls
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"scrypto/ecc/bls381Utils"
+	"scrypto/sutils"
+	"scrypto/sutils/kdf"
+)
+
+// Hierarchical key derivation of EIP-2333: the master key is derived from a
+// seed, and the child i of a key is derived from the compressed Lamport public
+// key of the parent key and of i. EIP-2334 paths m/12381/3600/i/0/0 select a
+// key in the tree.
+
+// lamportChunks is the number of 32 bytes chunks of a Lamport secret key
+const lamportChunks = 255
+
+var (
+	errShortSeed = errors.New("seed should be at least 32 bytes")
+	errPath      = errors.New("invalid derivation path")
+)
+
+// ikmToLamportSK returns the 255 chunks of HKDF(salt, ikm, "", 8160)
+func ikmToLamportSK(ikm, salt []byte) ([]byte, error) {
+	prk, err := kdf.Extract(sutils.SHA256, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	return kdf.Expand(sutils.SHA256, prk, nil, lamportChunks*sha256.Size)
+}
+
+// parentSKToLamportPK returns the compressed Lamport public key of the parent
+// key and of index
+func parentSKToLamportPK(parent *big.Int, index uint32) ([]byte, error) {
+	salt := make([]byte, 4)
+	binary.BigEndian.PutUint32(salt, index)
+	ikm := parent.FillBytes(make([]byte, 32))
+	lamport0, err := ikmToLamportSK(ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ikm {
+		ikm[i] = ^ikm[i]
+	}
+	lamport1, err := ikmToLamportSK(ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	for _, sk := range [][]byte{lamport0, lamport1} {
+		for i := 0; i < len(sk); i += sha256.Size {
+			chunk := sha256.Sum256(sk[i : i+sha256.Size])
+			h.Write(chunk[:])
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// eip2333HKDFModR is the HKDF_mod_r of EIP-2333, KeyGen of the draft 04 of
+// the IETF BLS signature, which hashes the salt before the first iteration
+func eip2333HKDFModR(ikm []byte) (*big.Int, error) {
+	salt := []byte(keyGenSalt)
+	for {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		sk, err := hkdfModR(ikm, salt, nil)
+		if err != nil {
+			return nil, err
+		}
+		if sk.Sign() != 0 {
+			return sk, nil
+		}
+	}
+}
+
+// DeriveMasterSK derives the master secret key of EIP-2333 from a seed of at
+// least 32 bytes
+func DeriveMasterSK(seed []byte) (*big.Int, error) {
+	if len(seed) < 32 {
+		return nil, errShortSeed
+	}
+	return eip2333HKDFModR(seed)
+}
+
+// DeriveChildSK derives the child index of the parent secret key as EIP-2333
+func DeriveChildSK(parent *big.Int, index uint32) (*big.Int, error) {
+	if parent.Sign() < 0 || parent.Cmp(bls381Utils.Order) >= 0 {
+		return nil, errInvalidSecret
+	}
+	pk, err := parentSKToLamportPK(parent, index)
+	if err != nil {
+		return nil, err
+	}
+	return eip2333HKDFModR(pk)
+}
+
+// DerivePath derives the secret key of the path "m/i_1/.../i_n" from seed,
+// as EIP-2334 paths such as "m/12381/3600/0/0/0"
+func DerivePath(seed []byte, path string) (*big.Int, error) {
+	nodes := strings.Split(path, "/")
+	if nodes[0] != "m" {
+		return nil, errPath
+	}
+	sk, err := DeriveMasterSK(seed)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes[1:] {
+		index, err := strconv.ParseUint(node, 10, 32)
+		if err != nil {
+			return nil, errPath
+		}
+		sk, err = DeriveChildSK(sk, uint32(index))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sk, nil
+}
@@ -0,0 +1,96 @@
+package bls
+
+import (
+	"encoding/hex"
+	"math/big"
+	"scrypto/ecc/bls381Utils"
+	"testing"
+)
+
+// EIP-2333 test cases
+var eip2333Vectors = []struct {
+	seed   string
+	master string
+	index  uint32
+	child  string
+}{
+	{
+		seed:   "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		master: "6083874454709270928345386274498605044986640685124978867557563392430687146096",
+		index:  0,
+		child:  "20397789859736650942317412262472558107875392172444076792671091975210932703118",
+	},
+	{
+		seed:   "3141592653589793238462643383279502884197169399375105820974944592",
+		master: "29757020647961307431480504535336562678282505419141012933316116377660817309383",
+		index:  3141592653,
+		child:  "25457201688850691947727629385191704516744796114925897962676248250929345014287",
+	},
+	{
+		seed:   "0099ff991111002299dd7744ee3355bbdd8844115566cc55663355668888cc00",
+		master: "27580842291869792442942448775674722299803720648445448686099262467207037398656",
+		index:  4294967295,
+		child:  "29358610794459428860402234341874281240803786294062035874021252734817515685787",
+	},
+	{
+		seed:   "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+		master: "19022158461524446591288038168518313374041767046816487870552872741050760015818",
+		index:  42,
+		child:  "31372231650479070279774297061823572166496564838472787488249775572789064611981",
+	},
+}
+
+func TestDeriveChildSK(t *testing.T) {
+	for i, v := range eip2333Vectors {
+		seed, err := hex.DecodeString(v.seed)
+		if err != nil {
+			panic(err)
+		}
+		master, err := DeriveMasterSK(seed)
+		if err != nil {
+			panic(err)
+		}
+		if master.String() != v.master {
+			t.Errorf("vector %d: master key %s, expected %s", i, master, v.master)
+		}
+		child, err := DeriveChildSK(master, v.index)
+		if err != nil {
+			panic(err)
+		}
+		if child.String() != v.child {
+			t.Errorf("vector %d: child key %s, expected %s", i, child, v.child)
+		}
+	}
+}
+
+func TestDerivePath(t *testing.T) {
+	seed, err := hex.DecodeString(eip2333Vectors[1].seed)
+	if err != nil {
+		panic(err)
+	}
+	sk, err := DerivePath(seed, "m/3141592653")
+	if err != nil {
+		panic(err)
+	}
+	if sk.String() != eip2333Vectors[1].child {
+		t.Error("path m/3141592653 not match the child key")
+	}
+	master, err := DerivePath(seed, "m")
+	if err != nil {
+		panic(err)
+	}
+	if master.String() != eip2333Vectors[1].master {
+		t.Error("path m not match the master key")
+	}
+	for _, path := range []string{"", "n/0", "m/", "m/-1", "m/4294967296", "m/0x1"} {
+		if _, err := DerivePath(seed, path); err == nil {
+			t.Errorf("path %q accepted", path)
+		}
+	}
+	if _, err := DeriveMasterSK(seed[:31]); err == nil {
+		t.Error("short seed accepted")
+	}
+	if _, err := DeriveChildSK(new(big.Int).Set(bls381Utils.Order), 0); err == nil {
+		t.Error("parent key not below r accepted")
+	}
+}
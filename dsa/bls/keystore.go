@@ -0,0 +1,290 @@
+package bls
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+	"scrypto/ecc/bls381Utils"
+)
+
+// EIP-2335 keystores: the secret key is encrypted with AES-128-CTR under the
+// first half of a key derived from the password by scrypt or PBKDF2, and the
+// checksum SHA-256(second half || ciphertext) detects a wrong password.
+
+// The key derivation functions of a keystore
+const (
+	KeystoreScrypt = "scrypt"
+	KeystorePBKDF2 = "pbkdf2"
+)
+
+const (
+	keystoreVersion = 4
+	keystoreDKLen   = 32
+	keystoreSalt    = 32
+	// scrypt n = 2^18, r = 8, p = 1 and PBKDF2 c = 2^18 as in EIP-2335
+	keystoreScryptN = 1 << 18
+	keystoreScryptR = 8
+	keystoreScryptP = 1
+	keystorePBKDF2C = 1 << 18
+)
+
+var (
+	errKeystoreVersion  = errors.New("keystore version should be 4")
+	errKeystoreFunction = errors.New("unsupported keystore function")
+	errKeystoreParams   = errors.New("invalid keystore parameters")
+	errKeystorePassword = errors.New("invalid keystore password")
+	errKeystorePubkey   = errors.New("keystore public key not match the secret key")
+)
+
+// KeystoreModule is a step of the keystore: the kdf, the checksum or the
+// cipher, with its parameters and its message in hex
+type KeystoreModule struct {
+	Function string          `json:"function"`
+	Params   json.RawMessage `json:"params"`
+	Message  string          `json:"message"`
+}
+
+// KeystoreCrypto holds the modules of a keystore
+type KeystoreCrypto struct {
+	KDF      KeystoreModule `json:"kdf"`
+	Checksum KeystoreModule `json:"checksum"`
+	Cipher   KeystoreModule `json:"cipher"`
+}
+
+// Keystore is an EIP-2335 keystore, the public key is the MinPk public key of
+// the secret key in hex, path is its EIP-2334 path if any
+type Keystore struct {
+	Crypto      KeystoreCrypto `json:"crypto"`
+	Description string         `json:"description"`
+	Pubkey      string         `json:"pubkey"`
+	Path        string         `json:"path"`
+	UUID        string         `json:"uuid"`
+	Version     int            `json:"version"`
+}
+
+type scryptParams struct {
+	DKLen int    `json:"dklen"`
+	N     int    `json:"n"`
+	P     int    `json:"p"`
+	R     int    `json:"r"`
+	Salt  string `json:"salt"`
+}
+
+type pbkdf2Params struct {
+	DKLen int    `json:"dklen"`
+	C     int    `json:"c"`
+	PRF   string `json:"prf"`
+	Salt  string `json:"salt"`
+}
+
+type cipherParams struct {
+	IV string `json:"iv"`
+}
+
+// keystorePassword returns the bytes of password: its NFKD normalization
+// without the control codes C0, C1 and Delete
+func keystorePassword(password string) []byte {
+	var res []byte
+	for _, c := range norm.NFKD.String(password) {
+		if c < 0x20 || (c >= 0x7f && c < 0xa0) {
+			continue
+		}
+		res = append(res, string(c)...)
+	}
+	return res
+}
+
+// decryptionKey derives the decryption key of the kdf module from password
+func (m *KeystoreModule) decryptionKey(password []byte) ([]byte, error) {
+	switch m.Function {
+	case KeystoreScrypt:
+		var p scryptParams
+		if err := json.Unmarshal(m.Params, &p); err != nil {
+			return nil, err
+		}
+		salt, err := hex.DecodeString(p.Salt)
+		if err != nil || p.DKLen != keystoreDKLen {
+			return nil, errKeystoreParams
+		}
+		return scrypt.Key(password, salt, p.N, p.R, p.P, p.DKLen)
+	case KeystorePBKDF2:
+		var p pbkdf2Params
+		if err := json.Unmarshal(m.Params, &p); err != nil {
+			return nil, err
+		}
+		salt, err := hex.DecodeString(p.Salt)
+		if err != nil || p.DKLen != keystoreDKLen || p.C < 1 {
+			return nil, errKeystoreParams
+		}
+		if p.PRF != "hmac-sha256" {
+			return nil, errKeystoreFunction
+		}
+		return pbkdf2.Key(password, salt, p.C, p.DKLen, sha256.New), nil
+	}
+	return nil, errKeystoreFunction
+}
+
+// aes128CTR encrypts or decrypts data with AES-128-CTR
+func aes128CTR(key, iv, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, errKeystoreParams
+	}
+	res := make([]byte, len(data))
+	cipher.NewCTR(block, iv).XORKeyStream(res, data)
+	return res, nil
+}
+
+// keystoreChecksum returns SHA-256(dk[16:32] || ciphertext)
+func keystoreChecksum(dk, ciphertext []byte) []byte {
+	h := sha256.New()
+	h.Write(dk[16:32])
+	h.Write(ciphertext)
+	return h.Sum(nil)
+}
+
+// randomBytes returns n bytes of crypto/rand
+func randomBytes(n int) ([]byte, error) {
+	res := make([]byte, n)
+	if _, err := rand.Read(res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// NewKeystore encrypts sk with password in a keystore, kdf is KeystoreScrypt
+// or KeystorePBKDF2 with the parameters of EIP-2335, path is the EIP-2334
+// path of sk or ""
+func NewKeystore(sk *big.Int, password, path, kdf string) (*Keystore, error) {
+	if sk.Sign() <= 0 || sk.Cmp(bls381Utils.Order) >= 0 {
+		return nil, errInvalidSecret
+	}
+	salt, err := randomBytes(keystoreSalt)
+	if err != nil {
+		return nil, err
+	}
+	var params interface{}
+	switch kdf {
+	case KeystoreScrypt:
+		params = scryptParams{DKLen: keystoreDKLen, N: keystoreScryptN, P: keystoreScryptP, R: keystoreScryptR, Salt: hex.EncodeToString(salt)}
+	case KeystorePBKDF2:
+		params = pbkdf2Params{DKLen: keystoreDKLen, C: keystorePBKDF2C, PRF: "hmac-sha256", Salt: hex.EncodeToString(salt)}
+	default:
+		return nil, errKeystoreFunction
+	}
+	k := &Keystore{Pubkey: hex.EncodeToString(MinPk.SkToPk(sk)), Path: path, Version: keystoreVersion}
+	k.Crypto.KDF = KeystoreModule{Function: kdf, Params: mustMarshal(params)}
+	dk, err := k.Crypto.KDF.decryptionKey(keystorePassword(password))
+	if err != nil {
+		return nil, err
+	}
+	iv, err := randomBytes(aes.BlockSize)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := aes128CTR(dk[:16], iv, SecretKeyToBytes(sk))
+	if err != nil {
+		return nil, err
+	}
+	k.Crypto.Cipher = KeystoreModule{
+		Function: "aes-128-ctr",
+		Params:   mustMarshal(cipherParams{IV: hex.EncodeToString(iv)}),
+		Message:  hex.EncodeToString(ciphertext),
+	}
+	k.Crypto.Checksum = KeystoreModule{
+		Function: "sha256",
+		Params:   json.RawMessage("{}"),
+		Message:  hex.EncodeToString(keystoreChecksum(dk, ciphertext)),
+	}
+	id, err := randomBytes(16)
+	if err != nil {
+		return nil, err
+	}
+	// version 4 UUID
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	k.UUID = fmt.Sprintf("%x-%x-%x-%x-%x", id[:4], id[4:6], id[6:8], id[8:10], id[10:])
+	return k, nil
+}
+
+// mustMarshal returns the JSON encoding of parameters, which cannot fail
+func mustMarshal(v interface{}) json.RawMessage {
+	res, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// LoadKeystore decodes a JSON keystore of version 4
+func LoadKeystore(data []byte) (*Keystore, error) {
+	k := new(Keystore)
+	if err := json.Unmarshal(data, k); err != nil {
+		return nil, err
+	}
+	if k.Version != keystoreVersion {
+		return nil, errKeystoreVersion
+	}
+	return k, nil
+}
+
+// Decrypt returns the secret key of k, it fails on a wrong password or if
+// the public key of k is not the one of the secret key
+func (k *Keystore) Decrypt(password string) (*big.Int, error) {
+	if k.Version != keystoreVersion {
+		return nil, errKeystoreVersion
+	}
+	c := &k.Crypto
+	if c.Checksum.Function != "sha256" || c.Cipher.Function != "aes-128-ctr" {
+		return nil, errKeystoreFunction
+	}
+	var p cipherParams
+	if err := json.Unmarshal(c.Cipher.Params, &p); err != nil {
+		return nil, err
+	}
+	iv, err := hex.DecodeString(p.IV)
+	if err != nil {
+		return nil, errKeystoreParams
+	}
+	ciphertext, err := hex.DecodeString(c.Cipher.Message)
+	if err != nil {
+		return nil, errKeystoreParams
+	}
+	checksum, err := hex.DecodeString(c.Checksum.Message)
+	if err != nil {
+		return nil, errKeystoreParams
+	}
+	dk, err := c.KDF.decryptionKey(keystorePassword(password))
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(keystoreChecksum(dk, ciphertext), checksum) != 1 {
+		return nil, errKeystorePassword
+	}
+	secret, err := aes128CTR(dk[:16], iv, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	sk, err := SecretKeyFromBytes(secret)
+	if err != nil {
+		return nil, err
+	}
+	if k.Pubkey != "" && k.Pubkey != hex.EncodeToString(MinPk.SkToPk(sk)) {
+		return nil, errKeystorePubkey
+	}
+	return sk, nil
+}
@@ -0,0 +1,81 @@
+package bls
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"testing"
+)
+
+// the password of the EIP-2335 test keystores, NFKD normalizes it to
+// "testpassword🔑"
+const keystoreTestPassword = "𝔱𝔢𝔰𝔱𝔭𝔞𝔰𝔰𝔴𝔬𝔯𝔡🔑"
+
+func TestKeystore_Decrypt(t *testing.T) {
+	secret, ok := new(big.Int).SetString("000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f", 16)
+	if !ok {
+		panic("bad secret")
+	}
+	for _, file := range []string{"testdata/keystore_scrypt.json", "testdata/keystore_pbkdf2.json"} {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			panic(err)
+		}
+		k, err := LoadKeystore(data)
+		if err != nil {
+			panic(err)
+		}
+		sk, err := k.Decrypt(keystoreTestPassword)
+		if err != nil {
+			t.Errorf("%s: %v", file, err)
+			continue
+		}
+		if sk.Cmp(secret) != 0 {
+			t.Errorf("%s: secret key not match", file)
+		}
+		if _, err := k.Decrypt("testpassword"); err == nil {
+			t.Errorf("%s: wrong password accepted", file)
+		}
+	}
+}
+
+func TestNewKeystore(t *testing.T) {
+	seed := []byte("a seed of the derivation of 32 bytes")
+	path := "m/12381/3600/0/0/0"
+	sk, err := DerivePath(seed, path)
+	if err != nil {
+		panic(err)
+	}
+	for _, kdf := range []string{KeystorePBKDF2, KeystoreScrypt} {
+		k, err := NewKeystore(sk, "a password\u0007", path, kdf)
+		if err != nil {
+			panic(err)
+		}
+		data, err := json.Marshal(k)
+		if err != nil {
+			panic(err)
+		}
+		loaded, err := LoadKeystore(data)
+		if err != nil {
+			panic(err)
+		}
+		// control codes are not part of the password
+		got, err := loaded.Decrypt("a password")
+		if err != nil {
+			t.Errorf("%s: %v", kdf, err)
+			continue
+		}
+		if got.Cmp(sk) != 0 || loaded.Path != path || len(loaded.UUID) != 36 {
+			t.Errorf("%s: keystore not match", kdf)
+		}
+		if _, err := loaded.Decrypt("another password"); err == nil {
+			t.Errorf("%s: wrong password accepted", kdf)
+		}
+	}
+	if _, err := NewKeystore(sk, "", "", "argon2"); err == nil {
+		t.Error("unsupported kdf accepted")
+	}
+	if _, err := LoadKeystore([]byte(`{"version": 3}`)); err == nil {
+		t.Error("keystore version 3 accepted")
+	}
+}
@@ -0,0 +1,105 @@
+package bls
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+)
+
+// Proofs of possession: a key is only aggregated once its owner has signed
+// the key itself under the PoP tag of the ciphersuite, which the owner of a
+// rogue key pk' = [sk']g - pk cannot do without the secret key of pk.
+
+var (
+	errNoPop           = errors.New("the ciphersuite has no proof of possession")
+	errInvalidPop      = errors.New("invalid proof of possession")
+	errUnregisteredKey = errors.New("public key not registered")
+)
+
+// HasPop reports whether s is a ciphersuite of the proof of possession scheme
+func (s *Scheme) HasPop() bool {
+	return s.popID != ""
+}
+
+// PopProve returns the proof of possession of sk, the signature of the
+// encoding of its public key under the PoP tag
+func (s *Scheme) PopProve(sk *big.Int) ([]byte, error) {
+	if !s.HasPop() {
+		return nil, errNoPop
+	}
+	return s.sign(sk, s.SkToPk(sk), s.popID)
+}
+
+// PopVerify reports whether proof is a proof of possession of the secret key
+// of pk
+func (s *Scheme) PopVerify(pk, proof []byte) bool {
+	if !s.HasPop() {
+		return false
+	}
+	return s.verify([][]byte{pk}, [][]byte{pk}, proof, s.popID)
+}
+
+// Registry holds the public keys whose proof of possession was checked, only
+// those keys are aggregated. It is safe for concurrent use.
+type Registry struct {
+	scheme *Scheme
+	mu     sync.RWMutex
+	keys   map[string]struct{}
+}
+
+// NewRegistry returns an empty registry of keys of s, s must be a ciphersuite
+// of the proof of possession scheme
+func NewRegistry(s *Scheme) (*Registry, error) {
+	if !s.HasPop() {
+		return nil, errNoPop
+	}
+	return &Registry{scheme: s, keys: make(map[string]struct{})}, nil
+}
+
+// Register adds pk if proof is a valid proof of possession of its secret key
+func (r *Registry) Register(pk, proof []byte) error {
+	if !r.scheme.PopVerify(pk, proof) {
+		return errInvalidPop
+	}
+	r.mu.Lock()
+	r.keys[string(pk)] = struct{}{}
+	r.mu.Unlock()
+	return nil
+}
+
+// IsRegistered reports whether pk was registered
+func (r *Registry) IsRegistered(pk []byte) bool {
+	r.mu.RLock()
+	_, ok := r.keys[string(pk)]
+	r.mu.RUnlock()
+	return ok
+}
+
+// registered returns an error if a key of pks was not registered
+func (r *Registry) registered(pks [][]byte) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, pk := range pks {
+		if _, ok := r.keys[string(pk)]; !ok {
+			return errUnregisteredKey
+		}
+	}
+	return nil
+}
+
+// AggregatePublicKeys returns the sum of pks, all of them must be registered
+func (r *Registry) AggregatePublicKeys(pks [][]byte) ([]byte, error) {
+	if err := r.registered(pks); err != nil {
+		return nil, err
+	}
+	return r.scheme.AggregatePublicKeys(pks)
+}
+
+// FastAggregateVerify is the FastAggregateVerify of the scheme, it rejects
+// keys that were not registered
+func (r *Registry) FastAggregateVerify(pks [][]byte, message, signature []byte) bool {
+	if r.registered(pks) != nil {
+		return false
+	}
+	return r.scheme.FastAggregateVerify(pks, message, signature)
+}
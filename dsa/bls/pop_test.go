@@ -0,0 +1,162 @@
+package bls
+
+import (
+	"math/big"
+	"testing"
+
+	"scrypto/ecc/bls381"
+	"scrypto/ecc/bls381Utils"
+)
+
+// rogueKey returns the key [skA]g - pk of an attacker knowing skA only, the sum
+// of pk and of the rogue key is the key of skA
+func rogueKey(s *Scheme, pk []byte, skA *bls381Utils.Scalar) []byte {
+	if s.minPk {
+		p, ok := decodeG1(pk)
+		if !ok {
+			panic("bad public key")
+		}
+		var res bls381.G1Affine
+		return bls381Utils.G1Add(bls381Utils.G1MulBase(skA), bls381Utils.G1Neg(p)).ToAffineFromJac(&res).Bytes()
+	}
+	p, ok := decodeG2(pk)
+	if !ok {
+		panic("bad public key")
+	}
+	var res bls381.G2Affine
+	return bls381Utils.G2Add(bls381Utils.G2MulBase(skA), bls381Utils.G2Neg(p)).ToAffineFromJac(&res).Bytes()
+}
+
+func TestScheme_PopVerify(t *testing.T) {
+	for _, s := range []*Scheme{MinPkPop, MinSigPop} {
+		sk, err := KeyGen([]byte("an honest key material of 32 bytes"), nil)
+		if err != nil {
+			panic(err)
+		}
+		other, err := KeyGen([]byte("another key material of 32 bytes!"), nil)
+		if err != nil {
+			panic(err)
+		}
+		pk := s.SkToPk(sk)
+		proof, err := s.PopProve(sk)
+		if err != nil {
+			panic(err)
+		}
+		if !s.PopVerify(pk, proof) {
+			t.Errorf("%s: proof of possession not verified", s.ID())
+		}
+		if s.PopVerify(s.SkToPk(other), proof) {
+			t.Errorf("%s: proof of possession verified for another key", s.ID())
+		}
+		// the PoP tag separates proofs from signatures of the key
+		sig, err := s.Sign(sk, pk)
+		if err != nil {
+			panic(err)
+		}
+		if s.PopVerify(pk, sig) {
+			t.Errorf("%s: signature of the key accepted as a proof", s.ID())
+		}
+		if s.Verify(pk, pk, proof) {
+			t.Errorf("%s: proof accepted as a signature of the key", s.ID())
+		}
+	}
+	for _, s := range []*Scheme{MinPk, MinSig} {
+		if _, err := s.PopProve(big.NewInt(1)); err == nil {
+			t.Errorf("%s: proof of possession in the basic scheme", s.ID())
+		}
+		if _, err := NewRegistry(s); err == nil {
+			t.Errorf("%s: registry of the basic scheme", s.ID())
+		}
+	}
+}
+
+func TestRegistry_RogueKey(t *testing.T) {
+	for _, s := range []*Scheme{MinPkPop, MinSigPop} {
+		skH, err := KeyGen([]byte("an honest key material of 32 bytes"), nil)
+		if err != nil {
+			panic(err)
+		}
+		skA, err := KeyGen([]byte("the attacker key material 32 bytes"), nil)
+		if err != nil {
+			panic(err)
+		}
+		pkH := s.SkToPk(skH)
+		rogue := rogueKey(s, pkH, bls381Utils.NewScalar(skA))
+		m := []byte("the honest signer never signed this")
+		forged, err := s.Sign(skA, m)
+		if err != nil {
+			panic(err)
+		}
+		pks := [][]byte{pkH, rogue}
+		// the basic scheme refuses to aggregate keys without proofs of
+		// possession, which would accept the forgery
+		basic := MinPk
+		if !s.minPk {
+			basic = MinSig
+		}
+		naive, err := basic.Sign(skA, m)
+		if err != nil {
+			panic(err)
+		}
+		if basic.FastAggregateVerify(pks, m, naive) {
+			t.Errorf("%s: forgery verified without proofs of possession", basic.ID())
+		}
+		if _, err := basic.AggregatePublicKeys(pks); err == nil {
+			t.Errorf("%s: rogue key aggregated without proofs of possession", basic.ID())
+		}
+
+		reg, err := NewRegistry(s)
+		if err != nil {
+			panic(err)
+		}
+		proofH, err := s.PopProve(skH)
+		if err != nil {
+			panic(err)
+		}
+		if err := reg.Register(pkH, proofH); err != nil {
+			t.Errorf("%s: honest key refused: %v", s.ID(), err)
+		}
+		// the attacker only has proofs for keys it knows
+		proofA, err := s.PopProve(skA)
+		if err != nil {
+			panic(err)
+		}
+		for _, proof := range [][]byte{proofA, proofH, forged} {
+			if reg.Register(rogue, proof) == nil {
+				t.Errorf("%s: rogue key registered", s.ID())
+			}
+		}
+		if reg.IsRegistered(rogue) || !reg.IsRegistered(pkH) {
+			t.Errorf("%s: wrong registered keys", s.ID())
+		}
+		if reg.FastAggregateVerify(pks, m, forged) {
+			t.Errorf("%s: forgery verified with a rogue key", s.ID())
+		}
+		if _, err := reg.AggregatePublicKeys(pks); err == nil {
+			t.Errorf("%s: rogue key aggregated", s.ID())
+		}
+
+		// registered keys aggregate as usual
+		sig, err := s.Sign(skH, m)
+		if err != nil {
+			panic(err)
+		}
+		if !reg.FastAggregateVerify(pks[:1], m, sig) {
+			t.Errorf("%s: registered key not verified", s.ID())
+		}
+	}
+}
+
+func TestScheme_AggregateVerifyPop(t *testing.T) {
+	for _, s := range []*Scheme{MinPkPop, MinSigPop} {
+		pks, _, _, sigs, m := signers(s, 3)
+		agg, err := s.Aggregate(sigs)
+		if err != nil {
+			panic(err)
+		}
+		// repeated messages are allowed with proofs of possession
+		if !s.AggregateVerify(pks, [][]byte{m, m, m}, agg) {
+			t.Errorf("%s: repeated messages refused", s.ID())
+		}
+	}
+}
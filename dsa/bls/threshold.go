@@ -0,0 +1,218 @@
+package bls
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+
+	"scrypto/ecc/bls381"
+	"scrypto/ecc/bls381/fr"
+	"scrypto/ecc/bls381Utils"
+	"scrypto/smath/poly/bls381Poly"
+)
+
+// Threshold signatures: the secret key is the constant term of a polynomial f
+// of degree t-1 over fr and party i holds f(i). Partial signatures [f(i)]H(m)
+// are plain signatures under the share public keys [f(i)]g, any t of them
+// give [f(0)]H(m) by Lagrange interpolation at 0 in the exponent, which
+// verifies under the group public key [f(0)]g.
+
+// indexSize is the size of an encoded share index
+const indexSize = 4
+
+var (
+	errThreshold      = errors.New("threshold should be in [1, n]")
+	errShareIndex     = errors.New("share index should not be 0")
+	errDuplicateIndex = errors.New("duplicate share index")
+	errFewPartials    = errors.New("not enough partial signatures")
+	errShareSize      = errors.New("key share size not match")
+	errPartialSize    = errors.New("partial signature size not match")
+)
+
+// KeyShare is the share f(Index) of a secret key
+type KeyShare struct {
+	Index  uint32
+	Secret *big.Int
+}
+
+// PartialSignature is the signature of a message with the key share Index
+type PartialSignature struct {
+	Index     uint32
+	Signature []byte
+}
+
+// Deal shares sk in n key shares, any t of them sign for sk. The coefficients
+// of the polynomial are drawn from random, crypto/rand if random is nil.
+func Deal(sk *big.Int, t, n int, random io.Reader) ([]*KeyShare, error) {
+	if t < 1 || t > n || uint64(n) > uint64(^uint32(0)) {
+		return nil, errThreshold
+	}
+	if sk.Sign() <= 0 || sk.Cmp(bls381Utils.Order) >= 0 {
+		return nil, errInvalidSecret
+	}
+	f := make(bls381Poly.Polynomial, t)
+	f[0] = bls381Utils.NewScalar(sk).Element()
+	for i := 1; i < t; i++ {
+		c, err := bls381Utils.RandomScalar(random)
+		if err != nil {
+			return nil, err
+		}
+		f[i] = c.Element()
+	}
+	shares := make([]*KeyShare, n)
+	var x fr.Element
+	for i := range shares {
+		index := uint32(i + 1)
+		y := f.Eval(x.SetUint64(uint64(index)))
+		shares[i] = &KeyShare{Index: index, Secret: y.ToBigIntRegular(new(big.Int))}
+	}
+	return shares, nil
+}
+
+// Bytes encodes k as its big-endian index in 4 bytes and its secret in
+// SecretKeySize bytes
+func (k *KeyShare) Bytes() []byte {
+	res := make([]byte, indexSize, indexSize+SecretKeySize)
+	binary.BigEndian.PutUint32(res, k.Index)
+	return append(res, SecretKeyToBytes(k.Secret)...)
+}
+
+// KeyShareFromBytes decodes a key share encoded by Bytes
+func KeyShareFromBytes(data []byte) (*KeyShare, error) {
+	if len(data) != indexSize+SecretKeySize {
+		return nil, errShareSize
+	}
+	index := binary.BigEndian.Uint32(data)
+	if index == 0 {
+		return nil, errShareIndex
+	}
+	sk, err := SecretKeyFromBytes(data[indexSize:])
+	if err != nil {
+		return nil, err
+	}
+	return &KeyShare{Index: index, Secret: sk}, nil
+}
+
+// Bytes encodes p as its big-endian index in 4 bytes and its signature
+func (p *PartialSignature) Bytes() []byte {
+	res := make([]byte, indexSize, indexSize+len(p.Signature))
+	binary.BigEndian.PutUint32(res, p.Index)
+	return append(res, p.Signature...)
+}
+
+// PartialSignatureFromBytes decodes a partial signature of s encoded by Bytes,
+// the signature is checked by PartialVerify
+func (s *Scheme) PartialSignatureFromBytes(data []byte) (*PartialSignature, error) {
+	if len(data) != indexSize+s.SignatureSize() {
+		return nil, errPartialSize
+	}
+	index := binary.BigEndian.Uint32(data)
+	if index == 0 {
+		return nil, errShareIndex
+	}
+	return &PartialSignature{Index: index, Signature: append([]byte{}, data[indexSize:]...)}, nil
+}
+
+// SharePublicKey returns the public key of a key share, against which its
+// partial signatures verify
+func (s *Scheme) SharePublicKey(share *KeyShare) []byte {
+	return s.SkToPk(share.Secret)
+}
+
+// PartialSign signs message with a key share
+func (s *Scheme) PartialSign(share *KeyShare, message []byte) (*PartialSignature, error) {
+	sig, err := s.Sign(share.Secret, message)
+	if err != nil {
+		return nil, err
+	}
+	return &PartialSignature{Index: share.Index, Signature: sig}, nil
+}
+
+// PartialVerify reports whether partial is a signature of message under the
+// public key of its share
+func (s *Scheme) PartialVerify(sharePk, message []byte, partial *PartialSignature) bool {
+	return s.Verify(sharePk, message, partial.Signature)
+}
+
+// lagrangeAtZero returns the Lagrange coefficients at 0 of the distinct
+// indices: l_i = prod_{j != i} x_j / (x_j - x_i)
+func lagrangeAtZero(indices []uint32) []*bls381Utils.Scalar {
+	xs := make([]bls381Utils.Scalar, len(indices))
+	for i, index := range indices {
+		xs[i].SetUint64(uint64(index))
+	}
+	res := make([]*bls381Utils.Scalar, len(indices))
+	var num, den, t bls381Utils.Scalar
+	for i := range xs {
+		num.SetUint64(1)
+		den.SetUint64(1)
+		for j := range xs {
+			if i != j {
+				num.Mul(&num, &xs[j])
+				den.Mul(&den, t.Sub(&xs[j], &xs[i]))
+			}
+		}
+		res[i] = new(bls381Utils.Scalar).Mul(&num, den.Inverse(&den))
+	}
+	return res
+}
+
+// Combine interpolates the first t partial signatures into the signature of
+// the secret key, which Verify checks under the group public key. Partial
+// signatures should pass PartialVerify first, an invalid one gives an invalid
+// signature.
+func (s *Scheme) Combine(t int, partials []*PartialSignature) ([]byte, error) {
+	if t < 1 {
+		return nil, errThreshold
+	}
+	if len(partials) < t {
+		return nil, errFewPartials
+	}
+	partials = partials[:t]
+	indices := make([]uint32, t)
+	seen := make(map[uint32]struct{}, t)
+	for i, p := range partials {
+		if p.Index == 0 {
+			return nil, errShareIndex
+		}
+		if _, ok := seen[p.Index]; ok {
+			return nil, errDuplicateIndex
+		}
+		seen[p.Index] = struct{}{}
+		indices[i] = p.Index
+	}
+	coefficients := lagrangeAtZero(indices)
+	if s.minPk {
+		var sum bls381Utils.G2
+		for i, p := range partials {
+			sig, ok := decodeG2(p.Signature)
+			if !ok {
+				return nil, errInvalidSignature
+			}
+			term := bls381Utils.G2Mul(sig, coefficients[i])
+			if i == 0 {
+				sum.Set(term)
+			} else {
+				sum.Add(bls381Utils.BLSCurve, term)
+			}
+		}
+		var res bls381.G2Affine
+		return sum.ToAffineFromJac(&res).Bytes(), nil
+	}
+	var sum bls381Utils.G1
+	for i, p := range partials {
+		sig, ok := decodeG1(p.Signature)
+		if !ok {
+			return nil, errInvalidSignature
+		}
+		term := bls381Utils.G1Mul(sig, coefficients[i])
+		if i == 0 {
+			sum.Set(term)
+		} else {
+			sum.Add(bls381Utils.BLSCurve, term)
+		}
+	}
+	var res bls381.G1Affine
+	return sum.ToAffineFromJac(&res).Bytes(), nil
+}
@@ -0,0 +1,174 @@
+package bls
+
+import (
+	"bytes"
+	"testing"
+)
+
+// deal shares a fixed key of s in n shares with threshold t and returns the
+// shares, their public keys and the group public key
+func deal(s *Scheme, t, n int) ([]*KeyShare, [][]byte, []byte) {
+	sk, err := KeyGen([]byte("the group key material of 32 bytes"), nil)
+	if err != nil {
+		panic(err)
+	}
+	shares, err := Deal(sk, t, n, nil)
+	if err != nil {
+		panic(err)
+	}
+	pks := make([][]byte, n)
+	for i, share := range shares {
+		pks[i] = s.SharePublicKey(share)
+	}
+	return shares, pks, s.SkToPk(sk)
+}
+
+func TestScheme_Combine(t *testing.T) {
+	const threshold, n = 3, 5
+	m := []byte("threshold message")
+	for _, s := range []*Scheme{MinPk, MinSig} {
+		shares, pks, groupPk := deal(s, threshold, n)
+		partials := make([]*PartialSignature, n)
+		for i, share := range shares {
+			p, err := s.PartialSign(share, m)
+			if err != nil {
+				panic(err)
+			}
+			if !s.PartialVerify(pks[i], m, p) {
+				t.Errorf("%s: partial signature %d not verified", s.ID(), p.Index)
+			}
+			if s.PartialVerify(pks[(i+1)%n], m, p) {
+				t.Errorf("%s: partial signature %d verified under another share", s.ID(), p.Index)
+			}
+			partials[i] = p
+		}
+		// any t partial signatures give the same signature
+		var first []byte
+		for _, set := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3}} {
+			var subset []*PartialSignature
+			for _, i := range set {
+				subset = append(subset, partials[i])
+			}
+			sig, err := s.Combine(threshold, subset)
+			if err != nil {
+				panic(err)
+			}
+			if !s.Verify(groupPk, m, sig) {
+				t.Errorf("%s: combined signature of %v not verified", s.ID(), set)
+			}
+			if first == nil {
+				first = sig
+			} else if !bytes.Equal(first, sig) {
+				t.Errorf("%s: combined signature of %v differs", s.ID(), set)
+			}
+		}
+		// t-1 partial signatures are not enough
+		if _, err := s.Combine(threshold, partials[:threshold-1]); err == nil {
+			t.Errorf("%s: too few partial signatures combined", s.ID())
+		}
+		sig, err := s.Combine(threshold-1, partials[:threshold-1])
+		if err != nil {
+			panic(err)
+		}
+		if s.Verify(groupPk, m, sig) {
+			t.Errorf("%s: signature of t-1 shares verified", s.ID())
+		}
+		dup := []*PartialSignature{partials[0], partials[1], partials[0]}
+		if _, err := s.Combine(threshold, dup); err == nil {
+			t.Errorf("%s: duplicate share index combined", s.ID())
+		}
+	}
+}
+
+func TestScheme_CombineInvalidPartial(t *testing.T) {
+	const threshold, n = 2, 4
+	m := []byte("threshold message")
+	s := MinSig
+	shares, pks, groupPk := deal(s, threshold, n)
+	// share 1 signs another message, the combiner drops it
+	var valid []*PartialSignature
+	for i, share := range shares {
+		msg := m
+		if i == 0 {
+			msg = []byte("another message")
+		}
+		p, err := s.PartialSign(share, msg)
+		if err != nil {
+			panic(err)
+		}
+		if s.PartialVerify(pks[i], m, p) {
+			valid = append(valid, p)
+		}
+	}
+	if len(valid) != n-1 || valid[0].Index != 2 {
+		t.Fatalf("wrong valid partial signatures")
+	}
+	sig, err := s.Combine(threshold, valid)
+	if err != nil {
+		panic(err)
+	}
+	if !s.Verify(groupPk, m, sig) {
+		t.Error("combined signature of the valid partials not verified")
+	}
+}
+
+func TestKeyShare_Bytes(t *testing.T) {
+	shares, _, _ := deal(MinPk, 2, 3)
+	for _, share := range shares {
+		got, err := KeyShareFromBytes(share.Bytes())
+		if err != nil {
+			panic(err)
+		}
+		if got.Index != share.Index || got.Secret.Cmp(share.Secret) != 0 {
+			t.Errorf("key share %d not decoded", share.Index)
+		}
+	}
+	if _, err := KeyShareFromBytes(make([]byte, indexSize+SecretKeySize)); err == nil {
+		t.Error("share index 0 decoded")
+	}
+	if _, err := KeyShareFromBytes(shares[0].Bytes()[1:]); err == nil {
+		t.Error("short key share decoded")
+	}
+}
+
+func TestPartialSignature_Bytes(t *testing.T) {
+	for _, s := range []*Scheme{MinPk, MinSig} {
+		shares, pks, _ := deal(s, 2, 3)
+		p, err := s.PartialSign(shares[2], []byte("message"))
+		if err != nil {
+			panic(err)
+		}
+		got, err := s.PartialSignatureFromBytes(p.Bytes())
+		if err != nil {
+			panic(err)
+		}
+		if got.Index != p.Index || !s.PartialVerify(pks[2], []byte("message"), got) {
+			t.Errorf("%s: partial signature not decoded", s.ID())
+		}
+		if _, err := s.PartialSignatureFromBytes(p.Bytes()[:indexSize+1]); err == nil {
+			t.Errorf("%s: short partial signature decoded", s.ID())
+		}
+	}
+}
+
+func TestDeal(t *testing.T) {
+	sk, err := KeyGen([]byte("the group key material of 32 bytes"), nil)
+	if err != nil {
+		panic(err)
+	}
+	for _, c := range [][2]int{{0, 3}, {4, 3}, {-1, 2}} {
+		if _, err := Deal(sk, c[0], c[1], nil); err == nil {
+			t.Errorf("threshold %d of %d accepted", c[0], c[1])
+		}
+	}
+	// a threshold of 1 gives every party the secret key
+	shares, err := Deal(sk, 1, 2, nil)
+	if err != nil {
+		panic(err)
+	}
+	for _, share := range shares {
+		if share.Secret.Cmp(sk) != 0 {
+			t.Error("share of a 1 of n sharing is not the secret key")
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package ecdsaUtils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+
+	"scrypto/ecc/group"
+	"scrypto/ecc/secp256k1Utils"
+	"scrypto/proof/transcript"
+)
+
+// Adaptor signatures: for the statement T = [t]G, the signer draws k and sets
+// R' = [k]G, R = [k]T, r = x(R) mod n and s' = k^{-1} (e + r x). Anyone can
+// check (r, s') against R' like a signature, and a DLEQ proof shows that R'
+// and R share k. Whoever knows t adapts s' to s = s' / t, the nonce of (r, s)
+// is then k t with [k t]G = R, and from s and s' anyone extracts t = s' / s.
+
+var (
+	errStatement    = errors.New("witness is not the discrete logarithm of the statement")
+	errPreSignature = errors.New("signature is not the adaptation of the pre-signature")
+)
+
+// DLEQProof proves that log_G A = log_H B
+type DLEQProof struct {
+	C, Z *big.Int
+}
+
+// PreSignature is an ECDSA pre-signature bound to the statement T, the
+// points are encoded uncompressed
+type PreSignature struct {
+	T      []byte
+	R      []byte
+	RPrime []byte
+	S      *big.Int
+	Proof  DLEQProof
+}
+
+// group returns the group of the curve of c, adaptor signatures are
+// available on P-256 and secp256k1
+func (c *ECDSA) group() (group.Group, error) {
+	switch {
+	case c.sameCurve(elliptic.P256()):
+		return group.P256(), nil
+	case c.sameCurve(secp256k1Utils.S256()):
+		return group.Secp256k1(), nil
+	}
+	return nil, errCurve
+}
+
+// publicPoint returns the point of a public key of the curve of c
+func (c *ECDSA) publicPoint(g group.Group, pk *ecdsa.PublicKey) (group.Point, bool) {
+	if pk == nil || !c.sameCurve(pk.Curve) || !c.curve.IsOnCurve(pk.X, pk.Y) {
+		return nil, false
+	}
+	p, err := g.Unmarshal(elliptic.Marshal(c.curve, pk.X, pk.Y))
+	return p, err == nil
+}
+
+// point decodes a point of g, the identity is rejected
+func point(g group.Group, data []byte) (group.Point, bool) {
+	p, err := g.Unmarshal(data)
+	if err != nil || g.IsIdentity(p) {
+		return nil, false
+	}
+	return p, true
+}
+
+// affineX returns the x-coordinate of p mod the order of g, 0 for the identity
+func affineX(g group.Group, p group.Point) *big.Int {
+	data := g.Marshal(p)
+	x := new(big.Int).SetBytes(data[1 : 1+(len(data)-1)/2])
+	return x.Mod(x, g.Order())
+}
+
+// dleqChallenge hashes the statement log_G a = log_h b and the commitments
+func dleqChallenge(g group.Group, h, a, b, a1, a2 group.Point) *big.Int {
+	tr := transcript.New("ecdsa-adaptor-dleq")
+	tr.AppendMessage("group", []byte(g.Name()))
+	tr.AppendPoint("H", g, h)
+	tr.AppendPoint("A", g, a)
+	tr.AppendPoint("B", g, b)
+	tr.AppendPoint("A1", g, a1)
+	tr.AppendPoint("A2", g, a2)
+	return tr.ChallengeScalar("c", g.Order())
+}
+
+// proveDLEQ proves that A = [k]G and B = [k]H
+func proveDLEQ(g group.Group, k *big.Int, h, a, b group.Point) (DLEQProof, error) {
+	w, err := group.RandomScalar(g, nil)
+	if err != nil {
+		return DLEQProof{}, err
+	}
+	e := dleqChallenge(g, h, a, b, g.ScalarBaseMult(w), g.ScalarMult(h, w))
+	// z = w + e k
+	z := new(big.Int).Mul(e, k)
+	z.Add(z, w)
+	z.Mod(z, g.Order())
+	return DLEQProof{C: e, Z: z}, nil
+}
+
+// verifyDLEQ recomputes A1 = [z]G - [c]A and A2 = [z]H - [c]B and their challenge
+func verifyDLEQ(g group.Group, proof *DLEQProof, h, a, b group.Point) bool {
+	n := g.Order()
+	if proof.C == nil || proof.Z == nil || proof.C.Sign() < 0 || proof.C.Cmp(n) >= 0 ||
+		proof.Z.Sign() < 0 || proof.Z.Cmp(n) >= 0 {
+		return false
+	}
+	a1 := group.Sub(g, g.ScalarBaseMult(proof.Z), g.ScalarMult(a, proof.C))
+	a2 := group.Sub(g, g.ScalarMult(h, proof.Z), g.ScalarMult(b, proof.C))
+	return dleqChallenge(g, h, a, b, a1, a2).Cmp(proof.C) == 0
+}
+
+// PreSign returns the pre-signature of message with sk for the statement T
+func (c *ECDSA) PreSign(sk *ecdsa.PrivateKey, message []byte, T *ecdsa.PublicKey) (*PreSignature, error) {
+	g, err := c.group()
+	if err != nil {
+		return nil, err
+	}
+	if sk == nil || sk.D == nil {
+		return nil, errInvalidKey
+	}
+	if !c.sameCurve(sk.Curve) {
+		return nil, errCurve
+	}
+	t, ok := c.publicPoint(g, T)
+	if !ok {
+		return nil, errCurve
+	}
+	digest, err := c.digest(message)
+	if err != nil {
+		return nil, err
+	}
+	n := g.Order()
+	if sk.D.Sign() <= 0 || sk.D.Cmp(n) >= 0 {
+		return nil, errInvalidKey
+	}
+	e := bits2int(digest, n.BitLen())
+	inv := newMontgomery(n)
+	for {
+		k, err := group.RandomScalar(g, nil)
+		if err != nil {
+			return nil, err
+		}
+		rPrime, r := g.ScalarBaseMult(k), g.ScalarMult(t, k)
+		rx := affineX(g, r)
+		if rx.Sign() == 0 {
+			continue
+		}
+		// s' = (e + r x) / k
+		s := new(big.Int).Mul(rx, sk.D)
+		s.Add(s, e)
+		s.Mul(s, inv.inverse(k))
+		s.Mod(s, n)
+		if s.Sign() == 0 {
+			continue
+		}
+		proof, err := proveDLEQ(g, k, t, rPrime, r)
+		if err != nil {
+			return nil, err
+		}
+		return &PreSignature{
+			T:      g.Marshal(t),
+			R:      g.Marshal(r),
+			RPrime: g.Marshal(rPrime),
+			S:      s,
+			Proof:  proof,
+		}, nil
+	}
+}
+
+// preSignature decodes the points of pre and returns T, R and r
+func (c *ECDSA) preSignature(g group.Group, pre *PreSignature) (t, r group.Point, rx *big.Int, ok bool) {
+	n := g.Order()
+	if pre == nil || pre.S == nil || pre.S.Sign() <= 0 || pre.S.Cmp(n) >= 0 {
+		return nil, nil, nil, false
+	}
+	if t, ok = point(g, pre.T); !ok {
+		return nil, nil, nil, false
+	}
+	if r, ok = point(g, pre.R); !ok {
+		return nil, nil, nil, false
+	}
+	rx = affineX(g, r)
+	return t, r, rx, rx.Sign() != 0
+}
+
+// PreVerify reports whether pre is a pre-signature of message under pk for
+// the statement T
+func (c *ECDSA) PreVerify(pk *ecdsa.PublicKey, message []byte, T *ecdsa.PublicKey, pre *PreSignature) bool {
+	g, err := c.group()
+	if err != nil {
+		return false
+	}
+	p, ok := c.publicPoint(g, pk)
+	if !ok {
+		return false
+	}
+	statement, ok := c.publicPoint(g, T)
+	if !ok {
+		return false
+	}
+	t, r, rx, ok := c.preSignature(g, pre)
+	if !ok || !g.Equal(t, statement) {
+		return false
+	}
+	rPrime, ok := point(g, pre.RPrime)
+	if !ok {
+		return false
+	}
+	digest, err := c.digest(message)
+	if err != nil {
+		return false
+	}
+	// R' = [e / s']G + [r / s']P
+	n := g.Order()
+	w := new(big.Int).ModInverse(pre.S, n)
+	u1 := bits2int(digest, n.BitLen())
+	u1.Mul(u1, w)
+	u2 := new(big.Int).Mul(rx, w)
+	if !g.Equal(g.Add(g.ScalarBaseMult(u1), g.ScalarMult(p, u2)), rPrime) {
+		return false
+	}
+	return verifyDLEQ(g, &pre.Proof, t, rPrime, r)
+}
+
+// Adapt returns the signature of pre with the witness t of its statement
+func (c *ECDSA) Adapt(pre *PreSignature, t *big.Int) (*Signature, error) {
+	g, err := c.group()
+	if err != nil {
+		return nil, err
+	}
+	T, _, rx, ok := c.preSignature(g, pre)
+	if !ok {
+		return nil, errPreSignature
+	}
+	n := g.Order()
+	if t.Sign() <= 0 || t.Cmp(n) >= 0 || !g.Equal(g.ScalarBaseMult(t), T) {
+		return nil, errStatement
+	}
+	// the witness is secret, its inverse runs in constant time
+	s := new(big.Int).Mul(pre.S, newMontgomery(n).inverse(t))
+	s.Mod(s, n)
+	return c.lowSignature(&Signature{R: rx, S: s}), nil
+}
+
+// Extract returns the witness of the statement of pre from the signature sig
+// adapted from pre, whether s was negated to low-S or not
+func (c *ECDSA) Extract(sig *Signature, pre *PreSignature) (*big.Int, error) {
+	g, err := c.group()
+	if err != nil {
+		return nil, err
+	}
+	T, _, rx, ok := c.preSignature(g, pre)
+	if !ok || sig == nil || sig.R == nil || sig.S == nil || sig.R.Cmp(rx) != 0 {
+		return nil, errPreSignature
+	}
+	n := g.Order()
+	if sig.S.Sign() <= 0 || sig.S.Cmp(n) >= 0 {
+		return nil, errPreSignature
+	}
+	// t = s' / s, or -s' / s if s was negated
+	t := new(big.Int).Mul(pre.S, new(big.Int).ModInverse(sig.S, n))
+	t.Mod(t, n)
+	if g.Equal(g.ScalarBaseMult(t), T) {
+		return t, nil
+	}
+	t.Sub(n, t)
+	if g.Equal(g.ScalarBaseMult(t), T) {
+		return t, nil
+	}
+	return nil, errPreSignature
+}
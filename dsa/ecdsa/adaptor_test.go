@@ -0,0 +1,132 @@
+package ecdsaUtils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"math/big"
+	"testing"
+
+	"scrypto/ecc/secp256k1Utils"
+)
+
+func TestECDSA_Adaptor(t *testing.T) {
+	for _, c := range []*ECDSA{
+		NewECDSA(elliptic.P256()),
+		NewECDSA(secp256k1Utils.S256()),
+		NewECDSA(secp256k1Utils.S256()).SetLowS(true),
+	} {
+		name := c.Curve().Params().Name
+		alice, _, err := c.GenerateKeys()
+		if err != nil {
+			panic(err)
+		}
+		bob, _, err := c.GenerateKeys()
+		if err != nil {
+			panic(err)
+		}
+		// Alice knows t and both sign their transfer as a pre-signature on T
+		witness, T, err := c.GenerateKeys()
+		if err != nil {
+			panic(err)
+		}
+		toBob, toAlice := []byte("alice pays bob"), []byte("bob pays alice")
+		preA, err := c.PreSign(alice, toBob, T)
+		if err != nil {
+			panic(err)
+		}
+		preB, err := c.PreSign(bob, toAlice, T)
+		if err != nil {
+			panic(err)
+		}
+		if !c.PreVerify(&alice.PublicKey, toBob, T, preA) || !c.PreVerify(&bob.PublicKey, toAlice, T, preB) {
+			t.Fatalf("%s: pre-signature not verified", name)
+		}
+		if c.PreVerify(&bob.PublicKey, toBob, T, preA) || c.PreVerify(&alice.PublicKey, toAlice, T, preA) {
+			t.Errorf("%s: pre-signature verified under another key or message", name)
+		}
+		if c.PreVerify(&alice.PublicKey, toBob, &bob.PublicKey, preA) {
+			t.Errorf("%s: pre-signature verified for another statement", name)
+		}
+		// R must be [k]T for the k of R'
+		bad := *preA
+		bad.R = preB.R
+		if c.PreVerify(&alice.PublicKey, toBob, T, &bad) {
+			t.Errorf("%s: pre-signature with another R verified", name)
+		}
+		bad = *preA
+		bad.Proof.Z = new(big.Int).Add(preA.Proof.Z, big.NewInt(1))
+		if c.PreVerify(&alice.PublicKey, toBob, T, &bad) {
+			t.Errorf("%s: pre-signature with a wrong proof verified", name)
+		}
+		if _, err := c.Adapt(preB, new(big.Int).Add(witness.D, big.NewInt(1))); err == nil {
+			t.Errorf("%s: adapted with a wrong witness", name)
+		}
+		// Alice claims her payment, which reveals t to Bob
+		sigB, err := c.Adapt(preB, witness.D)
+		if err != nil {
+			panic(err)
+		}
+		digest, err := c.digest(toAlice)
+		if err != nil {
+			panic(err)
+		}
+		if !c.Verify(&bob.PublicKey, toAlice, sigB) || !ecdsa.Verify(&bob.PublicKey, digest, sigB.R, sigB.S) {
+			t.Errorf("%s: adapted signature not verified", name)
+		}
+		extracted, err := c.Extract(sigB, preB)
+		if err != nil {
+			panic(err)
+		}
+		if extracted.Cmp(witness.D) != 0 {
+			t.Errorf("%s: extracted witness not match", name)
+		}
+		sigA, err := c.Adapt(preA, extracted)
+		if err != nil {
+			panic(err)
+		}
+		if !c.Verify(&alice.PublicKey, toBob, sigA) {
+			t.Errorf("%s: signature of Bob not verified", name)
+		}
+		// the negated signature also reveals t
+		negated := &Signature{R: sigA.R, S: new(big.Int).Sub(c.Curve().Params().N, sigA.S)}
+		if extracted, err := c.Extract(negated, preA); err != nil || extracted.Cmp(witness.D) != 0 {
+			t.Errorf("%s: witness not extracted from the negated signature", name)
+		}
+		plain, err := c.Sign(bob, toAlice)
+		if err != nil {
+			panic(err)
+		}
+		if _, err := c.Extract(plain, preB); err == nil {
+			t.Errorf("%s: witness extracted from an unrelated signature", name)
+		}
+	}
+}
+
+func TestECDSA_PreSignCurve(t *testing.T) {
+	c := NewECDSA(elliptic.P384())
+	sk, T, err := c.GenerateKeys()
+	if err != nil {
+		panic(err)
+	}
+	if _, err := c.PreSign(sk, []byte("message"), T); err == nil {
+		t.Error("pre-signed on a curve without a group")
+	}
+	c = NewECDSA(elliptic.P256())
+	sk, _, err = c.GenerateKeys()
+	if err != nil {
+		panic(err)
+	}
+	identity := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int), Y: new(big.Int)}
+	if _, err := c.PreSign(sk, []byte("message"), identity); err == nil {
+		t.Error("pre-signed for the identity")
+	}
+	_, T, err = c.GenerateKeys()
+	if err != nil {
+		panic(err)
+	}
+	zero := *sk
+	zero.D = new(big.Int)
+	if _, err := c.PreSign(&zero, []byte("message"), T); err == nil {
+		t.Error("pre-signed with a zero secret")
+	}
+}
@@ -1,100 +1,369 @@
 package ecdsaUtils
 
 import (
-	"bytes"
-	"compress/gzip"
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/x509"
-	"encoding/hex"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
 	"math/big"
-	"strings"
+
+	"scrypto/sutils"
 )
 
-type ecdsaCipher struct {
-	curve elliptic.Curve
+// ECDSA signs raw messages on its curve: the message is hashed with the
+// selected hash (SHA-256 by default), the nonce is random or derived as in
+// RFC 6979, and signatures encode in ASN.1 DER or as fixed-size r || s.
+type ECDSA struct {
+	curve         elliptic.Curve
+	hash          sutils.Hash
+	deterministic bool
+	lowS          bool
 }
 
-func NewECDSA(curve elliptic.Curve) (ecdsaSigner *ecdsaCipher) {
-	ecdsaSigner = &ecdsaCipher{
-		curve: curve,
-	}
-	return ecdsaSigner
+// Signature is an ECDSA signature (r, s)
+type Signature struct {
+	R, S *big.Int
+}
+
+var (
+	errUnavailableHash = errors.New("hash function is unavailable")
+	errCurve           = errors.New("key is not on the curve of the signer")
+	errSignatureSize   = errors.New("signature size not match")
+	errInvalidKey      = errors.New("invalid ecdsa key")
+	errTrailingData    = errors.New("trailing data after the signature")
+)
+
+// NewECDSA returns a signer on curve with SHA-256 and random nonces
+func NewECDSA(curve elliptic.Curve) *ECDSA {
+	return &ECDSA{curve: curve, hash: sutils.SHA256}
+}
+
+// SetHash selects the hash of the messages and of the RFC 6979 HMAC_DRBG
+func (c *ECDSA) SetHash(h sutils.Hash) *ECDSA {
+	c.hash = h
+	return c
+}
+
+// SetDeterministic selects RFC 6979 nonces instead of random ones
+func (c *ECDSA) SetDeterministic(deterministic bool) *ECDSA {
+	c.deterministic = deterministic
+	return c
 }
 
-// Generate Private and Public key-pair
-func (*ecdsaCipher) GenerateKeys() (priKey *ecdsa.PrivateKey, pubKey *ecdsa.PublicKey, err error) {
-	p256 := elliptic.P256()
-	priKey, err = ecdsa.GenerateKey(p256, rand.Reader)
+// SetLowS makes Sign return s <= n/2 and Verify reject s > n/2, as the
+// malleability rule of Bitcoin and Ethereum
+func (c *ECDSA) SetLowS(lowS bool) *ECDSA {
+	c.lowS = lowS
+	return c
+}
+
+// Curve returns the curve of c
+func (c *ECDSA) Curve() elliptic.Curve {
+	return c.curve
+}
+
+// GenerateKeys returns a key pair on the curve of c
+func (c *ECDSA) GenerateKeys() (priKey *ecdsa.PrivateKey, pubKey *ecdsa.PublicKey, err error) {
+	priKey, err = ecdsa.GenerateKey(c.curve, rand.Reader)
 	if err != nil {
 		return nil, nil, err
 	}
-	pubKey = &priKey.PublicKey
-	return priKey, pubKey, nil
+	return priKey, &priKey.PublicKey, nil
 }
 
-// ECDSA Sign
-func (*ecdsaCipher) Sign(privateKeyStr string, messageHash string) (string, error) {
-	privateKeyBytes, err := hex.DecodeString(privateKeyStr)
-	if err != nil {
-		return "", err
+// digest returns the hash of message
+func (c *ECDSA) digest(message []byte) ([]byte, error) {
+	if !c.hash.Available() {
+		return nil, errUnavailableHash
 	}
-	privateKey, err := x509.ParseECPrivateKey(privateKeyBytes)
-	if err != nil {
-		return "", err
+	return sutils.HashBytes(c.hash, message)
+}
+
+// sameCurve reports whether the curve of a key is the curve of c
+func (c *ECDSA) sameCurve(curve elliptic.Curve) bool {
+	return curve != nil && curve.Params().Name == c.curve.Params().Name &&
+		curve.Params().N.Cmp(c.curve.Params().N) == 0
+}
+
+// bits2int returns the leftmost qlen bits of b as an integer (RFC 6979 2.3.2)
+func bits2int(b []byte, qlen int) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if blen := len(b) * 8; blen > qlen {
+		v.Rsh(v, uint(blen-qlen))
 	}
-	r, s, err := ecdsa.Sign(rand.Reader, privateKey, []byte(messageHash))
-	if err != nil {
-		return "", err
-	}
-	rStr, _ := r.MarshalText()
-	sStr, _ := s.MarshalText()
-	var result bytes.Buffer
-	w := gzip.NewWriter(&result)
-	defer w.Close()
-	_, err = w.Write([]byte(string(rStr) + "+" + string(sStr)))
-	if err != nil {
-		return "", err
+	return v
+}
+
+// int2octets returns x in rlen big-endian bytes (RFC 6979 2.3.3)
+func int2octets(x *big.Int, rlen int) []byte {
+	return x.FillBytes(make([]byte, rlen))
+}
+
+// bits2octets returns bits2int(b) mod n in rlen bytes (RFC 6979 2.3.4)
+func bits2octets(b []byte, n *big.Int, rlen int) []byte {
+	z := bits2int(b, n.BitLen())
+	if z.Cmp(n) >= 0 {
+		z.Sub(z, n)
 	}
-	w.Flush()
-	return hex.EncodeToString(result.Bytes()), nil
+	return int2octets(z, rlen)
 }
 
-// ECDSA Verify
-func (*ecdsaCipher) Verify(messageHash, signature string, publicKey string) (bool, error) {
-	publicKeyBytes, err := hex.DecodeString(publicKey)
+// nonceRFC6979 returns the generator of the deterministic nonces of the
+// secret x and of the digest with HMAC_DRBG on the hash of c (RFC 6979 3.2),
+// each call continues the DRBG as in step h.3 and returns the next nonce
+func (c *ECDSA) nonceRFC6979(x *big.Int, digest []byte) func() *big.Int {
+	n := c.curve.Params().N
+	qlen := n.BitLen()
+	rlen := (qlen + 7) / 8
+	mac := func(key []byte, data ...[]byte) []byte {
+		m := hmac.New(c.hash.New, key)
+		for _, d := range data {
+			m.Write(d)
+		}
+		return m.Sum(nil)
+	}
+	bx := append(int2octets(x, rlen), bits2octets(digest, n, rlen)...)
+	hlen := c.hash.Size()
+	v := make([]byte, hlen)
+	for i := range v {
+		v[i] = 0x01
+	}
+	k := make([]byte, hlen)
+	k = mac(k, v, []byte{0x00}, bx)
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, bx)
+	v = mac(k, v)
+	started := false
+	return func() *big.Int {
+		for {
+			if started {
+				k = mac(k, v, []byte{0x00})
+				v = mac(k, v)
+			}
+			started = true
+			var t []byte
+			for len(t) < rlen {
+				v = mac(k, v)
+				t = append(t, v...)
+			}
+			nonce := bits2int(t[:rlen], qlen)
+			if nonce.Sign() > 0 && nonce.Cmp(n) < 0 {
+				return nonce
+			}
+		}
+	}
+}
+
+// stdlibCurve reports whether c is on a curve of crypto/elliptic, whose
+// crypto/ecdsa signatures run in constant time
+func (c *ECDSA) stdlibCurve() bool {
+	switch c.curve {
+	case elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521():
+		return true
+	}
+	return false
+}
+
+// lowSignature returns sig with s replaced by n - s above n/2 when c has the
+// low-s rule
+func (c *ECDSA) lowSignature(sig *Signature) *Signature {
+	n := c.curve.Params().N
+	if c.lowS && sig.S.Cmp(halfOrder(n)) > 0 {
+		sig.S.Sub(n, sig.S)
+	}
+	return sig
+}
+
+// Sign signs message with sk, which must be on the curve of c. Random
+// nonces on the curves of crypto/elliptic sign with crypto/ecdsa, RFC 6979
+// nonces and other curves sign here with a constant time inversion of the
+// nonce.
+func (c *ECDSA) Sign(sk *ecdsa.PrivateKey, message []byte) (*Signature, error) {
+	if sk == nil || sk.D == nil {
+		return nil, errInvalidKey
+	}
+	if !c.sameCurve(sk.Curve) {
+		return nil, errCurve
+	}
+	n := c.curve.Params().N
+	if sk.D.Sign() <= 0 || sk.D.Cmp(n) >= 0 {
+		return nil, errInvalidKey
+	}
+	digest, err := c.digest(message)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+	if !c.deterministic && c.stdlibCurve() && sk.Curve == c.curve {
+		r, s, err := ecdsa.Sign(rand.Reader, sk, digest)
+		if err != nil {
+			return nil, err
+		}
+		return c.lowSignature(&Signature{R: r, S: s}), nil
+	}
+	e := bits2int(digest, n.BitLen())
+	inv := newMontgomery(n)
+	var nonces func() *big.Int
+	if c.deterministic {
+		nonces = c.nonceRFC6979(sk.D, digest)
+	}
+	for {
+		var k *big.Int
+		if nonces != nil {
+			// r = 0 or s = 0 takes the next nonce of the same DRBG
+			k = nonces()
+		} else {
+			k, err = rand.Int(rand.Reader, new(big.Int).Sub(n, big.NewInt(1)))
+			if err != nil {
+				return nil, err
+			}
+			k.Add(k, big.NewInt(1))
+		}
+		// r = x([k]G) mod n, s = (e + r d) / k mod n
+		x, _ := c.curve.ScalarBaseMult(k.Bytes())
+		r := x.Mod(x, n)
+		if r.Sign() == 0 {
+			continue
+		}
+		s := new(big.Int).Mul(r, sk.D)
+		s.Add(s, e)
+		s.Mul(s, inv.inverse(k))
+		s.Mod(s, n)
+		if s.Sign() == 0 {
+			continue
+		}
+		return c.lowSignature(&Signature{R: r, S: s}), nil
+	}
+}
+
+// halfOrder returns n / 2
+func halfOrder(n *big.Int) *big.Int {
+	return new(big.Int).Rsh(n, 1)
+}
+
+// Verify reports whether sig is a signature of message under pk, pk must be
+// on the curve of c
+func (c *ECDSA) Verify(pk *ecdsa.PublicKey, message []byte, sig *Signature) bool {
+	if pk == nil || sig == nil || sig.R == nil || sig.S == nil || !c.sameCurve(pk.Curve) {
+		return false
 	}
-	pubKey, _ := x509.ParsePKIXPublicKey(publicKeyBytes)
-	sigBytes, err := hex.DecodeString(signature)
+	if c.lowS && sig.S.Cmp(halfOrder(c.curve.Params().N)) > 0 {
+		return false
+	}
+	digest, err := c.digest(message)
 	if err != nil {
-		return false, err
+		return false
 	}
-	reader, err := gzip.NewReader(bytes.NewBuffer(sigBytes))
+	return ecdsa.Verify(pk, digest, sig.R, sig.S)
+}
+
+// DER returns the ASN.1 DER encoding SEQUENCE { r INTEGER, s INTEGER }
+func (sig *Signature) DER() ([]byte, error) {
+	return asn1.Marshal(struct{ R, S *big.Int }{sig.R, sig.S})
+}
+
+// ParseDER decodes a DER signature
+func ParseDER(data []byte) (*Signature, error) {
+	var v struct{ R, S *big.Int }
+	rest, err := asn1.Unmarshal(data, &v)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errTrailingData
+	}
+	if v.R.Sign() <= 0 || v.S.Sign() <= 0 {
+		return nil, errors.New("signature values should be positive")
+	}
+	return &Signature{R: v.R, S: v.S}, nil
+}
+
+// scalarSize returns the size of an encoded integer modulo n
+func (c *ECDSA) scalarSize() int {
+	return (c.curve.Params().N.BitLen() + 7) / 8
+}
+
+// Fixed returns r || s, both in the size of the order of the curve of c
+func (c *ECDSA) Fixed(sig *Signature) []byte {
+	size := c.scalarSize()
+	res := make([]byte, 2*size)
+	sig.R.FillBytes(res[:size])
+	sig.S.FillBytes(res[size:])
+	return res
+}
+
+// ParseFixed decodes an r || s signature of the curve of c
+func (c *ECDSA) ParseFixed(data []byte) (*Signature, error) {
+	size := c.scalarSize()
+	if len(data) != 2*size {
+		return nil, errSignatureSize
 	}
-	defer reader.Close()
-	buf := make([]byte, 1024)
-	count, err := reader.Read(buf)
+	return &Signature{
+		R: new(big.Int).SetBytes(data[:size]),
+		S: new(big.Int).SetBytes(data[size:]),
+	}, nil
+}
+
+// pemOrDER returns the DER bytes of data and its PEM type, "" for DER input
+func pemOrDER(data []byte) ([]byte, string) {
+	if block, _ := pem.Decode(data); block != nil {
+		return block.Bytes, block.Type
+	}
+	return data, ""
+}
+
+// ParsePrivateKey decodes a private key in PKCS#8 or SEC 1, PEM or DER
+func ParsePrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
+	der, typ := pemOrDER(data)
+	if typ == "" || typ == "PRIVATE KEY" {
+		if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+			sk, ok := key.(*ecdsa.PrivateKey)
+			if !ok {
+				return nil, errInvalidKey
+			}
+			return sk, nil
+		}
+	}
+	if typ == "" || typ == "EC PRIVATE KEY" {
+		return x509.ParseECPrivateKey(der)
+	}
+	return nil, errInvalidKey
+}
+
+// ParsePublicKey decodes a PKIX public key, PEM or DER
+func ParsePublicKey(data []byte) (*ecdsa.PublicKey, error) {
+	der, typ := pemOrDER(data)
+	if typ != "" && typ != "PUBLIC KEY" {
+		return nil, errInvalidKey
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	rsArr := strings.Split(string(buf[:count]), "+")
-	if len(rsArr) != 2 {
-		return false, err
+	pk, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errInvalidKey
 	}
-	var r, s big.Int
-	err = r.UnmarshalText([]byte(rsArr[0]))
+	return pk, nil
+}
+
+// MarshalPrivateKey encodes sk in a PKCS#8 PEM block
+func MarshalPrivateKey(sk *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(sk)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	err = s.UnmarshalText([]byte(rsArr[1]))
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// MarshalPublicKey encodes pk in a PKIX PEM block
+func MarshalPublicKey(pk *ecdsa.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(pk)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	result := ecdsa.Verify(pubKey.(*ecdsa.PublicKey), []byte(messageHash), &r, &s)
-	return result, nil
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
 }
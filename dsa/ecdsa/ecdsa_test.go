@@ -0,0 +1,314 @@
+package ecdsaUtils
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"testing"
+
+	"scrypto/sutils"
+)
+
+func hexInt(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("bad hex integer " + s)
+	}
+	return v
+}
+
+// rfc6979Key is the P-256 key of RFC 6979 A.2.5
+func rfc6979Key() *ecdsa.PrivateKey {
+	sk := &ecdsa.PrivateKey{D: hexInt("C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721")}
+	sk.Curve = elliptic.P256()
+	sk.X, sk.Y = sk.Curve.ScalarBaseMult(sk.D.Bytes())
+	return sk
+}
+
+func TestECDSA_SignRFC6979(t *testing.T) {
+	sk := rfc6979Key()
+	if sk.X.Cmp(hexInt("60FED4BA255A9D31C961EB74C6356D68C049B8923B61FA6CE669622E60F29FB6")) != 0 {
+		panic("bad public key")
+	}
+	vectors := []struct {
+		hash    sutils.Hash
+		message string
+		k, r, s string
+	}{
+		{
+			sutils.SHA256, "sample",
+			"A6E3C57DD01ABE90086538398355DD4C3B17AA873382B0F24D6129493D8AAD60",
+			"EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716",
+			"F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8",
+		},
+		{
+			sutils.SHA256, "test",
+			"D16B6AE827F17175E040871A1C7EC3500192C4C92677336EC2537ACAEE0008E0",
+			"F1ABB023518351CD71D881567B1EA663ED3EFCF6C5132B354F28D3B0B7D38367",
+			"019F4113742A2B14BD25926B49C649155F267E60D3814B4C0CC84250E46F0083",
+		},
+		{
+			sutils.SHA512, "sample",
+			"5FA81C63109BADB88C1F367B47DA606DA28CAD69AA22C4FE6AD7DF73A7173AA5",
+			"8496A60B5E9B47C825488827E0495B0E3FA109EC4568FD3F8D1097678EB97F00",
+			"2362AB1ADBE2B8ADF9CB9EDAB740EA6049C028114F2460F96554F61FAE3302FE",
+		},
+	}
+	for _, v := range vectors {
+		c := NewECDSA(elliptic.P256()).SetHash(v.hash).SetDeterministic(true)
+		digest, err := sutils.HashBytes(v.hash, []byte(v.message))
+		if err != nil {
+			panic(err)
+		}
+		nonces := c.nonceRFC6979(sk.D, digest)
+		if k := nonces(); k.Cmp(hexInt(v.k)) != 0 {
+			t.Errorf("%s %q: nonce not match", v.hash, v.message)
+		}
+		// a retry continues the DRBG instead of returning the same nonce
+		if k := nonces(); k.Cmp(hexInt(v.k)) == 0 || k.Cmp(c.nonceRFC6979(sk.D, digest)()) == 0 {
+			t.Errorf("%s %q: retry returned the first nonce", v.hash, v.message)
+		}
+		sig, err := c.Sign(sk, []byte(v.message))
+		if err != nil {
+			panic(err)
+		}
+		if sig.R.Cmp(hexInt(v.r)) != 0 || sig.S.Cmp(hexInt(v.s)) != 0 {
+			t.Errorf("%s %q: signature not match", v.hash, v.message)
+		}
+		if !c.Verify(&sk.PublicKey, []byte(v.message), sig) {
+			t.Errorf("%s %q: signature not verified", v.hash, v.message)
+		}
+	}
+}
+
+func TestECDSA_NonceRFC6979Retry(t *testing.T) {
+	// RFC 6979 A.1.2: with the 163 bits order q, the first candidate of
+	// "sample" is above q and the DRBG continues as in step h.3
+	q := hexInt("4000000000000000000020108A2E0CC0D99F8A5EF")
+	x := hexInt("09A4D6792295A7F730FC3F2B49CBC0F62E862272F")
+	c := NewECDSA(&elliptic.CurveParams{Name: "rfc6979-a1", N: q})
+	digest, err := sutils.HashBytes(sutils.SHA256, []byte("sample"))
+	if err != nil {
+		panic(err)
+	}
+	if k := c.nonceRFC6979(x, digest)(); k.Cmp(hexInt("23AF4074C90A02B3FE61D286D5C87F425E6BDD81B")) != 0 {
+		t.Errorf("nonce after a retry not match: %X", k)
+	}
+}
+
+func TestECDSA_Sign(t *testing.T) {
+	for _, c := range []*ECDSA{
+		NewECDSA(elliptic.P256()),
+		NewECDSA(elliptic.P384()).SetHash(sutils.SHA384),
+		NewECDSA(elliptic.P521()).SetHash(sutils.SHA512).SetDeterministic(true),
+		NewECDSA(elliptic.P224()).SetLowS(true),
+	} {
+		name := c.Curve().Params().Name
+		sk, pk, err := c.GenerateKeys()
+		if err != nil {
+			panic(err)
+		}
+		if sk.Curve != c.Curve() {
+			t.Errorf("%s: key generated on another curve", name)
+		}
+		m := []byte("message")
+		sig, err := c.Sign(sk, m)
+		if err != nil {
+			panic(err)
+		}
+		if !c.Verify(pk, m, sig) || c.Verify(pk, []byte("another message"), sig) {
+			t.Errorf("%s: wrong verification", name)
+		}
+		// the standard library verifies the signatures of the digest
+		digest, err := sutils.HashBytes(c.hash, m)
+		if err != nil {
+			panic(err)
+		}
+		if !ecdsa.Verify(pk, digest, sig.R, sig.S) {
+			t.Errorf("%s: signature not verified by crypto/ecdsa", name)
+		}
+		der, err := sig.DER()
+		if err != nil {
+			panic(err)
+		}
+		fromDER, err := ParseDER(der)
+		if err != nil {
+			panic(err)
+		}
+		fixed := c.Fixed(sig)
+		if len(fixed) != 2*c.scalarSize() {
+			t.Errorf("%s: fixed size %d", name, len(fixed))
+		}
+		fromFixed, err := c.ParseFixed(fixed)
+		if err != nil {
+			panic(err)
+		}
+		if !c.Verify(pk, m, fromDER) || !c.Verify(pk, m, fromFixed) {
+			t.Errorf("%s: decoded signature not verified", name)
+		}
+		if _, err := ParseDER(append(der, 0)); err == nil {
+			t.Errorf("%s: trailing data accepted", name)
+		}
+		if _, err := c.ParseFixed(fixed[1:]); err == nil {
+			t.Errorf("%s: short signature accepted", name)
+		}
+	}
+}
+
+func TestECDSA_SignInvalidKey(t *testing.T) {
+	for _, c := range []*ECDSA{
+		NewECDSA(elliptic.P256()),
+		NewECDSA(elliptic.P256()).SetDeterministic(true),
+	} {
+		sk, _, err := c.GenerateKeys()
+		if err != nil {
+			panic(err)
+		}
+		n := c.Curve().Params().N
+		for _, d := range []*big.Int{big.NewInt(0), new(big.Int).Neg(sk.D), n, new(big.Int).Add(n, sk.D)} {
+			bad := *sk
+			bad.D = d
+			if _, err := c.Sign(&bad, []byte("message")); err == nil {
+				t.Errorf("secret %X signed", d)
+			}
+		}
+		if _, err := c.Sign(nil, []byte("message")); err == nil {
+			t.Error("nil key signed")
+		}
+	}
+}
+
+func TestECDSA_Deterministic(t *testing.T) {
+	c := NewECDSA(elliptic.P256()).SetDeterministic(true)
+	sk, _, err := c.GenerateKeys()
+	if err != nil {
+		panic(err)
+	}
+	a, err := c.Sign(sk, []byte("message"))
+	if err != nil {
+		panic(err)
+	}
+	b, err := c.Sign(sk, []byte("message"))
+	if err != nil {
+		panic(err)
+	}
+	if a.R.Cmp(b.R) != 0 || a.S.Cmp(b.S) != 0 {
+		t.Error("deterministic signatures differ")
+	}
+	c.SetDeterministic(false)
+	b, err = c.Sign(sk, []byte("message"))
+	if err != nil {
+		panic(err)
+	}
+	if a.R.Cmp(b.R) == 0 {
+		t.Error("random nonce is the deterministic one")
+	}
+}
+
+func TestECDSA_LowS(t *testing.T) {
+	c := NewECDSA(elliptic.P256())
+	sk, pk, err := c.GenerateKeys()
+	if err != nil {
+		panic(err)
+	}
+	n := elliptic.P256().Params().N
+	c.SetLowS(true)
+	for i := 0; i < 20; i++ {
+		sig, err := c.Sign(sk, []byte("message"))
+		if err != nil {
+			panic(err)
+		}
+		if sig.S.Cmp(halfOrder(n)) > 0 {
+			t.Fatal("high s signed")
+		}
+		// (r, n - s) is valid but malleable
+		high := &Signature{R: sig.R, S: new(big.Int).Sub(n, sig.S)}
+		if c.Verify(pk, []byte("message"), high) {
+			t.Error("high s accepted")
+		}
+		c.SetLowS(false)
+		if !c.Verify(pk, []byte("message"), high) {
+			t.Error("high s rejected without the low-s rule")
+		}
+		c.SetLowS(true)
+	}
+}
+
+func TestECDSA_Curve(t *testing.T) {
+	p384 := NewECDSA(elliptic.P384())
+	sk, pk, err := NewECDSA(elliptic.P256()).GenerateKeys()
+	if err != nil {
+		panic(err)
+	}
+	if _, err := p384.Sign(sk, []byte("message")); err == nil {
+		t.Error("P-256 key signed on P-384")
+	}
+	sig, err := NewECDSA(elliptic.P256()).Sign(sk, []byte("message"))
+	if err != nil {
+		panic(err)
+	}
+	if p384.Verify(pk, []byte("message"), sig) {
+		t.Error("P-256 key verified on P-384")
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	c := NewECDSA(elliptic.P256())
+	sk, pk, err := c.GenerateKeys()
+	if err != nil {
+		panic(err)
+	}
+	pkcs8, err := MarshalPrivateKey(sk)
+	if err != nil {
+		panic(err)
+	}
+	sec1, err := x509.MarshalECPrivateKey(sk)
+	if err != nil {
+		panic(err)
+	}
+	pkcs8DER, _ := pem.Decode(pkcs8)
+	inputs := [][]byte{
+		pkcs8,
+		pkcs8DER.Bytes,
+		sec1,
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1}),
+	}
+	for i, data := range inputs {
+		got, err := ParsePrivateKey(data)
+		if err != nil {
+			t.Errorf("input %d: %v", i, err)
+			continue
+		}
+		if got.D.Cmp(sk.D) != 0 || got.Curve != sk.Curve {
+			t.Errorf("input %d: private key not match", i)
+		}
+	}
+	if _, err := ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: sec1})); err == nil {
+		t.Error("certificate block accepted")
+	}
+
+	pub, err := MarshalPublicKey(pk)
+	if err != nil {
+		panic(err)
+	}
+	block, _ := pem.Decode(pub)
+	for i, data := range [][]byte{pub, block.Bytes} {
+		got, err := ParsePublicKey(data)
+		if err != nil {
+			t.Errorf("public input %d: %v", i, err)
+			continue
+		}
+		if got.X.Cmp(pk.X) != 0 || got.Y.Cmp(pk.Y) != 0 {
+			t.Errorf("public input %d: public key not match", i)
+		}
+	}
+	if _, err := ParsePublicKey(pkcs8); err == nil {
+		t.Error("private key parsed as a public key")
+	}
+	if bytes.Contains(pub, []byte("PRIVATE")) {
+		t.Error("public key encoding shows a private block")
+	}
+}
@@ -0,0 +1,119 @@
+package ecdsaUtils
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+// montgomery is the Montgomery arithmetic modulo an odd public n on
+// fixed-size little-endian 64 bits limbs. Its products have no branch nor
+// memory access that depends on the values, they back the inversion of the
+// secret nonces.
+type montgomery struct {
+	n     []uint64
+	n0inv uint64   // -n^-1 mod 2^64
+	rr    []uint64 // R^2 mod n, R = 2^(64 len(n))
+}
+
+// newMontgomery returns the arithmetic modulo the odd n
+func newMontgomery(n *big.Int) *montgomery {
+	size := (n.BitLen() + 63) / 64
+	m := &montgomery{n: toLimbs(n, size)}
+	// Newton iteration doubles the correct low bits of n^-1 each step
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - m.n[0]*inv
+	}
+	m.n0inv = -inv
+	rr := new(big.Int).Lsh(big.NewInt(1), uint(128*size))
+	m.rr = toLimbs(rr.Mod(rr, n), size)
+	return m
+}
+
+// toLimbs returns v < 2^(64 size) in size little-endian limbs
+func toLimbs(v *big.Int, size int) []uint64 {
+	b := v.FillBytes(make([]byte, 8*size))
+	res := make([]uint64, size)
+	for i := range res {
+		res[i] = binary.BigEndian.Uint64(b[8*(size-1-i):])
+	}
+	return res
+}
+
+// fromLimbs returns the integer of little-endian limbs
+func fromLimbs(a []uint64) *big.Int {
+	b := make([]byte, 8*len(a))
+	for i, l := range a {
+		binary.BigEndian.PutUint64(b[8*(len(a)-1-i):], l)
+	}
+	return new(big.Int).SetBytes(b)
+}
+
+// mul returns a b / R mod n for a, b < n (CIOS multiplication)
+func (m *montgomery) mul(a, b []uint64) []uint64 {
+	size := len(m.n)
+	t := make([]uint64, size+2)
+	for i := 0; i < size; i++ {
+		var c, cc uint64
+		for j := 0; j < size; j++ {
+			hi, lo := bits.Mul64(a[j], b[i])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j], c = lo, hi
+		}
+		t[size], cc = bits.Add64(t[size], c, 0)
+		t[size+1] = cc
+
+		// t + q n is divisible by 2^64 for q = t[0] n0inv
+		q := t[0] * m.n0inv
+		hi, lo := bits.Mul64(q, m.n[0])
+		_, cc = bits.Add64(lo, t[0], 0)
+		c = hi + cc
+		for j := 1; j < size; j++ {
+			hi, lo = bits.Mul64(q, m.n[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], c = lo, hi
+		}
+		t[size-1], cc = bits.Add64(t[size], c, 0)
+		t[size] = t[size+1] + cc
+	}
+
+	// t < 2n, subtract n unless it borrows from the top limb
+	res := make([]uint64, size)
+	var borrow uint64
+	for j := 0; j < size; j++ {
+		res[j], borrow = bits.Sub64(t[j], m.n[j], borrow)
+	}
+	_, borrow = bits.Sub64(t[size], 0, borrow)
+	keep := -borrow
+	for j := range res {
+		res[j] = t[j]&keep | res[j]&^keep
+	}
+	return res
+}
+
+// inverse returns k^-1 mod n for 0 < k < n as k^(n-2), n must be prime. The
+// square and multiply runs on the bits of the public exponent only, so its
+// time does not depend on k.
+func (m *montgomery) inverse(k *big.Int) *big.Int {
+	size := len(m.n)
+	x := m.mul(toLimbs(k, size), m.rr)
+	one := make([]uint64, size)
+	one[0] = 1
+	r := m.mul(one, m.rr)
+	e := fromLimbs(m.n)
+	e.Sub(e, big.NewInt(2))
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		r = m.mul(r, r)
+		if e.Bit(i) == 1 {
+			r = m.mul(r, x)
+		}
+	}
+	return fromLimbs(m.mul(r, one))
+}
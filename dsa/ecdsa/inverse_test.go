@@ -0,0 +1,37 @@
+package ecdsaUtils
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestMontgomery_Inverse(t *testing.T) {
+	secp256k1N := hexInt("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141")
+	for _, n := range []*big.Int{
+		elliptic.P224().Params().N,
+		elliptic.P256().Params().N,
+		elliptic.P384().Params().N,
+		elliptic.P521().Params().N,
+		secp256k1N,
+	} {
+		m := newMontgomery(n)
+		edges := []*big.Int{big.NewInt(1), big.NewInt(2), new(big.Int).Sub(n, big.NewInt(1))}
+		for i := 0; i < 20; i++ {
+			k, err := rand.Int(rand.Reader, n)
+			if err != nil {
+				panic(err)
+			}
+			if k.Sign() == 0 {
+				continue
+			}
+			edges = append(edges, k)
+		}
+		for _, k := range edges {
+			if m.inverse(k).Cmp(new(big.Int).ModInverse(k, n)) != 0 {
+				t.Errorf("wrong inverse of %X modulo %X", k, n)
+			}
+		}
+	}
+}
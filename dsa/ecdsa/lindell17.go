@@ -0,0 +1,456 @@
+package ecdsaUtils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"math/big"
+
+	"scrypto/ecc/group"
+	"scrypto/ecc/p256Utils"
+	"scrypto/encryption/paillier"
+	"scrypto/proof/transcript"
+)
+
+// Two-party ECDSA of Lindell (https://eprint.iacr.org/2017/552) on P-256: the
+// key is x = x1 x2 mod q, party 1 holds x1 and a Paillier key, party 2 holds
+// x2 and the encryption c_key = Enc(x1). To sign, the parties agree on the
+// nonce R = [k1 k2]G, party 2 computes Enc(k2^{-1} (m + r x1 x2)) + Enc(rho q)
+// under the key of party 1, which decrypts it and divides by k1.
+//
+// Key generation: party 1 commits to Q1 = [x1]G and its proof, party 2 sends
+// Q2 = [x2]G and its proof, party 1 opens its commitment and sends c_key.
+// Signing: party 1 sends R1 = [k1]G and its proof, party 2 answers R2 = [k2]G,
+// its proof and the encrypted s'. Each point comes with a Schnorr proof of
+// knowledge of its discrete logarithm, bound to the session id of the key
+// generation or to the joint key for the signatures.
+//
+// Signing deviates from the paper: party 1 sends R1 and its proof in the
+// clear instead of a commitment opened after R2, so signing takes two
+// messages and party 2 sees R1 before it picks k2. The simulation of the
+// paper against a malicious party 2 extracts k2 and then opens the
+// commitment to the R1 that gives the R of the ideal signature, it does not
+// apply here: party 2 can choose k2, and so R, depending on R1, and the
+// security of the signatures against a malicious party 2 is not covered by
+// the proof of the paper.
+//
+// With c_key, party 1 proves that its Paillier modulus is coprime to phi(N)
+// and that c_key encrypts the discrete logarithm of Q1 in a small range, so a
+// malicious party 1 cannot learn bits of x2 through failed signatures. Party 1
+// verifies every signature before returning it.
+
+const (
+	// MinPaillierBits is the minimal size of the Paillier modulus of party 1
+	MinPaillierBits = 2048
+)
+
+var (
+	errDLogProof     = errors.New("invalid discrete logarithm proof")
+	errCommitment    = errors.New("commitment not match")
+	errInvalidPoint  = errors.New("invalid P-256 point")
+	errPaillierKey   = errors.New("paillier modulus is too small")
+	errPaillierProof = errors.New("invalid paillier modulus proof")
+	errEncryptedKey  = errors.New("invalid encrypted key share")
+	errPartialResult = errors.New("signature of the parties not verified")
+)
+
+// DLogProof is a Schnorr proof of knowledge of x such that Q = [x]G
+type DLogProof struct {
+	T []byte
+	Z *big.Int
+}
+
+// KeyGenMsg1 is sent by party 1: a commitment to Q1 and to its proof
+type KeyGenMsg1 struct {
+	Commitment []byte
+}
+
+// KeyGenMsg2 is sent by party 2: Q2 = [x2]G and its proof
+type KeyGenMsg2 struct {
+	Q2    []byte
+	Proof DLogProof
+}
+
+// KeyGenMsg3 is sent by party 1: the opening of its commitment, its Paillier
+// modulus and the encryption of x1 with their proofs
+type KeyGenMsg3 struct {
+	Q1                []byte
+	Proof             DLogProof
+	Salt              []byte
+	PaillierN         *big.Int
+	PaillierProof     []*big.Int
+	EncryptedKey      *big.Int
+	EncryptedKeyProof *EncryptedKeyProof
+}
+
+// SignMsg1 is sent by party 1: R1 = [k1]G and its proof
+type SignMsg1 struct {
+	R1    []byte
+	Proof DLogProof
+}
+
+// SignMsg2 is sent by party 2: R2 = [k2]G, its proof and the encrypted s'
+type SignMsg2 struct {
+	R2              []byte
+	Proof           DLogProof
+	EncryptedResult *big.Int
+}
+
+// KeyGenParty1 is the state of party 1 during the key generation
+type KeyGenParty1 struct {
+	session []byte
+	x1      *big.Int
+	q1      []byte
+	proof   DLogProof
+	salt    []byte
+}
+
+// KeyGenParty2 is the state of party 2 during the key generation
+type KeyGenParty2 struct {
+	session    []byte
+	x2         *big.Int
+	commitment []byte
+}
+
+// Party1Key is the key share of party 1
+type Party1Key struct {
+	x1       *big.Int
+	paillier *paillier.PrivateKey
+	pk       *ecdsa.PublicKey
+}
+
+// Party2Key is the key share of party 2
+type Party2Key struct {
+	x2           *big.Int
+	paillier     *paillier.PublicKey
+	encryptedKey *big.Int
+	pk           *ecdsa.PublicKey
+}
+
+// SignParty1 is the state of party 1 during a signature
+type SignParty1 struct {
+	key     *Party1Key
+	message []byte
+	k1      *big.Int
+}
+
+// decodePoint decodes an uncompressed P-256 point, the identity is rejected
+func decodePoint(data []byte) (*p256Utils.CurvePoint, error) {
+	x, y := elliptic.Unmarshal(elliptic.P256(), data)
+	if x == nil {
+		return nil, errInvalidPoint
+	}
+	return &p256Utils.CurvePoint{Curve: elliptic.P256(), X: x, Y: y}, nil
+}
+
+// dlogChallenge returns the challenge of a proof labelled label in session
+// for Q and T
+func dlogChallenge(label string, session []byte, q, t *p256Utils.CurvePoint) *big.Int {
+	g := group.P256()
+	tr := transcript.New("lindell17-dlog")
+	tr.AppendMessage("role", []byte(label))
+	tr.AppendMessage("session", session)
+	tr.AppendPoint("Q", g, q)
+	tr.AppendPoint("T", g, t)
+	return tr.ChallengeScalar("c", p256Utils.N)
+}
+
+// proveDLog proves the knowledge of x such that Q = [x]G, label binds the
+// proof to its step of the protocol and session to the run of the protocol
+func proveDLog(label string, session []byte, x *big.Int, q *p256Utils.CurvePoint) (DLogProof, error) {
+	k, err := group.RandomScalar(group.P256(), nil)
+	if err != nil {
+		return DLogProof{}, err
+	}
+	t := p256Utils.ScalarBaseMult(k)
+	// z = k + c x
+	z := new(big.Int).Mul(dlogChallenge(label, session, q, t), x)
+	z.Add(z, k)
+	z.Mod(z, p256Utils.N)
+	return DLogProof{T: p256Utils.Marshal(t), Z: z}, nil
+}
+
+// verify reports whether p proves the knowledge of the discrete logarithm of Q
+// in session
+func (p *DLogProof) verify(label string, session []byte, q *p256Utils.CurvePoint) bool {
+	t, err := decodePoint(p.T)
+	if err != nil || p.Z == nil || p.Z.Sign() < 0 || p.Z.Cmp(p256Utils.N) >= 0 {
+		return false
+	}
+	// [z]G = T + [c]Q
+	left := p256Utils.ScalarBaseMult(p.Z)
+	right := p256Utils.ScalarAdd(t, p256Utils.ScalarMult(q, dlogChallenge(label, session, q, t)))
+	return p256Utils.IsEqual(left, right)
+}
+
+// keyGenCommitment returns SHA-256(salt || Q1 || T || z)
+func keyGenCommitment(salt, q1 []byte, proof *DLogProof) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write(q1)
+	h.Write(proof.T)
+	h.Write(proof.Z.FillBytes(make([]byte, 32)))
+	return h.Sum(nil)
+}
+
+// paillierContext binds the proof of the Paillier modulus to Q1
+func paillierContext(q1 []byte) []byte {
+	return append([]byte("lindell17-paillier"), q1...)
+}
+
+// jointPublicKey returns [x]Q
+func jointPublicKey(x *big.Int, q *p256Utils.CurvePoint) (*ecdsa.PublicKey, error) {
+	pk := p256Utils.ScalarMult(q, x)
+	if !p256Utils.IsOnCurve(pk) {
+		return nil, errInvalidPoint
+	}
+	return pk, nil
+}
+
+// signSession returns the session of the proofs of a signature, the joint key
+func signSession(pk *ecdsa.PublicKey) []byte {
+	return p256Utils.Marshal(pk)
+}
+
+// NewKeyGenParty1 starts the key generation of party 1 in session, an id of
+// the key generation that both parties agree on and never reuse
+func NewKeyGenParty1(session []byte) (*KeyGenParty1, *KeyGenMsg1, error) {
+	x1, err := group.RandomScalar(group.P256(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	q1 := p256Utils.ScalarBaseMult(x1)
+	session = append([]byte{}, session...)
+	proof, err := proveDLog("keygen-party1", session, x1, q1)
+	if err != nil {
+		return nil, nil, err
+	}
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, err
+	}
+	p := &KeyGenParty1{session: session, x1: x1, q1: p256Utils.Marshal(q1), proof: proof, salt: salt}
+	return p, &KeyGenMsg1{Commitment: keyGenCommitment(salt, p.q1, &proof)}, nil
+}
+
+// NewKeyGenParty2 answers the first message of party 1 in session, the id
+// of the key generation given to NewKeyGenParty1
+func NewKeyGenParty2(session []byte, msg *KeyGenMsg1) (*KeyGenParty2, *KeyGenMsg2, error) {
+	x2, err := group.RandomScalar(group.P256(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	q2 := p256Utils.ScalarBaseMult(x2)
+	session = append([]byte{}, session...)
+	proof, err := proveDLog("keygen-party2", session, x2, q2)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := &KeyGenParty2{session: session, x2: x2, commitment: append([]byte{}, msg.Commitment...)}
+	return p, &KeyGenMsg2{Q2: p256Utils.Marshal(q2), Proof: proof}, nil
+}
+
+// Finish checks the message of party 2 and returns the key share of party 1
+// with a fresh Paillier key of paillierBits bits, and the last message
+func (p *KeyGenParty1) Finish(msg *KeyGenMsg2, paillierBits int) (*Party1Key, *KeyGenMsg3, error) {
+	if paillierBits < MinPaillierBits {
+		return nil, nil, errPaillierKey
+	}
+	q2, err := decodePoint(msg.Q2)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !msg.Proof.verify("keygen-party2", p.session, q2) {
+		return nil, nil, errDLogProof
+	}
+	pk, err := jointPublicKey(p.x1, q2)
+	if err != nil {
+		return nil, nil, err
+	}
+	sk, err := paillier.GenerateKey(nil, paillierBits)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyProof, err := sk.ProveKey(paillierContext(p.q1))
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := sk.RandomNonce(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := sk.EncryptWithNonce(p.x1, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	encProof, err := proveEncryptedKey(&sk.PublicKey, p.x1, r, c, p.q1)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := &Party1Key{x1: p.x1, paillier: sk, pk: pk}
+	return key, &KeyGenMsg3{
+		Q1:                p.q1,
+		Proof:             p.proof,
+		Salt:              p.salt,
+		PaillierN:         new(big.Int).Set(sk.N),
+		PaillierProof:     keyProof,
+		EncryptedKey:      c,
+		EncryptedKeyProof: encProof,
+	}, nil
+}
+
+// Finish checks the opening of party 1 and returns the key share of party 2
+func (p *KeyGenParty2) Finish(msg *KeyGenMsg3) (*Party2Key, error) {
+	z := msg.Proof.Z
+	if z == nil || z.Sign() < 0 || z.Cmp(p256Utils.N) >= 0 || msg.PaillierN == nil {
+		return nil, errDLogProof
+	}
+	if subtle.ConstantTimeCompare(keyGenCommitment(msg.Salt, msg.Q1, &msg.Proof), p.commitment) != 1 {
+		return nil, errCommitment
+	}
+	q1, err := decodePoint(msg.Q1)
+	if err != nil {
+		return nil, err
+	}
+	if !msg.Proof.verify("keygen-party1", p.session, q1) {
+		return nil, errDLogProof
+	}
+	if msg.PaillierN.BitLen() < MinPaillierBits || msg.PaillierN.Bit(0) == 0 {
+		return nil, errPaillierKey
+	}
+	pub := paillier.NewPublicKey(msg.PaillierN)
+	if !pub.VerifyKey(paillierContext(msg.Q1), msg.PaillierProof) {
+		return nil, errPaillierProof
+	}
+	if !pub.IsCiphertext(msg.EncryptedKey) || !msg.EncryptedKeyProof.verify(pub, msg.EncryptedKey, q1, msg.Q1) {
+		return nil, errEncryptedKey
+	}
+	pk, err := jointPublicKey(p.x2, q1)
+	if err != nil {
+		return nil, err
+	}
+	return &Party2Key{
+		x2:           p.x2,
+		paillier:     pub,
+		encryptedKey: new(big.Int).Set(msg.EncryptedKey),
+		pk:           pk,
+	}, nil
+}
+
+// PublicKey returns the joint public key
+func (k *Party1Key) PublicKey() *ecdsa.PublicKey {
+	return k.pk
+}
+
+// PublicKey returns the joint public key
+func (k *Party2Key) PublicKey() *ecdsa.PublicKey {
+	return k.pk
+}
+
+// lindell17Signer hashes the messages and verifies the joint signatures
+func lindell17Signer() *ECDSA {
+	return NewECDSA(elliptic.P256()).SetLowS(true)
+}
+
+// NewSign starts the signature of message by party 1
+func (k *Party1Key) NewSign(message []byte) (*SignParty1, *SignMsg1, error) {
+	k1, err := group.RandomScalar(group.P256(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	r1 := p256Utils.ScalarBaseMult(k1)
+	proof, err := proveDLog("sign-party1", signSession(k.pk), k1, r1)
+	if err != nil {
+		return nil, nil, err
+	}
+	s := &SignParty1{key: k, message: append([]byte{}, message...), k1: k1}
+	return s, &SignMsg1{R1: p256Utils.Marshal(r1), Proof: proof}, nil
+}
+
+// Sign answers the first message of party 1 for message, the same message
+// as the one of party 1
+func (k *Party2Key) Sign(message []byte, msg *SignMsg1) (*SignMsg2, error) {
+	r1, err := decodePoint(msg.R1)
+	if err != nil {
+		return nil, err
+	}
+	if !msg.Proof.verify("sign-party1", signSession(k.pk), r1) {
+		return nil, errDLogProof
+	}
+	digest, err := lindell17Signer().digest(message)
+	if err != nil {
+		return nil, err
+	}
+	n := p256Utils.N
+	m := bits2int(digest, n.BitLen())
+	k2, err := group.RandomScalar(group.P256(), nil)
+	if err != nil {
+		return nil, err
+	}
+	r2 := p256Utils.ScalarBaseMult(k2)
+	proof, err := proveDLog("sign-party2", signSession(k.pk), k2, r2)
+	if err != nil {
+		return nil, err
+	}
+	// R = [k2]R1, r = x(R) mod q
+	rPoint := p256Utils.ScalarMult(r1, k2)
+	r := new(big.Int).Mod(rPoint.X, n)
+	if r.Sign() == 0 {
+		return nil, errPartialResult
+	}
+	// c1 = Enc(rho q + k2^{-1} m), rho < q^2 hides the reduction modulo q
+	inv := newMontgomery(n).inverse(k2)
+	rho, err := rand.Int(rand.Reader, new(big.Int).Mul(n, n))
+	if err != nil {
+		return nil, err
+	}
+	plain := new(big.Int).Mul(inv, m)
+	plain.Mod(plain, n)
+	plain.Add(plain, rho.Mul(rho, n))
+	c1, err := k.paillier.Encrypt(plain)
+	if err != nil {
+		return nil, err
+	}
+	// c2 = c_key^{k2^{-1} r x2} = Enc(k2^{-1} r x1 x2)
+	v := new(big.Int).Mul(inv, r)
+	v.Mul(v, k.x2)
+	v.Mod(v, n)
+	c3 := k.paillier.Add(c1, k.paillier.MulConst(k.encryptedKey, v))
+	return &SignMsg2{R2: p256Utils.Marshal(r2), Proof: proof, EncryptedResult: c3}, nil
+}
+
+// Finish decrypts the answer of party 2 and returns the low-S signature of
+// the message, it fails if the signature does not verify under the joint key
+func (s *SignParty1) Finish(msg *SignMsg2) (*Signature, error) {
+	r2, err := decodePoint(msg.R2)
+	if err != nil {
+		return nil, err
+	}
+	if !msg.Proof.verify("sign-party2", signSession(s.key.pk), r2) {
+		return nil, errDLogProof
+	}
+	n := p256Utils.N
+	rPoint := p256Utils.ScalarMult(r2, s.k1)
+	r := new(big.Int).Mod(rPoint.X, n)
+	if msg.EncryptedResult == nil {
+		return nil, errPartialResult
+	}
+	sPrime, err := s.key.paillier.Decrypt(msg.EncryptedResult)
+	if err != nil {
+		return nil, err
+	}
+	// s = k1^{-1} s' mod q
+	sig := sPrime.Mul(sPrime, newMontgomery(n).inverse(s.k1))
+	sig.Mod(sig, n)
+	if sig.Cmp(halfOrder(n)) > 0 {
+		sig.Sub(n, sig)
+	}
+	res := &Signature{R: r, S: sig}
+	if r.Sign() == 0 || sig.Sign() == 0 || !lindell17Signer().Verify(s.key.pk, s.message, res) {
+		return nil, errPartialResult
+	}
+	return res, nil
+}
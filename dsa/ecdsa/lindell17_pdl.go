@@ -0,0 +1,171 @@
+package ecdsaUtils
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	"scrypto/ecc"
+	"scrypto/ecc/p256Utils"
+	"scrypto/encryption/paillier"
+	"scrypto/proof/transcript"
+)
+
+// Proof that c_key = Enc(x1; r) encrypts the discrete logarithm of Q1 = [x1]G
+// in a range, the PDL and range proofs of Lindell merged into pdlRounds
+// parallel Sigma protocols with binary challenges e: the prover commits to
+// A = Enc(a; b) and B = [a]G for a random a < q 2^pdlSlack, and answers
+// z = a + e x1 < q 2^pdlSlack and w = b r^e. Two answers to one commitment
+// give x' = z1 - z0 with Enc(x'; w1 / w0) = c_key and [x']G = Q1: when Enc
+// is a bijection, which the Paillier key proof ensures, the plaintext of
+// c_key is congruent to log Q1 modulo q and lies in (-q 2^pdlSlack,
+// q 2^pdlSlack). The encrypted s' of party 2 then never wraps modulo N and
+// always decrypts to a valid signature, a cheating party 1 learns nothing on x2.
+
+const (
+	// pdlRounds is the number of repetitions, the soundness error is 2^{-pdlRounds}
+	pdlRounds = 128
+	// pdlSlack is the statistical distance of the answers, in bits
+	pdlSlack = 80
+)
+
+// EncryptedKeyProof proves that the encrypted key of party 1 is the discrete
+// logarithm of Q1 in a small range
+type EncryptedKeyProof struct {
+	A []*big.Int
+	B [][]byte
+	Z []*big.Int
+	W []*big.Int
+}
+
+// pdlBound returns q 2^pdlSlack, the bound of the answers
+func pdlBound() *big.Int {
+	return new(big.Int).Lsh(p256Utils.N, pdlSlack)
+}
+
+// pdlChallenge returns the pdlRounds challenge bits of the commitments
+func pdlChallenge(pk *paillier.PublicKey, c *big.Int, q1 []byte, a []*big.Int, b [][]byte) []byte {
+	t := transcript.New("lindell17-pdl")
+	t.AppendScalar("N", pk.N)
+	t.AppendScalar("c_key", c)
+	t.AppendMessage("Q1", q1)
+	for i := range a {
+		t.AppendScalar("A", a[i])
+		t.AppendMessage("B", b[i])
+	}
+	return t.ChallengeBytes("e", pdlRounds/8)
+}
+
+func challengeBit(e []byte, i int) bool {
+	return e[i/8]>>(i%8)&1 == 1
+}
+
+// proveEncryptedKey proves that c = Enc(x; r) under pk encrypts the discrete
+// logarithm of q1
+func proveEncryptedKey(pk *paillier.PublicKey, x, r, c *big.Int, q1 []byte) (*EncryptedKeyProof, error) {
+	for {
+		proof, ok, err := tryProveEncryptedKey(pk, x, r, c, q1)
+		if err != nil || ok {
+			return proof, err
+		}
+	}
+}
+
+// tryProveEncryptedKey returns the proof and whether all the answers are in
+// range, which fails with probability about pdlRounds 2^{-pdlSlack} for x < q
+func tryProveEncryptedKey(pk *paillier.PublicKey, x, r, c *big.Int, q1 []byte) (*EncryptedKeyProof, bool, error) {
+	bound := pdlBound()
+	alphas, betas := make([]*big.Int, pdlRounds), make([]*big.Int, pdlRounds)
+	proof := &EncryptedKeyProof{
+		A: make([]*big.Int, pdlRounds),
+		B: make([][]byte, pdlRounds),
+		Z: make([]*big.Int, pdlRounds),
+		W: make([]*big.Int, pdlRounds),
+	}
+	errs := make([]error, pdlRounds)
+	ecc.Execute(pdlRounds, func(start, end int) {
+		for i := start; i < end; i++ {
+			alphas[i], betas[i], proof.A[i], errs[i] = pdlCommit(pk, bound)
+			if errs[i] == nil {
+				proof.B[i] = p256Utils.Marshal(p256Utils.ScalarBaseMult(new(big.Int).Mod(alphas[i], p256Utils.N)))
+			}
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	e := pdlChallenge(pk, c, q1, proof.A, proof.B)
+	ok := true
+	for i := 0; i < pdlRounds; i++ {
+		z, w := alphas[i], betas[i]
+		if challengeBit(e, i) {
+			z = new(big.Int).Add(z, x)
+			w = new(big.Int).Mul(w, r)
+			w.Mod(w, pk.N)
+		}
+		ok = ok && z.Cmp(bound) < 0
+		proof.Z[i], proof.W[i] = z, w
+	}
+	return proof, ok, nil
+}
+
+// pdlCommit returns a random a < bound, a random nonce b and Enc(a; b)
+func pdlCommit(pk *paillier.PublicKey, bound *big.Int) (a, b, c *big.Int, err error) {
+	if a, err = rand.Int(rand.Reader, bound); err != nil {
+		return nil, nil, nil, err
+	}
+	if b, err = pk.RandomNonce(nil); err != nil {
+		return nil, nil, nil, err
+	}
+	if c, err = pk.EncryptWithNonce(a, b); err != nil {
+		return nil, nil, nil, err
+	}
+	return a, b, c, nil
+}
+
+// verify reports whether p proves that c encrypts the discrete logarithm of
+// q1 under pk, whose modulus is at least MinPaillierBits
+func (p *EncryptedKeyProof) verify(pk *paillier.PublicKey, c *big.Int, q1 *p256Utils.CurvePoint, q1Bytes []byte) bool {
+	if p == nil || len(p.A) != pdlRounds || len(p.B) != pdlRounds || len(p.Z) != pdlRounds || len(p.W) != pdlRounds {
+		return false
+	}
+	bound := pdlBound()
+	e := pdlChallenge(pk, c, q1Bytes, p.A, p.B)
+	// the range and the points first, they are cheap
+	for i := 0; i < pdlRounds; i++ {
+		z := p.Z[i]
+		if z == nil || p.W[i] == nil || z.Sign() < 0 || z.Cmp(bound) >= 0 || !pk.IsCiphertext(p.A[i]) {
+			return false
+		}
+		b, err := decodePoint(p.B[i])
+		if err != nil {
+			return false
+		}
+		// [z]G = B + [e]Q1
+		if challengeBit(e, i) {
+			b = p256Utils.ScalarAdd(b, q1)
+		}
+		if !p256Utils.IsEqual(p256Utils.ScalarBaseMult(new(big.Int).Mod(z, p256Utils.N)), b) {
+			return false
+		}
+	}
+	valid := make([]bool, pdlRounds)
+	ecc.Execute(pdlRounds, func(start, end int) {
+		for i := start; i < end; i++ {
+			// Enc(z; w) = A c^e
+			left, err := pk.EncryptWithNonce(p.Z[i], p.W[i])
+			right := p.A[i]
+			if challengeBit(e, i) {
+				right = pk.Add(right, c)
+			}
+			valid[i] = err == nil && left.Cmp(right) == 0
+		}
+	})
+	for _, v := range valid {
+		if !v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,293 @@
+package ecdsaUtils
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+
+	"scrypto/ecc/p256Utils"
+	"scrypto/encryption/paillier"
+)
+
+// roundTrip sends msg through its JSON encoding into res
+func roundTrip(msg, res interface{}) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal(data, res); err != nil {
+		panic(err)
+	}
+}
+
+var lindell17Keys struct {
+	once sync.Once
+	key1 *Party1Key
+	key2 *Party2Key
+}
+
+// lindell17KeyGen returns the key shares of a key generation shared by the
+// tests, the proofs of party 1 take seconds
+func lindell17KeyGen() (*Party1Key, *Party2Key) {
+	lindell17Keys.once.Do(func() {
+		lindell17Keys.key1, lindell17Keys.key2 = runLindell17KeyGen()
+	})
+	return lindell17Keys.key1, lindell17Keys.key2
+}
+
+// runLindell17KeyGen runs the key generation of both parties
+func runLindell17KeyGen() (*Party1Key, *Party2Key) {
+	p1, msg1, err := NewKeyGenParty1([]byte("session"))
+	if err != nil {
+		panic(err)
+	}
+	var in1 KeyGenMsg1
+	roundTrip(msg1, &in1)
+	p2, msg2, err := NewKeyGenParty2([]byte("session"), &in1)
+	if err != nil {
+		panic(err)
+	}
+	var in2 KeyGenMsg2
+	roundTrip(msg2, &in2)
+	key1, msg3, err := p1.Finish(&in2, MinPaillierBits)
+	if err != nil {
+		panic(err)
+	}
+	var in3 KeyGenMsg3
+	roundTrip(msg3, &in3)
+	key2, err := p2.Finish(&in3)
+	if err != nil {
+		panic(err)
+	}
+	return key1, key2
+}
+
+func TestParty2Key_Sign(t *testing.T) {
+	key1, key2 := lindell17KeyGen()
+	if !p256Utils.IsEqual(key1.PublicKey(), key2.PublicKey()) {
+		t.Fatal("joint public keys not match")
+	}
+	// the joint key is [x1 x2]G
+	x := new(big.Int).Mul(key1.x1, key2.x2)
+	if !p256Utils.IsEqual(key1.PublicKey(), p256Utils.ScalarBaseMult(x.Mod(x, p256Utils.N))) {
+		t.Error("joint public key is not [x1 x2]G")
+	}
+	for _, m := range []string{"message", "transfer 42 to alice", ""} {
+		s1, msg1, err := key1.NewSign([]byte(m))
+		if err != nil {
+			panic(err)
+		}
+		var in1 SignMsg1
+		roundTrip(msg1, &in1)
+		msg2, err := key2.Sign([]byte(m), &in1)
+		if err != nil {
+			panic(err)
+		}
+		var in2 SignMsg2
+		roundTrip(msg2, &in2)
+		sig, err := s1.Finish(&in2)
+		if err != nil {
+			t.Errorf("%q: %v", m, err)
+			continue
+		}
+		digest := sha256.Sum256([]byte(m))
+		if !ecdsa.Verify(key1.PublicKey(), digest[:], sig.R, sig.S) {
+			t.Errorf("%q: signature not verified by crypto/ecdsa", m)
+		}
+		if sig.S.Cmp(halfOrder(p256Utils.N)) > 0 {
+			t.Errorf("%q: high s", m)
+		}
+	}
+}
+
+func TestKeyGenParty2_Finish(t *testing.T) {
+	p1, msg1, err := NewKeyGenParty1([]byte("session"))
+	if err != nil {
+		panic(err)
+	}
+	p2, msg2, err := NewKeyGenParty2([]byte("session"), msg1)
+	if err != nil {
+		panic(err)
+	}
+	if _, _, err := p1.Finish(msg2, 1024); err == nil {
+		t.Error("small paillier key generated")
+	}
+	// the proofs are bound to the session
+	_, replayed, err := NewKeyGenParty2([]byte("another session"), msg1)
+	if err != nil {
+		panic(err)
+	}
+	if _, _, err := p1.Finish(replayed, MinPaillierBits); err == nil {
+		t.Error("party 1 accepted a proof of another session")
+	}
+	bad := *msg2
+	bad.Proof.Z = new(big.Int).Add(msg2.Proof.Z, big.NewInt(1))
+	if _, _, err := p1.Finish(&bad, MinPaillierBits); err == nil {
+		t.Error("party 1 accepted a wrong proof")
+	}
+	_, msg3, err := p1.Finish(msg2, MinPaillierBits)
+	if err != nil {
+		panic(err)
+	}
+	// Q1 of another key does not open the commitment
+	other := *msg3
+	other.Q1 = p256Utils.Marshal(p256Utils.ScalarBaseMult(big.NewInt(7)))
+	if _, err := p2.Finish(&other); err == nil {
+		t.Error("party 2 accepted another Q1")
+	}
+	other = *msg3
+	other.Proof.Z = p256Utils.N
+	if _, err := p2.Finish(&other); err == nil {
+		t.Error("party 2 accepted an unreduced proof")
+	}
+	other = *msg3
+	other.EncryptedKey = new(big.Int).Mul(msg3.PaillierN, msg3.PaillierN)
+	if _, err := p2.Finish(&other); err == nil {
+		t.Error("party 2 accepted an invalid ciphertext")
+	}
+	other = *msg3
+	other.PaillierN = new(big.Int).Rsh(msg3.PaillierN, 1024)
+	if _, err := p2.Finish(&other); err == nil {
+		t.Error("party 2 accepted a small paillier key")
+	}
+	if _, err := p2.Finish(msg3); err != nil {
+		t.Error(err)
+	}
+}
+
+// cheatingKey returns a message 3 of party 1 whose c_key encrypts x under
+// the Paillier key of key1, with a proof attempt for x
+func cheatingKey(key1 *Party1Key, msg3 *KeyGenMsg3, x *big.Int) *KeyGenMsg3 {
+	pk := &key1.paillier.PublicKey
+	r, err := pk.RandomNonce(nil)
+	if err != nil {
+		panic(err)
+	}
+	c, err := pk.EncryptWithNonce(x, r)
+	if err != nil {
+		panic(err)
+	}
+	proof, _, err := tryProveEncryptedKey(pk, x, r, c, msg3.Q1)
+	if err != nil {
+		panic(err)
+	}
+	res := *msg3
+	res.EncryptedKey, res.EncryptedKeyProof = c, proof
+	return &res
+}
+
+func TestKeyGenParty2_FinishCheatingParty1(t *testing.T) {
+	p1, msg1, err := NewKeyGenParty1([]byte("session"))
+	if err != nil {
+		panic(err)
+	}
+	p2, msg2, err := NewKeyGenParty2([]byte("session"), msg1)
+	if err != nil {
+		panic(err)
+	}
+	key1, msg3, err := p1.Finish(msg2, MinPaillierBits)
+	if err != nil {
+		panic(err)
+	}
+	n := p256Utils.N
+	// c_key of another key share
+	if _, err := p2.Finish(cheatingKey(key1, msg3, new(big.Int).Add(key1.x1, big.NewInt(1)))); err == nil {
+		t.Error("party 2 accepted the encryption of another key share")
+	}
+	// x1 + q 2^100 is x1 modulo q but out of range, a failed signature
+	// would reveal whether the product with x2 wraps modulo N
+	large := new(big.Int).Lsh(n, 100)
+	if _, err := p2.Finish(cheatingKey(key1, msg3, large.Add(large, key1.x1))); err == nil {
+		t.Error("party 2 accepted an encrypted key out of range")
+	}
+	// the honest proof does not prove another ciphertext of x1
+	other := *msg3
+	if other.EncryptedKey, err = key1.paillier.Encrypt(key1.x1); err != nil {
+		panic(err)
+	}
+	if _, err := p2.Finish(&other); err == nil {
+		t.Error("party 2 accepted the proof of another ciphertext")
+	}
+	other = *msg3
+	other.EncryptedKeyProof = nil
+	if _, err := p2.Finish(&other); err == nil {
+		t.Error("party 2 accepted an encrypted key without proof")
+	}
+	// the Paillier proof is bound to the modulus and to Q1
+	other = *msg3
+	other.PaillierProof = nil
+	if _, err := p2.Finish(&other); err == nil {
+		t.Error("party 2 accepted a paillier modulus without proof")
+	}
+	sk, err := paillier.GenerateKey(nil, MinPaillierBits)
+	if err != nil {
+		panic(err)
+	}
+	if other.PaillierProof, err = sk.ProveKey(nil); err != nil {
+		panic(err)
+	}
+	other.PaillierN = sk.N
+	if _, err := p2.Finish(&other); err == nil {
+		t.Error("party 2 accepted a paillier proof of another context")
+	}
+}
+
+func TestDLogProof_Verify(t *testing.T) {
+	x := big.NewInt(42)
+	q := p256Utils.ScalarBaseMult(x)
+	proof, err := proveDLog("sign-party1", []byte("session"), x, q)
+	if err != nil {
+		panic(err)
+	}
+	if !proof.verify("sign-party1", []byte("session"), q) {
+		t.Error("proof not verified")
+	}
+	if proof.verify("sign-party2", []byte("session"), q) {
+		t.Error("proof verified for another role")
+	}
+	if proof.verify("sign-party1", []byte("another session"), q) {
+		t.Error("proof verified in another session")
+	}
+}
+
+func TestSignParty1_Finish(t *testing.T) {
+	key1, key2 := lindell17KeyGen()
+	m := []byte("message")
+	s1, msg1, err := key1.NewSign(m)
+	if err != nil {
+		panic(err)
+	}
+	// the proof of R1 is bound to its point
+	bad := *msg1
+	bad.R1 = p256Utils.Marshal(p256Utils.ScalarBaseMult(big.NewInt(7)))
+	if _, err := key2.Sign(m, &bad); err == nil {
+		t.Error("party 2 accepted R1 with the proof of another point")
+	}
+	msg2, err := key2.Sign(m, msg1)
+	if err != nil {
+		panic(err)
+	}
+	tampered := *msg2
+	tampered.EncryptedResult = key2.paillier.Add(msg2.EncryptedResult, msg2.EncryptedResult)
+	if _, err := s1.Finish(&tampered); err == nil {
+		t.Error("party 1 returned a tampered signature")
+	}
+	// party 2 signing another message gives no signature
+	other, err := key2.Sign([]byte("another message"), msg1)
+	if err != nil {
+		panic(err)
+	}
+	if _, err := s1.Finish(other); err == nil {
+		t.Error("party 1 returned a signature of another message")
+	}
+	sig, err := s1.Finish(msg2)
+	if err != nil {
+		panic(err)
+	}
+	if !lindell17Signer().Verify(key2.PublicKey(), m, sig) {
+		t.Error("signature not verified")
+	}
+}
@@ -1,8 +1,22 @@
+// Package ed25519 implements Ed25519 of RFC 8032 on top of crypto/ed25519,
+// with the Ed25519ctx and Ed25519ph variants, strict and ZIP-215 verification,
+// batch verification, key encodings and the conversion to X25519 keys
 package ed25519
 
 import (
+	"crypto"
+	"crypto/ed25519"
 	"crypto/rand"
-	"golang.org/x/crypto/ed25519"
+	"errors"
+)
+
+// MaxContextSize is the largest context of Ed25519ctx and Ed25519ph
+const MaxContextSize = 255
+
+var (
+	errInvalidSeed    = errors.New("invalid seed length")
+	errInvalidContext = errors.New("context must be 1 to 255 bytes for Ed25519ctx, at most 255 for Ed25519ph")
+	errInvalidDigest  = errors.New("Ed25519ph signs a SHA-512 digest of 64 bytes")
 )
 
 func GenerateKeyPair() (pk *ed25519.PublicKey, sk *ed25519.PrivateKey, err error) {
@@ -13,6 +27,35 @@ func GenerateKeyPair() (pk *ed25519.PublicKey, sk *ed25519.PrivateKey, err error
 	return &pkOri, &skOri, nil
 }
 
+// NewKeyFromSeed derives the key pair of a 32-byte seed, the private key of
+// RFC 8032
+func NewKeyFromSeed(seed []byte) (pk *ed25519.PublicKey, sk *ed25519.PrivateKey, err error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, nil, errInvalidSeed
+	}
+	skOri := ed25519.NewKeyFromSeed(seed)
+	pkOri := skOri.Public().(ed25519.PublicKey)
+	return &pkOri, &skOri, nil
+}
+
+// Seed returns the 32-byte seed of sk
+func Seed(sk *ed25519.PrivateKey) []byte {
+	return sk.Seed()
+}
+
+// PublicKeyFromBytes decodes a raw 32-byte public key, which must be the
+// canonical encoding of a point
+func PublicKeyFromBytes(data []byte) (*ed25519.PublicKey, error) {
+	if len(data) != ed25519.PublicKeySize {
+		return nil, errInvalidPublicKeyLen
+	}
+	if _, err := decode(data, true); err != nil {
+		return nil, err
+	}
+	pk := ed25519.PublicKey(append([]byte{}, data...))
+	return &pk, nil
+}
+
 func Sign(sk *ed25519.PrivateKey, message []byte) []byte {
 	signature := ed25519.Sign(*sk, message)
 	return signature
@@ -21,3 +64,41 @@ func Sign(sk *ed25519.PrivateKey, message []byte) []byte {
 func Verify(pk *ed25519.PublicKey, message []byte, signature []byte) bool {
 	return ed25519.Verify(*pk, message, signature)
 }
+
+// SignCtx signs with Ed25519ctx, the context separates the protocols using
+// the same key
+func SignCtx(sk *ed25519.PrivateKey, message, context []byte) ([]byte, error) {
+	if len(context) == 0 || len(context) > MaxContextSize {
+		return nil, errInvalidContext
+	}
+	return sk.Sign(nil, message, &ed25519.Options{Context: string(context)})
+}
+
+// VerifyCtx verifies an Ed25519ctx signature
+func VerifyCtx(pk *ed25519.PublicKey, message, signature, context []byte) bool {
+	if len(context) == 0 || len(context) > MaxContextSize {
+		return false
+	}
+	return ed25519.VerifyWithOptions(*pk, message, signature, &ed25519.Options{Context: string(context)}) == nil
+}
+
+// SignPh signs with Ed25519ph the SHA-512 digest of a message, which can then
+// be hashed in a stream, the context may be empty
+func SignPh(sk *ed25519.PrivateKey, digest, context []byte) ([]byte, error) {
+	if len(digest) != 64 {
+		return nil, errInvalidDigest
+	}
+	if len(context) > MaxContextSize {
+		return nil, errInvalidContext
+	}
+	return sk.Sign(nil, digest, &ed25519.Options{Hash: crypto.SHA512, Context: string(context)})
+}
+
+// VerifyPh verifies an Ed25519ph signature of the SHA-512 digest of a message
+func VerifyPh(pk *ed25519.PublicKey, digest, signature, context []byte) bool {
+	if len(digest) != 64 || len(context) > MaxContextSize {
+		return false
+	}
+	opts := &ed25519.Options{Hash: crypto.SHA512, Context: string(context)}
+	return ed25519.VerifyWithOptions(*pk, digest, signature, opts) == nil
+}
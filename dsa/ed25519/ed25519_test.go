@@ -1,8 +1,15 @@
 package ed25519
 
 import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
 	"fmt"
+	"math/big"
 	"testing"
+
+	"golang.org/x/crypto/curve25519"
 )
 
 func TestTryOnce(t *testing.T) {
@@ -36,3 +43,188 @@ func TestVerify(t *testing.T) {
 	res := Verify(pk, []byte("hello"), signature)
 	fmt.Println("res:", res)
 }
+
+func fromHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestSignCtx(t *testing.T) {
+	// RFC 8032 7.1 TEST 1, 7.2 foo and 7.3 TEST abc
+	pk, sk, err := NewKeyFromSeed(fromHex("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60"))
+	if err != nil {
+		panic(err)
+	}
+	if !bytes.Equal(*pk, fromHex("d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a")) {
+		t.Error("public key not match")
+	}
+	if !bytes.Equal(Sign(sk, nil), fromHex("e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e065224901555fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b")) {
+		t.Error("Ed25519 signature not match")
+	}
+	pk, sk, err = NewKeyFromSeed(fromHex("0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6"))
+	if err != nil {
+		panic(err)
+	}
+	m := fromHex("f726936d19c800494e3fdaff20b276a8")
+	sig, err := SignCtx(sk, m, []byte("foo"))
+	if err != nil {
+		panic(err)
+	}
+	if !bytes.Equal(sig, fromHex("55a4cc2f70a54e04288c5f4cd1e45a7bb520b36292911876cada7323198dd87a8b36950b95130022907a7fb7c4e9b2d5f6cca685a587b4b21f4b888e4e7edb0d")) {
+		t.Error("Ed25519ctx signature not match")
+	}
+	if !VerifyCtx(pk, m, sig, []byte("foo")) {
+		t.Error("Ed25519ctx signature not verified")
+	}
+	if VerifyCtx(pk, m, sig, []byte("bar")) || Verify(pk, m, sig) {
+		t.Error("Ed25519ctx signature verified under another context")
+	}
+	if _, err := SignCtx(sk, m, nil); err == nil {
+		t.Error("empty context accepted by Ed25519ctx")
+	}
+	pk, sk, err = NewKeyFromSeed(fromHex("833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42"))
+	if err != nil {
+		panic(err)
+	}
+	digest := sha512.Sum512([]byte("abc"))
+	if sig, err = SignPh(sk, digest[:], nil); err != nil {
+		panic(err)
+	}
+	if !bytes.Equal(sig, fromHex("98a70222f0b8121aa9d30f813d683f809e462b469c7ff87639499bb94e6dae4131f85042463c2a355a2003d062adf5aaa10b8c61e636062aaad11c2a26083406")) {
+		t.Error("Ed25519ph signature not match")
+	}
+	if !VerifyPh(pk, digest[:], sig, nil) || VerifyPh(pk, digest[:], sig, []byte("foo")) {
+		t.Error("Ed25519ph verification failed")
+	}
+}
+
+func TestVerifyZIP215(t *testing.T) {
+	pk, sk, err := GenerateKeyPair()
+	if err != nil {
+		panic(err)
+	}
+	m := []byte("hello")
+	sig := Sign(sk, m)
+	if !VerifyStrict(pk, m, sig) || !VerifyZIP215(pk, m, sig) {
+		t.Error("signature not verified")
+	}
+	if VerifyStrict(pk, []byte("world"), sig) || VerifyZIP215(pk, []byte("world"), sig) {
+		t.Error("signature verified on another message")
+	}
+	// A and R are the identity, S = 0: valid for ZIP-215, small order for the
+	// strict rules
+	identity := fromHex("0100000000000000000000000000000000000000000000000000000000000000")
+	// y = p + 1, a non-canonical encoding of the identity
+	nonCanonical := fromHex("eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	for _, enc := range [][]byte{identity, nonCanonical} {
+		weak := ed25519.PublicKey(enc)
+		forged := append(append([]byte{}, enc...), make([]byte, 32)...)
+		if !VerifyZIP215(&weak, m, forged) {
+			t.Error("ZIP-215 rejected a signature of small order")
+		}
+		if VerifyStrict(&weak, m, forged) {
+			t.Error("strict rules accepted a signature of small order")
+		}
+	}
+	// S >= L is rejected by both rules
+	high := append(append([]byte{}, sig[:32]...), leBytes(new(big.Int).Add(leInt(sig[32:]), curveL))...)
+	if VerifyZIP215(pk, m, high) || VerifyStrict(pk, m, high) {
+		t.Error("non-canonical S accepted")
+	}
+}
+
+func TestBatchVerify(t *testing.T) {
+	var pks []*ed25519.PublicKey
+	var messages, sigs [][]byte
+	for i := 0; i < 16; i++ {
+		pk, sk, err := GenerateKeyPair()
+		if err != nil {
+			panic(err)
+		}
+		m := []byte{byte(i)}
+		pks, messages, sigs = append(pks, pk), append(messages, m), append(sigs, Sign(sk, m))
+	}
+	if !BatchVerify(pks, messages, sigs) {
+		t.Error("batch not verified")
+	}
+	messages[3] = []byte("another message")
+	if BatchVerify(pks, messages, sigs) {
+		t.Error("batch verified with a wrong signature")
+	}
+	if BatchVerify(pks[1:], messages, sigs) {
+		t.Error("batch verified with missing keys")
+	}
+}
+
+func TestParseJWK(t *testing.T) {
+	pk, sk, err := GenerateKeyPair()
+	if err != nil {
+		panic(err)
+	}
+	data, err := MarshalJWK(pk, sk)
+	if err != nil {
+		panic(err)
+	}
+	pk2, sk2, err := ParseJWK(data)
+	if err != nil {
+		panic(err)
+	}
+	if !bytes.Equal(*pk2, *pk) || !bytes.Equal(*sk2, *sk) {
+		t.Error("JWK round trip failed")
+	}
+	if data, err = MarshalJWK(pk, nil); err != nil {
+		panic(err)
+	}
+	if _, sk2, err = ParseJWK(data); err != nil || sk2 != nil {
+		t.Error("public JWK not parsed")
+	}
+	other, _, _ := GenerateKeyPair()
+	if _, err := MarshalJWK(other, sk); err == nil {
+		t.Error("JWK of mismatched keys encoded")
+	}
+	der, err := MarshalPKCS8(sk)
+	if err != nil {
+		panic(err)
+	}
+	if sk2, err = ParsePKCS8(der); err != nil || !bytes.Equal(*sk2, *sk) {
+		t.Error("PKCS#8 round trip failed")
+	}
+}
+
+func TestToX25519PublicKey(t *testing.T) {
+	pkA, skA, _ := GenerateKeyPair()
+	pkB, skB, _ := GenerateKeyPair()
+	xpkA, err := ToX25519PublicKey(pkA)
+	if err != nil {
+		panic(err)
+	}
+	xpkB, err := ToX25519PublicKey(pkB)
+	if err != nil {
+		panic(err)
+	}
+	derived, err := curve25519.X25519(ToX25519PrivateKey(skA), curve25519.Basepoint)
+	if err != nil {
+		panic(err)
+	}
+	if !bytes.Equal(derived, xpkA) {
+		t.Error("X25519 public key not match")
+	}
+	sharedA, err := curve25519.X25519(ToX25519PrivateKey(skA), xpkB)
+	if err != nil {
+		panic(err)
+	}
+	sharedB, err := curve25519.X25519(ToX25519PrivateKey(skB), xpkA)
+	if err != nil {
+		panic(err)
+	}
+	if !bytes.Equal(sharedA, sharedB) {
+		t.Error("shared secrets not match")
+	}
+	identity := ed25519.PublicKey(fromHex("0100000000000000000000000000000000000000000000000000000000000000"))
+	if _, err := ToX25519PublicKey(&identity); err == nil {
+		t.Error("key of small order converted")
+	}
+}
@@ -0,0 +1,182 @@
+package ed25519
+
+import (
+	"errors"
+	"math/big"
+)
+
+// edwards25519 is -x^2 + y^2 = 1 + d x^2 y^2 over GF(2^255 - 19), points are
+// in extended coordinates (X : Y : Z : T) with x = X/Z, y = Y/Z, xy = T/Z
+type edPoint struct {
+	X, Y, Z, T *big.Int
+}
+
+var (
+	fieldP = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	// curveD is -121665/121666
+	curveD, _ = new(big.Int).SetString("37095705934669439343138083508754565189542113879843219016388785533085940283555", 10)
+	// curveL is the prime order of the base point, 2^252 + 27742317777372353535851937790883648493
+	curveL, _ = new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	baseY, _  = new(big.Int).SetString("46316835694926478169428394003475163141307993866256225615783033603165251855960", 10)
+	basePoint = mustDecode(leBytes(baseY))
+)
+
+var (
+	errInvalidPoint        = errors.New("invalid point encoding")
+	errNonCanonicalPoint   = errors.New("point encoding is not canonical")
+	errSmallOrderPoint     = errors.New("point of small order")
+	errInvalidPublicKeyLen = errors.New("invalid public key length")
+)
+
+// leBytes encodes k < 2^256 in 32 bytes little-endian
+func leBytes(k *big.Int) []byte {
+	res := k.FillBytes(make([]byte, 32))
+	for i, j := 0, 31; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res
+}
+
+// leInt reads b as a little-endian integer
+func leInt(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+func fieldMul(a, b *big.Int) *big.Int {
+	res := new(big.Int).Mul(a, b)
+	return res.Mod(res, fieldP)
+}
+
+func identity() *edPoint {
+	return &edPoint{X: new(big.Int), Y: big.NewInt(1), Z: big.NewInt(1), T: new(big.Int)}
+}
+
+// decode reads the 32-byte encoding of a point: y in little-endian and the
+// sign of x in the top bit. Canonical decoding (RFC 8032) rejects y >= p and
+// the negative zero x, the ZIP-215 decoding accepts and reduces both
+func decode(b []byte, canonical bool) (*edPoint, error) {
+	if len(b) != 32 {
+		return nil, errInvalidPoint
+	}
+	sign := b[31] >> 7
+	yb := append([]byte{}, b...)
+	yb[31] &= 0x7f
+	y := leInt(yb)
+	if y.Cmp(fieldP) >= 0 {
+		if canonical {
+			return nil, errNonCanonicalPoint
+		}
+		y.Sub(y, fieldP)
+	}
+	// x^2 = (y^2 - 1) / (d y^2 + 1)
+	yy := fieldMul(y, y)
+	u := new(big.Int).Sub(yy, big.NewInt(1))
+	v := new(big.Int).Add(fieldMul(curveD, yy), big.NewInt(1))
+	xx := fieldMul(u, new(big.Int).ModInverse(v, fieldP))
+	x := new(big.Int).ModSqrt(xx, fieldP)
+	if x == nil {
+		return nil, errInvalidPoint
+	}
+	if x.Sign() == 0 && sign == 1 && canonical {
+		return nil, errNonCanonicalPoint
+	}
+	if uint(x.Bit(0)) != uint(sign) {
+		x.Sub(fieldP, x).Mod(x, fieldP)
+	}
+	return &edPoint{X: x, Y: y, Z: big.NewInt(1), T: fieldMul(x, y)}, nil
+}
+
+func mustDecode(b []byte) *edPoint {
+	p, err := decode(b, true)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// affine returns x and y of p
+func (p *edPoint) affine() (x, y *big.Int) {
+	zInv := new(big.Int).ModInverse(p.Z, fieldP)
+	return fieldMul(p.X, zInv), fieldMul(p.Y, zInv)
+}
+
+// encode returns the canonical encoding of p
+func (p *edPoint) encode() []byte {
+	x, y := p.affine()
+	res := leBytes(y)
+	res[31] |= byte(x.Bit(0)) << 7
+	return res
+}
+
+// add uses the complete formula add-2008-hwcd-3 for a = -1
+func add(p, q *edPoint) *edPoint {
+	a := fieldMul(new(big.Int).Sub(p.Y, p.X), new(big.Int).Sub(q.Y, q.X))
+	b := fieldMul(new(big.Int).Add(p.Y, p.X), new(big.Int).Add(q.Y, q.X))
+	c := fieldMul(fieldMul(p.T, q.T), new(big.Int).Lsh(curveD, 1))
+	d := fieldMul(new(big.Int).Lsh(p.Z, 1), q.Z)
+	e := new(big.Int).Sub(b, a)
+	f := new(big.Int).Sub(d, c)
+	g := new(big.Int).Add(d, c)
+	h := new(big.Int).Add(b, a)
+	return &edPoint{X: fieldMul(e, f), Y: fieldMul(g, h), Z: fieldMul(f, g), T: fieldMul(e, h)}
+}
+
+func neg(p *edPoint) *edPoint {
+	return &edPoint{
+		X: new(big.Int).Sub(fieldP, p.X),
+		Y: new(big.Int).Set(p.Y),
+		Z: new(big.Int).Set(p.Z),
+		T: new(big.Int).Sub(fieldP, p.T),
+	}
+}
+
+// scalarMult returns [k]p for k >= 0, in variable time
+func scalarMult(k *big.Int, p *edPoint) *edPoint {
+	return multiScalarMult([]*big.Int{k}, []*edPoint{p})
+}
+
+// multiScalarMult returns the sum of [k_i]p_i with interleaved double-and-add,
+// the doublings are shared by all the terms
+func multiScalarMult(scalars []*big.Int, points []*edPoint) *edPoint {
+	bits := 0
+	for _, k := range scalars {
+		if k.BitLen() > bits {
+			bits = k.BitLen()
+		}
+	}
+	res := identity()
+	for i := bits - 1; i >= 0; i-- {
+		res = add(res, res)
+		for j, k := range scalars {
+			if k.Bit(i) == 1 {
+				res = add(res, points[j])
+			}
+		}
+	}
+	return res
+}
+
+func (p *edPoint) isIdentity() bool {
+	return new(big.Int).Mod(p.X, fieldP).Sign() == 0 && new(big.Int).Mod(new(big.Int).Sub(p.Y, p.Z), fieldP).Sign() == 0
+}
+
+func (p *edPoint) equal(q *edPoint) bool {
+	return add(p, neg(q)).isIdentity()
+}
+
+// mulByCofactor returns [8]p
+func (p *edPoint) mulByCofactor() *edPoint {
+	for i := 0; i < 3; i++ {
+		p = add(p, p)
+	}
+	return p
+}
+
+// isSmallOrder reports whether p is one of the 8 points of order dividing 8
+func (p *edPoint) isSmallOrder() bool {
+	return p.mulByCofactor().isIdentity()
+}
@@ -0,0 +1,118 @@
+package ed25519
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+var (
+	errNotEd25519Key = errors.New("not an Ed25519 key")
+	errInvalidJWK    = errors.New("invalid Ed25519 JWK")
+)
+
+// MarshalPKCS8 encodes sk in a PKCS#8 DER structure of RFC 8410
+func MarshalPKCS8(sk *ed25519.PrivateKey) ([]byte, error) {
+	return x509.MarshalPKCS8PrivateKey(*sk)
+}
+
+// ParsePKCS8 decodes a PKCS#8 DER structure holding an Ed25519 private key
+func ParsePKCS8(der []byte) (*ed25519.PrivateKey, error) {
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	sk, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errNotEd25519Key
+	}
+	return &sk, nil
+}
+
+// JWK is an Ed25519 key in the JSON Web Key format of RFC 8037, D is only set
+// for a private key
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	D   string `json:"d,omitempty"`
+}
+
+// MarshalJWK encodes pk, and the seed of sk when sk is not nil, as a JWK
+func MarshalJWK(pk *ed25519.PublicKey, sk *ed25519.PrivateKey) ([]byte, error) {
+	k := JWK{Kty: "OKP", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(*pk)}
+	if sk != nil {
+		if !bytes.Equal(sk.Public().(ed25519.PublicKey), *pk) {
+			return nil, errNotEd25519Key
+		}
+		k.D = base64.RawURLEncoding.EncodeToString(sk.Seed())
+	}
+	return json.Marshal(k)
+}
+
+// ParseJWK decodes a JWK, sk is nil for a public key, the public key of a
+// private key must match its seed
+func ParseJWK(data []byte) (pk *ed25519.PublicKey, sk *ed25519.PrivateKey, err error) {
+	var k JWK
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, nil, err
+	}
+	if k.Kty != "OKP" || k.Crv != "Ed25519" {
+		return nil, nil, errNotEd25519Key
+	}
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, nil, errInvalidJWK
+	}
+	if pk, err = PublicKeyFromBytes(x); err != nil {
+		return nil, nil, err
+	}
+	if k.D == "" {
+		return pk, nil, nil
+	}
+	d, err := base64.RawURLEncoding.DecodeString(k.D)
+	if err != nil {
+		return nil, nil, errInvalidJWK
+	}
+	derived, sk, err := NewKeyFromSeed(d)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(*derived, *pk) {
+		return nil, nil, errInvalidJWK
+	}
+	return pk, sk, nil
+}
+
+// ToX25519PublicKey maps pk to the u-coordinate u = (1 + y) / (1 - y) of the
+// birationally equivalent Montgomery curve, keys of small order are rejected
+func ToX25519PublicKey(pk *ed25519.PublicKey) ([]byte, error) {
+	a, err := decode(*pk, true)
+	if err != nil {
+		return nil, err
+	}
+	if a.isSmallOrder() {
+		return nil, errSmallOrderPoint
+	}
+	_, y := a.affine()
+	num := new(big.Int).Add(big.NewInt(1), y)
+	den := new(big.Int).Sub(big.NewInt(1), y)
+	den.Mod(den, fieldP).ModInverse(den, fieldP)
+	return leBytes(fieldMul(num, den)), nil
+}
+
+// ToX25519PrivateKey returns the clamped scalar of sk, the first half of
+// SHA-512(seed), whose X25519 public key is ToX25519PublicKey of pk
+func ToX25519PrivateKey(sk *ed25519.PrivateKey) []byte {
+	h := sha512.Sum512(sk.Seed())
+	res := h[:32]
+	res[0] &= 248
+	res[31] &= 127
+	res[31] |= 64
+	return res
+}
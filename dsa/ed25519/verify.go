@@ -0,0 +1,104 @@
+package ed25519
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"math/big"
+)
+
+// Verify of crypto/ed25519 sits between the two rules below: it accepts any
+// y of A below 2^255 and points of small order, but only the canonical R, and
+// checks the cofactorless equation. The rules disagree on crafted signatures
+// only, which matters when several nodes must reach the same decision.
+
+// challenge returns k = SHA-512(R || A || M) mod L on the encodings as sent
+func challenge(r, a, message []byte) *big.Int {
+	h := sha512.New()
+	h.Write(r)
+	h.Write(a)
+	h.Write(message)
+	return new(big.Int).Mod(leInt(h.Sum(nil)), curveL)
+}
+
+// parse decodes A, R and S, S must be lower than L in both rules
+func parse(pk *ed25519.PublicKey, signature []byte, canonical bool) (a, r *edPoint, s *big.Int, ok bool) {
+	if pk == nil || len(*pk) != ed25519.PublicKeySize || len(signature) != ed25519.SignatureSize {
+		return nil, nil, nil, false
+	}
+	s = leInt(signature[32:])
+	if s.Cmp(curveL) >= 0 {
+		return nil, nil, nil, false
+	}
+	a, err := decode(*pk, canonical)
+	if err != nil {
+		return nil, nil, nil, false
+	}
+	if r, err = decode(signature[:32], canonical); err != nil {
+		return nil, nil, nil, false
+	}
+	return a, r, s, true
+}
+
+// VerifyStrict verifies with the strict rules of RFC 8032 and libsodium:
+// canonical encodings of A and R, neither of small order, and the equation
+// [S]B = R + [k]A
+func VerifyStrict(pk *ed25519.PublicKey, message, signature []byte) bool {
+	a, r, s, ok := parse(pk, signature, true)
+	if !ok || a.isSmallOrder() || r.isSmallOrder() {
+		return false
+	}
+	k := challenge(signature[:32], *pk, message)
+	left := scalarMult(s, basePoint)
+	return left.equal(add(r, scalarMult(k, a)))
+}
+
+// VerifyZIP215 verifies with the rules of ZIP-215: any y of A and R below
+// 2^255 is accepted and reduced, small orders are allowed, and the cofactored
+// equation [8][S]B = [8]R + [8][k]A is checked, so that single and batch
+// verification always agree
+func VerifyZIP215(pk *ed25519.PublicKey, message, signature []byte) bool {
+	a, r, s, ok := parse(pk, signature, false)
+	if !ok {
+		return false
+	}
+	k := challenge(signature[:32], *pk, message)
+	// [S]B - R - [k]A
+	diff := multiScalarMult([]*big.Int{s, k}, []*edPoint{basePoint, neg(a)})
+	return add(diff, neg(r)).mulByCofactor().isIdentity()
+}
+
+// BatchVerify verifies the signatures of messages under pks at once with the
+// rules of ZIP-215, so it accepts exactly when VerifyZIP215 accepts every
+// signature, up to a probability of 2^-128. It checks
+// [8]([sum z_i S_i]B - sum [z_i]R_i - sum [z_i k_i]A_i) = 0 for random z_i of
+// 128 bits
+func BatchVerify(pks []*ed25519.PublicKey, messages [][]byte, signatures [][]byte) bool {
+	if len(pks) != len(messages) || len(pks) != len(signatures) {
+		return false
+	}
+	if len(pks) == 0 {
+		return true
+	}
+	sumS := new(big.Int)
+	scalars := make([]*big.Int, 0, 2*len(pks)+1)
+	points := make([]*edPoint, 0, 2*len(pks)+1)
+	bound := new(big.Int).Lsh(big.NewInt(1), 128)
+	for i, pk := range pks {
+		a, r, s, ok := parse(pk, signatures[i], false)
+		if !ok {
+			return false
+		}
+		z, err := rand.Int(rand.Reader, bound)
+		if err != nil {
+			return false
+		}
+		k := challenge(signatures[i][:32], *pk, messages[i])
+		sumS.Add(sumS, new(big.Int).Mul(z, s))
+		scalars = append(scalars, z, new(big.Int).Mod(new(big.Int).Mul(z, k), curveL))
+		points = append(points, neg(r), neg(a))
+	}
+	scalars = append(scalars, sumS.Mod(sumS, curveL))
+	points = append(points, basePoint)
+	return multiScalarMult(scalars, points).mulByCofactor().isIdentity()
+}
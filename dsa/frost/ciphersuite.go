@@ -0,0 +1,209 @@
+// Package frost implements FROST threshold Schnorr signatures as in RFC 9591
+// with the ciphersuites FROST(ristretto255, SHA-512) and FROST(P-256, SHA-256):
+// any t of the n holders of a Shamir share of the key sign in two rounds, and
+// the aggregate is a Schnorr signature (R, z) checked as [z]G = R + [c]PK.
+package frost
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+
+	"scrypto/ecc/group"
+	"scrypto/ecc/p256Utils"
+	"scrypto/sutils/xmd"
+)
+
+// Ciphersuite is a FROST ciphersuite: its group, its encodings and its hash
+// functions H1 to H5
+type Ciphersuite struct {
+	id          string
+	group       group.Group
+	elementSize int
+	// hashToScalar returns H_tag(m), tag "chal" is H2
+	hashToScalar func(c *Ciphersuite, tag string, m []byte) *big.Int
+	// hash returns H_tag(m) as bytes, for H4 and H5
+	hash func(c *Ciphersuite, tag string, m []byte) []byte
+	// littleEndian scalars for ristretto255, big-endian for P-256
+	littleEndian bool
+}
+
+// The ciphersuites of RFC 9591
+var (
+	Ristretto255 = &Ciphersuite{
+		id:           "FROST-RISTRETTO255-SHA512-v1",
+		group:        group.Ristretto255(),
+		elementSize:  32,
+		hashToScalar: ristretto255HashToScalar,
+		hash:         sha512Hash,
+		littleEndian: true,
+	}
+	P256 = &Ciphersuite{
+		id:           "FROST-P256-SHA256-v1",
+		group:        group.P256(),
+		elementSize:  33,
+		hashToScalar: p256HashToScalar,
+		hash:         sha256Hash,
+	}
+)
+
+var (
+	errIdentityElement = errors.New("element is the identity")
+	errInvalidElement  = errors.New("invalid element encoding")
+	errInvalidScalar   = errors.New("invalid scalar encoding")
+)
+
+// ID returns the context string of c
+func (c *Ciphersuite) ID() string {
+	return c.id
+}
+
+// Group returns the group of c
+func (c *Ciphersuite) Group() group.Group {
+	return c.group
+}
+
+func sha512Hash(c *Ciphersuite, tag string, m []byte) []byte {
+	h := sha512.New()
+	h.Write([]byte(c.id + tag))
+	h.Write(m)
+	return h.Sum(nil)
+}
+
+func sha256Hash(c *Ciphersuite, tag string, m []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(c.id + tag))
+	h.Write(m)
+	return h.Sum(nil)
+}
+
+// ristretto255HashToScalar reduces SHA-512(contextString || tag || m) read in
+// little-endian
+func ristretto255HashToScalar(c *Ciphersuite, tag string, m []byte) *big.Int {
+	return new(big.Int).Mod(leToInt(sha512Hash(c, tag, m)), c.group.Order())
+}
+
+// p256HashToScalar is hash_to_field of RFC 9380 modulo the order with
+// expand_message_xmd on SHA-256, DST = contextString || tag and L = 48
+func p256HashToScalar(c *Ciphersuite, tag string, m []byte) *big.Int {
+	uniform, err := xmd.Expand(m, []byte(c.id+tag), 48)
+	if err != nil {
+		panic(err)
+	}
+	return new(big.Int).Mod(new(big.Int).SetBytes(uniform), c.group.Order())
+}
+
+// leToInt reads b as a little-endian integer
+func leToInt(b []byte) *big.Int {
+	be := make([]byte, len(b))
+	for i := range b {
+		be[i] = b[len(b)-1-i]
+	}
+	return new(big.Int).SetBytes(be)
+}
+
+// h1 derives the binding factors
+func (c *Ciphersuite) h1(m []byte) *big.Int {
+	return c.hashToScalar(c, "rho", m)
+}
+
+// h2 derives the challenge
+func (c *Ciphersuite) h2(m []byte) *big.Int {
+	return c.hashToScalar(c, "chal", m)
+}
+
+// h3 derives the nonces
+func (c *Ciphersuite) h3(m []byte) *big.Int {
+	return c.hashToScalar(c, "nonce", m)
+}
+
+// h4 hashes the message
+func (c *Ciphersuite) h4(m []byte) []byte {
+	return c.hash(c, "msg", m)
+}
+
+// h5 hashes the commitment list
+func (c *Ciphersuite) h5(m []byte) []byte {
+	return c.hash(c, "com", m)
+}
+
+// hDKG derives the challenges of the proofs of knowledge of the DKG
+func (c *Ciphersuite) hDKG(m []byte) *big.Int {
+	return c.hashToScalar(c, "dkg", m)
+}
+
+// SerializeElement encodes p, ristretto255 in 32 bytes and P-256 compressed,
+// the identity is rejected
+func (c *Ciphersuite) SerializeElement(p group.Point) ([]byte, error) {
+	if c.group.IsIdentity(p) {
+		return nil, errIdentityElement
+	}
+	if c == P256 {
+		q := p.(*p256Utils.CurvePoint)
+		return elliptic.MarshalCompressed(q.Curve, q.X, q.Y), nil
+	}
+	return c.group.Marshal(p), nil
+}
+
+// mustSerializeElement encodes p, which is not the identity
+func (c *Ciphersuite) mustSerializeElement(p group.Point) []byte {
+	res, err := c.SerializeElement(p)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// DeserializeElement decodes an element other than the identity
+func (c *Ciphersuite) DeserializeElement(data []byte) (group.Point, error) {
+	if len(data) != c.elementSize {
+		return nil, errInvalidElement
+	}
+	var p group.Point
+	if c == P256 {
+		x, y := elliptic.UnmarshalCompressed(elliptic.P256(), data)
+		if x == nil {
+			return nil, errInvalidElement
+		}
+		p = &p256Utils.CurvePoint{Curve: elliptic.P256(), X: x, Y: y}
+	} else {
+		var err error
+		if p, err = c.group.Unmarshal(data); err != nil {
+			return nil, errInvalidElement
+		}
+	}
+	if c.group.IsIdentity(p) {
+		return nil, errIdentityElement
+	}
+	return p, nil
+}
+
+// SerializeScalar encodes k mod the order in 32 bytes
+func (c *Ciphersuite) SerializeScalar(k *big.Int) []byte {
+	res := new(big.Int).Mod(k, c.group.Order()).FillBytes(make([]byte, 32))
+	if c.littleEndian {
+		for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+			res[i], res[j] = res[j], res[i]
+		}
+	}
+	return res
+}
+
+// DeserializeScalar decodes a scalar lower than the order
+func (c *Ciphersuite) DeserializeScalar(data []byte) (*big.Int, error) {
+	if len(data) != 32 {
+		return nil, errInvalidScalar
+	}
+	var k *big.Int
+	if c.littleEndian {
+		k = leToInt(data)
+	} else {
+		k = new(big.Int).SetBytes(data)
+	}
+	if k.Cmp(c.group.Order()) >= 0 {
+		return nil, errInvalidScalar
+	}
+	return k, nil
+}
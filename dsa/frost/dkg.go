@@ -0,0 +1,178 @@
+package frost
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"scrypto/ecc/group"
+)
+
+// DKGRound1Msg is broadcast by every participant of the distributed key
+// generation of the FROST paper: the VSS commitment of its polynomial and a
+// Schnorr proof of knowledge (R, mu) of the constant term
+type DKGRound1Msg struct {
+	ID         *big.Int
+	Commitment []group.Point
+	R          group.Point
+	Mu         *big.Int
+}
+
+// DKGRound2Msg is sent privately from participant From to participant To:
+// the evaluation f_From(To)
+type DKGRound2Msg struct {
+	From  *big.Int
+	To    *big.Int
+	Share *big.Int
+}
+
+// DKGParticipant is the state of a participant of the distributed key
+// generation, it replaces the trusted dealer
+type DKGParticipant struct {
+	c            *Ciphersuite
+	id           *big.Int
+	t, n         int
+	coefficients []*big.Int
+	round1       []*DKGRound1Msg
+}
+
+var (
+	errInvalidDKGMessage = errors.New("invalid DKG message")
+	errInvalidProof      = errors.New("invalid proof of knowledge")
+)
+
+// NewDKGParticipant starts the distributed key generation of a t-of-n key for
+// participant id in [1, n] and returns its round 1 broadcast
+func (c *Ciphersuite) NewDKGParticipant(id, t, n int, random io.Reader) (*DKGParticipant, *DKGRound1Msg, error) {
+	if err := c.checkParameters(t, n); err != nil {
+		return nil, nil, err
+	}
+	if id < 1 || id > n {
+		return nil, nil, errInvalidIdentifier
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+	secret, err := group.RandomScalar(c.group, random)
+	if err != nil {
+		return nil, nil, err
+	}
+	coefficients, err := c.polynomial(secret, t, random)
+	if err != nil {
+		return nil, nil, err
+	}
+	k, err := group.RandomScalar(c.group, random)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := &DKGParticipant{c: c, id: big.NewInt(int64(id)), t: t, n: n, coefficients: coefficients}
+	msg := &DKGRound1Msg{ID: p.id, Commitment: c.commit(coefficients), R: c.group.ScalarBaseMult(k)}
+	mu := new(big.Int).Mul(coefficients[0], c.dkgChallenge(msg.ID, msg.Commitment[0], msg.R))
+	msg.Mu = mu.Mod(mu.Add(mu, k), c.group.Order())
+	return p, msg, nil
+}
+
+// dkgChallenge binds the proof of knowledge to the identifier of the prover
+func (c *Ciphersuite) dkgChallenge(id *big.Int, a0, r group.Point) *big.Int {
+	data := c.SerializeScalar(id)
+	data = append(data, c.mustSerializeElement(a0)...)
+	return c.hDKG(append(data, c.mustSerializeElement(r)...))
+}
+
+// checkRound1 checks the shape of a broadcast and its proof [mu]G = R + [c]A_0
+func (p *DKGParticipant) checkRound1(msg *DKGRound1Msg) error {
+	c := p.c
+	if msg == nil || msg.ID == nil || msg.ID.Sign() <= 0 || msg.ID.Cmp(big.NewInt(int64(p.n))) > 0 {
+		return errInvalidDKGMessage
+	}
+	if len(msg.Commitment) != p.t || msg.R == nil || msg.Mu == nil || c.group.IsIdentity(msg.R) {
+		return errInvalidDKGMessage
+	}
+	for _, a := range msg.Commitment {
+		if a == nil || c.group.IsIdentity(a) {
+			return errInvalidDKGMessage
+		}
+	}
+	right := c.group.Add(msg.R, c.group.ScalarMult(msg.Commitment[0], c.dkgChallenge(msg.ID, msg.Commitment[0], msg.R)))
+	if !c.group.Equal(c.group.ScalarBaseMult(msg.Mu), right) {
+		return errInvalidProof
+	}
+	return nil
+}
+
+// Round2 verifies the broadcasts of the n participants, its own included,
+// and returns the shares to send privately to the other participants
+func (p *DKGParticipant) Round2(msgs []*DKGRound1Msg) ([]*DKGRound2Msg, error) {
+	if len(msgs) != p.n {
+		return nil, errInvalidDKGMessage
+	}
+	sorted := make([]*DKGRound1Msg, p.n)
+	for _, msg := range msgs {
+		if err := p.checkRound1(msg); err != nil {
+			return nil, err
+		}
+		i := msg.ID.Int64() - 1
+		if sorted[i] != nil {
+			return nil, errInvalidDKGMessage
+		}
+		sorted[i] = msg
+	}
+	if !p.c.group.Equal(sorted[p.id.Int64()-1].Commitment[0], p.c.group.ScalarBaseMult(p.coefficients[0])) {
+		return nil, errInvalidDKGMessage
+	}
+	p.round1 = sorted
+	var res []*DKGRound2Msg
+	for i := 1; i <= p.n; i++ {
+		to := big.NewInt(int64(i))
+		if to.Cmp(p.id) == 0 {
+			continue
+		}
+		res = append(res, &DKGRound2Msg{From: p.id, To: to, Share: p.c.evaluate(p.coefficients, to)})
+	}
+	return res, nil
+}
+
+// Finish checks the n - 1 shares received against the round 1 commitments and
+// returns the key share with the VSS commitment of the group polynomial, from
+// which GroupInfo derives the public key shares
+func (p *DKGParticipant) Finish(msgs []*DKGRound2Msg) (*KeyShare, []group.Point, error) {
+	c := p.c
+	if p.round1 == nil || p.coefficients == nil || len(msgs) != p.n-1 {
+		return nil, nil, errInvalidDKGMessage
+	}
+	secret := c.evaluate(p.coefficients, p.id)
+	seen := make([]bool, p.n)
+	seen[p.id.Int64()-1] = true
+	for _, msg := range msgs {
+		if msg == nil || msg.To == nil || msg.To.Cmp(p.id) != 0 || msg.From == nil || msg.Share == nil {
+			return nil, nil, errInvalidDKGMessage
+		}
+		if msg.From.Sign() <= 0 || msg.From.Cmp(big.NewInt(int64(p.n))) > 0 || seen[msg.From.Int64()-1] {
+			return nil, nil, errInvalidDKGMessage
+		}
+		seen[msg.From.Int64()-1] = true
+		commitment := p.round1[msg.From.Int64()-1].Commitment
+		if !c.group.Equal(c.group.ScalarBaseMult(msg.Share), c.commitmentAt(commitment, p.id)) {
+			return nil, nil, errInvalidShare
+		}
+		secret.Add(secret, msg.Share)
+	}
+	secret.Mod(secret, c.group.Order())
+	// the group commitment is the coefficient-wise sum of the commitments
+	commitment := make([]group.Point, p.t)
+	for j := range commitment {
+		commitment[j] = c.group.Identity()
+		for _, msg := range p.round1 {
+			commitment[j] = c.group.Add(commitment[j], msg.Commitment[j])
+		}
+	}
+	// the polynomial is no longer needed
+	p.coefficients = nil
+	return &KeyShare{
+		ID:             p.id,
+		Secret:         secret,
+		PublicKey:      c.group.ScalarBaseMult(secret),
+		GroupPublicKey: commitment[0],
+	}, commitment, nil
+}
@@ -0,0 +1,347 @@
+package frost
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+	"sort"
+	"sync"
+
+	"scrypto/ecc/group"
+)
+
+// Nonce is the secret of the first round of a signer, it signs once
+type Nonce struct {
+	mu      sync.Mutex
+	id      *big.Int
+	hiding  *big.Int
+	binding *big.Int
+	// commitment is the public counterpart sent to the coordinator
+	commitment *Commitment
+}
+
+// Commitment is the message of the first round: [d]G and [e]G of the hiding
+// and binding nonces of participant ID
+type Commitment struct {
+	ID      *big.Int
+	Hiding  group.Point
+	Binding group.Point
+}
+
+// SignatureShare is the message of the second round
+type SignatureShare struct {
+	ID *big.Int
+	Z  *big.Int
+}
+
+// Signature is a Schnorr signature (R, z) verified as [z]G = R + [c]PK with
+// c = H2(R || PK || m)
+type Signature struct {
+	R group.Point
+	Z *big.Int
+}
+
+var (
+	errNonceUsed           = errors.New("nonce already used")
+	errInvalidCommitments  = errors.New("commitment list is not sorted by distinct identifiers")
+	errMissingCommitment   = errors.New("commitment of the signer not in the list")
+	errMissingShare        = errors.New("signature share of a participant is missing")
+	errInvalidMessageBytes = errors.New("invalid message encoding")
+)
+
+// nonceGenerate returns H3(random || SerializeScalar(secret)), the secret
+// protects against a weak random source
+func (c *Ciphersuite) nonceGenerate(randomBytes []byte, secret *big.Int) *big.Int {
+	return c.h3(append(append([]byte{}, randomBytes...), c.SerializeScalar(secret)...))
+}
+
+// Commit runs the first round: it draws the nonces of share and returns them
+// with the commitment to broadcast
+func (c *Ciphersuite) Commit(share *KeyShare, random io.Reader) (*Nonce, *Commitment, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	hidingRandom, bindingRandom := make([]byte, 32), make([]byte, 32)
+	if _, err := io.ReadFull(random, hidingRandom); err != nil {
+		return nil, nil, err
+	}
+	if _, err := io.ReadFull(random, bindingRandom); err != nil {
+		return nil, nil, err
+	}
+	nonce, commitment := c.commitWithRandomness(share, hidingRandom, bindingRandom)
+	return nonce, commitment, nil
+}
+
+func (c *Ciphersuite) commitWithRandomness(share *KeyShare, hidingRandom, bindingRandom []byte) (*Nonce, *Commitment) {
+	nonce := &Nonce{
+		id:      share.ID,
+		hiding:  c.nonceGenerate(hidingRandom, share.Secret),
+		binding: c.nonceGenerate(bindingRandom, share.Secret),
+	}
+	nonce.commitment = &Commitment{
+		ID:      share.ID,
+		Hiding:  c.group.ScalarBaseMult(nonce.hiding),
+		Binding: c.group.ScalarBaseMult(nonce.binding),
+	}
+	return nonce, nonce.commitment
+}
+
+// checkCommitments checks that the list is sorted by distinct identifiers and
+// made of elements other than the identity
+func (c *Ciphersuite) checkCommitments(commitments []*Commitment) error {
+	if len(commitments) == 0 {
+		return errInvalidCommitments
+	}
+	for i, cm := range commitments {
+		if cm == nil || !c.isIdentifier(cm.ID) || c.group.IsIdentity(cm.Hiding) || c.group.IsIdentity(cm.Binding) {
+			return errInvalidCommitments
+		}
+		if i > 0 && commitments[i-1].ID.Cmp(cm.ID) >= 0 {
+			return errInvalidCommitments
+		}
+	}
+	return nil
+}
+
+// SortCommitments sorts a commitment list by identifier in place
+func SortCommitments(commitments []*Commitment) {
+	sort.Slice(commitments, func(i, j int) bool {
+		return commitments[i].ID.Cmp(commitments[j].ID) < 0
+	})
+}
+
+// encodeCommitments is encode_group_commitment_list: the concatenation of
+// identifier || hiding || binding of every commitment
+func (c *Ciphersuite) encodeCommitments(commitments []*Commitment) []byte {
+	var res []byte
+	for _, cm := range commitments {
+		res = append(res, cm.Bytes(c)...)
+	}
+	return res
+}
+
+// bindingFactors returns rho_i = H1(PK || H4(m) || H5(list) || i) of every
+// participant in the list
+func (c *Ciphersuite) bindingFactors(pk group.Point, commitments []*Commitment, message []byte) []*big.Int {
+	prefix := c.mustSerializeElement(pk)
+	prefix = append(prefix, c.h4(message)...)
+	prefix = append(prefix, c.h5(c.encodeCommitments(commitments))...)
+	res := make([]*big.Int, len(commitments))
+	for i, cm := range commitments {
+		res[i] = c.h1(append(append([]byte{}, prefix...), c.SerializeScalar(cm.ID)...))
+	}
+	return res
+}
+
+// groupCommitment returns R = sum of D_i + [rho_i]E_i
+func (c *Ciphersuite) groupCommitment(commitments []*Commitment, rhos []*big.Int) group.Point {
+	res := c.group.Identity()
+	for i, cm := range commitments {
+		res = c.group.Add(res, c.group.Add(cm.Hiding, c.group.ScalarMult(cm.Binding, rhos[i])))
+	}
+	return res
+}
+
+// challenge returns c = H2(R || PK || m)
+func (c *Ciphersuite) challenge(r, pk group.Point, message []byte) *big.Int {
+	data := append(c.mustSerializeElement(r), c.mustSerializeElement(pk)...)
+	return c.h2(append(data, message...))
+}
+
+// lagrangeCoefficient returns the coefficient at 0 of id among the identifiers
+// of the commitment list
+func (c *Ciphersuite) lagrangeCoefficient(id *big.Int, commitments []*Commitment) *big.Int {
+	n := c.group.Order()
+	num, den := big.NewInt(1), big.NewInt(1)
+	for _, cm := range commitments {
+		if cm.ID.Cmp(id) == 0 {
+			continue
+		}
+		num.Mod(num.Mul(num, cm.ID), n)
+		den.Mod(den.Mul(den, new(big.Int).Sub(cm.ID, id)), n)
+	}
+	return num.Mod(num.Mul(num, den.Mod(den, n).ModInverse(den, n)), n)
+}
+
+// session returns the binding factors, the group commitment and the challenge
+// shared by the signers of message
+func (c *Ciphersuite) session(pk group.Point, message []byte, commitments []*Commitment) ([]*big.Int, group.Point, *big.Int, error) {
+	if err := c.checkCommitments(commitments); err != nil {
+		return nil, nil, nil, err
+	}
+	rhos := c.bindingFactors(pk, commitments, message)
+	r := c.groupCommitment(commitments, rhos)
+	if c.group.IsIdentity(r) {
+		return nil, nil, nil, errIdentityElement
+	}
+	return rhos, r, c.challenge(r, pk, message), nil
+}
+
+// index returns the position of id in the commitment list, or -1
+func index(id *big.Int, commitments []*Commitment) int {
+	for i, cm := range commitments {
+		if cm.ID.Cmp(id) == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// Sign runs the second round on the commitment list chosen by the coordinator,
+// the nonce is erased so that it never signs twice
+func (c *Ciphersuite) Sign(share *KeyShare, nonce *Nonce, message []byte, commitments []*Commitment) (*SignatureShare, error) {
+	nonce.mu.Lock()
+	defer nonce.mu.Unlock()
+	if nonce.hiding == nil {
+		return nil, errNonceUsed
+	}
+	if nonce.id.Cmp(share.ID) != 0 {
+		return nil, errMissingCommitment
+	}
+	rhos, _, challenge, err := c.session(share.GroupPublicKey, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+	i := index(share.ID, commitments)
+	if i < 0 || !c.group.Equal(commitments[i].Hiding, nonce.commitment.Hiding) || !c.group.Equal(commitments[i].Binding, nonce.commitment.Binding) {
+		return nil, errMissingCommitment
+	}
+	n := c.group.Order()
+	lambda := c.lagrangeCoefficient(share.ID, commitments)
+	z := new(big.Int).Mul(nonce.binding, rhos[i])
+	z.Add(z, nonce.hiding)
+	z.Add(z, new(big.Int).Mul(new(big.Int).Mul(lambda, share.Secret), challenge))
+	nonce.hiding, nonce.binding = nil, nil
+	return &SignatureShare{ID: share.ID, Z: z.Mod(z, n)}, nil
+}
+
+// VerifyShare checks the signature share of the participant of public key
+// share pkShare: [z_i]G = D_i + [rho_i]E_i + [c * lambda_i]PK_i
+func (c *Ciphersuite) VerifyShare(pk, pkShare group.Point, message []byte, commitments []*Commitment, share *SignatureShare) bool {
+	if share == nil || share.Z == nil || share.Z.Sign() < 0 || share.Z.Cmp(c.group.Order()) >= 0 {
+		return false
+	}
+	rhos, _, challenge, err := c.session(pk, message, commitments)
+	if err != nil {
+		return false
+	}
+	i := index(share.ID, commitments)
+	if i < 0 {
+		return false
+	}
+	cm := commitments[i]
+	r := c.group.Add(cm.Hiding, c.group.ScalarMult(cm.Binding, rhos[i]))
+	lambda := c.lagrangeCoefficient(share.ID, commitments)
+	right := c.group.Add(r, c.group.ScalarMult(pkShare, new(big.Int).Mul(challenge, lambda)))
+	return c.group.Equal(c.group.ScalarBaseMult(share.Z), right)
+}
+
+// Aggregate sums the signature shares of every participant of the commitment
+// list, a wrong share gives a signature that does not verify and is found
+// with VerifyShare
+func (c *Ciphersuite) Aggregate(pk group.Point, message []byte, commitments []*Commitment, shares []*SignatureShare) (*Signature, error) {
+	_, r, _, err := c.session(pk, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+	if len(shares) != len(commitments) {
+		return nil, errMissingShare
+	}
+	z := new(big.Int)
+	seen := make([]bool, len(commitments))
+	for _, s := range shares {
+		i := -1
+		if s != nil && s.Z != nil {
+			i = index(s.ID, commitments)
+		}
+		if i < 0 || seen[i] {
+			return nil, errMissingShare
+		}
+		seen[i] = true
+		z.Add(z, s.Z)
+	}
+	return &Signature{R: r, Z: z.Mod(z, c.group.Order())}, nil
+}
+
+// Verify checks a signature under the group public key
+func (c *Ciphersuite) Verify(pk group.Point, message []byte, sig *Signature) bool {
+	if sig == nil || sig.R == nil || sig.Z == nil || sig.Z.Sign() < 0 || sig.Z.Cmp(c.group.Order()) >= 0 {
+		return false
+	}
+	if c.group.IsIdentity(pk) || c.group.IsIdentity(sig.R) {
+		return false
+	}
+	challenge := c.challenge(sig.R, pk, message)
+	right := c.group.Add(sig.R, c.group.ScalarMult(pk, challenge))
+	return c.group.Equal(c.group.ScalarBaseMult(sig.Z), right)
+}
+
+// Bytes encodes a commitment as identifier || hiding || binding
+func (cm *Commitment) Bytes(c *Ciphersuite) []byte {
+	res := c.SerializeScalar(cm.ID)
+	res = append(res, c.mustSerializeElement(cm.Hiding)...)
+	return append(res, c.mustSerializeElement(cm.Binding)...)
+}
+
+// CommitmentFromBytes decodes a commitment
+func (c *Ciphersuite) CommitmentFromBytes(data []byte) (*Commitment, error) {
+	if len(data) != 32+2*c.elementSize {
+		return nil, errInvalidMessageBytes
+	}
+	id, err := c.DeserializeScalar(data[:32])
+	if err != nil || id.Sign() == 0 {
+		return nil, errInvalidIdentifier
+	}
+	hiding, err := c.DeserializeElement(data[32 : 32+c.elementSize])
+	if err != nil {
+		return nil, err
+	}
+	binding, err := c.DeserializeElement(data[32+c.elementSize:])
+	if err != nil {
+		return nil, err
+	}
+	return &Commitment{ID: id, Hiding: hiding, Binding: binding}, nil
+}
+
+// Bytes encodes a signature share as identifier || z
+func (s *SignatureShare) Bytes(c *Ciphersuite) []byte {
+	return append(c.SerializeScalar(s.ID), c.SerializeScalar(s.Z)...)
+}
+
+// SignatureShareFromBytes decodes a signature share
+func (c *Ciphersuite) SignatureShareFromBytes(data []byte) (*SignatureShare, error) {
+	if len(data) != 64 {
+		return nil, errInvalidMessageBytes
+	}
+	id, err := c.DeserializeScalar(data[:32])
+	if err != nil || id.Sign() == 0 {
+		return nil, errInvalidIdentifier
+	}
+	z, err := c.DeserializeScalar(data[32:])
+	if err != nil {
+		return nil, err
+	}
+	return &SignatureShare{ID: id, Z: z}, nil
+}
+
+// Bytes encodes a signature as R || z
+func (sig *Signature) Bytes(c *Ciphersuite) []byte {
+	return append(c.mustSerializeElement(sig.R), c.SerializeScalar(sig.Z)...)
+}
+
+// SignatureFromBytes decodes a signature
+func (c *Ciphersuite) SignatureFromBytes(data []byte) (*Signature, error) {
+	if len(data) != c.elementSize+32 {
+		return nil, errInvalidMessageBytes
+	}
+	r, err := c.DeserializeElement(data[:c.elementSize])
+	if err != nil {
+		return nil, err
+	}
+	z, err := c.DeserializeScalar(data[c.elementSize:])
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{R: r, Z: z}, nil
+}
@@ -0,0 +1,316 @@
+package frost
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"scrypto/ecc/group"
+)
+
+func fromHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func scalarFromHex(c *Ciphersuite, s string) *big.Int {
+	k, err := c.DeserializeScalar(fromHex(s))
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+func TestCiphersuite_TrustedDealerKeyGen(t *testing.T) {
+	// group keys and shares of the test vectors of RFC 9591, 2-of-3
+	vectors := []struct {
+		c                   *Ciphersuite
+		secret, coefficient string
+		pk                  string
+		shares              []string
+	}{
+		{
+			Ristretto255,
+			"1b25a55e463cfd15cf14a5d3acc3d15053f08da49c8afcf3ab265f2ebc4f970b",
+			"410f8b744b19325891d73736923525a4f596c805d060dfb9c98009d34e3fec02",
+			"e2a62f39eede11269e3bd5a7d97554f5ca384f9f6d3dd9c3c0d05083c7254f57",
+			[]string{
+				"5c3430d391552f6e60ecdc093ff9f6f4488756aa6cebdbad75a768010b8f830e",
+				"b06fc5eac20b4f6e1b271d9df2343d843e1e1fb03c4cbb673f2872d459ce6f01",
+				"f17e505f0e2581c6acfe54d3846a622834b5e7b50cad9a2109a97ba7a80d5c04",
+			},
+		},
+		{
+			P256,
+			"8ba9bba2e0fd8c4767154d35a0b7562244a4aaf6f36c8fb8735fa48b301bd8de",
+			"80f25e6c0709353e46bfbe882a11bdbb1f8097e46340eb8673b7e14556e6c3a4",
+			"023a309ad94e9fe8a7ba45dfc58f38bf091959d3c99cfbd02b4dc00585ec45ab70",
+			[]string{
+				"0c9c1a0fe806c184add50bbdcac913dda73e482daf95dcb9f35dbb0d8a9f7731",
+				"8d8e787bef0ff6c2f494ca45f4dad198c6bee01212d6c84067159c52e1863ad5",
+				"0e80d6e8f6192c003b5488ce1eec8f5429587d48cf001541e713b2d53c09d928",
+			},
+		},
+	}
+	for _, v := range vectors {
+		c := v.c
+		coefficients := []*big.Int{scalarFromHex(c, v.secret), scalarFromHex(c, v.coefficient)}
+		shares, commitment := c.dealShares(coefficients, 3)
+		pk, pkShares, err := c.GroupInfo(3, commitment)
+		if err != nil {
+			panic(err)
+		}
+		if !bytes.Equal(c.mustSerializeElement(pk), fromHex(v.pk)) {
+			t.Errorf("%s: group public key not match", c.ID())
+		}
+		for i, s := range shares {
+			if !bytes.Equal(c.SerializeScalar(s.Secret), fromHex(v.shares[i])) {
+				t.Errorf("%s: share %d not match", c.ID(), i+1)
+			}
+			if !c.VSSVerify(s, commitment) || !c.group.Equal(pkShares[i], s.PublicKey) {
+				t.Errorf("%s: share %d not verified", c.ID(), i+1)
+			}
+		}
+		shares[0].Secret = new(big.Int).Add(shares[0].Secret, big.NewInt(1))
+		if c.VSSVerify(shares[0], commitment) {
+			t.Errorf("%s: wrong share verified", c.ID())
+		}
+	}
+	if _, _, err := P256.TrustedDealerKeyGen(nil, 4, 3, nil); err == nil {
+		t.Error("threshold above the number of participants accepted")
+	}
+}
+
+func TestCiphersuite_Sign(t *testing.T) {
+	// FROST(P-256, SHA-256) test vector of RFC 9591, participants 1 and 3
+	c := P256
+	coefficients := []*big.Int{
+		scalarFromHex(c, "8ba9bba2e0fd8c4767154d35a0b7562244a4aaf6f36c8fb8735fa48b301bd8de"),
+		scalarFromHex(c, "80f25e6c0709353e46bfbe882a11bdbb1f8097e46340eb8673b7e14556e6c3a4"),
+	}
+	shares, _ := c.dealShares(coefficients, 3)
+	m := fromHex("74657374")
+	n1, c1 := c.commitWithRandomness(shares[0],
+		fromHex("ec4c891c85fee802a9d757a67d1252e7f4e5efb8a538991ac18fbd0e06fb6fd3"),
+		fromHex("9334e29d09061223f69a09421715a347e4e6deba77444c8f42b0c833f80f4ef9"))
+	n3, c3 := c.commitWithRandomness(shares[2],
+		fromHex("c0451c5a0a5480d6c1f860e5db7d655233dca2669fd90ff048454b8ce983367b"),
+		fromHex("2ba5f7793ae700e40e78937a82f407dd35e847e33d1e607b5c7eb6ed2a8ed799"))
+	if !bytes.Equal(c.SerializeScalar(n1.hiding), fromHex("9f0542a5ba879a58f255c09f06da7102ef6a2dec6279700c656d58394d8facd4")) ||
+		!bytes.Equal(c.SerializeScalar(n1.binding), fromHex("6513dfe7429aa2fc972c69bb495b27118c45bbc6e654bb9dc9be55385b55c0d7")) {
+		t.Error("nonces not match")
+	}
+	expected := fromHex("0000000000000000000000000000000000000000000000000000000000000001" +
+		"0213b3e6298bf8ad46fd5e9389519a8665d63d98f4ec6a1fcca434e809d2d8070e" +
+		"02188ff1390bf69374d7b272e454b1878ef10a6b6ea3ff36f114b300b4dbd5233b")
+	if !bytes.Equal(c1.Bytes(c), expected) {
+		t.Error("commitment not match")
+	}
+	commitments := []*Commitment{c1, c3}
+	rhos := c.bindingFactors(shares[0].GroupPublicKey, commitments, m)
+	if !bytes.Equal(c.SerializeScalar(rhos[0]), fromHex("7925f0d4693f204e6e59233e92227c7124664a99739d2c06b81cf64ddf90559e")) {
+		t.Error("binding factor not match")
+	}
+	z1, err := c.Sign(shares[0], n1, m, commitments)
+	if err != nil {
+		panic(err)
+	}
+	z3, err := c.Sign(shares[2], n3, m, commitments)
+	if err != nil {
+		panic(err)
+	}
+	if !bytes.Equal(c.SerializeScalar(z1.Z), fromHex("400308eaed7a2ddee02a265abe6a1cfe04d946ee8720768899619cfabe7a3aeb")) ||
+		!bytes.Equal(c.SerializeScalar(z3.Z), fromHex("561da3c179edbb0502d941bb3e3ace3c37d122aaa46fb54499f15f3a3331de44")) {
+		t.Error("signature shares not match")
+	}
+	sig, err := c.Aggregate(shares[0].GroupPublicKey, m, commitments, []*SignatureShare{z3, z1})
+	if err != nil {
+		panic(err)
+	}
+	expected = fromHex("026d8d434874f87bdb7bc0dfd239b2c00639044f9dcb195e9a04426f70bfa4b70d" +
+		"9620acac6767e8e3e3036815fca4eb3a3caa69992b902bcd3352fc34f1ac192f")
+	if !bytes.Equal(sig.Bytes(c), expected) {
+		t.Error("signature not match")
+	}
+	if !c.Verify(shares[0].GroupPublicKey, m, sig) {
+		t.Error("signature not verified")
+	}
+	if _, err := c.Sign(shares[0], n1, m, commitments); err == nil {
+		t.Error("nonce signed twice")
+	}
+}
+
+func TestCiphersuite_SignRistretto255(t *testing.T) {
+	// key of the FROST(ristretto255, SHA-512) test vector of RFC 9591,
+	// participants 1 and 3, the challenge is checked as H2 of section 6.2:
+	// SHA-512(contextString || "chal" || R || PK || m) mod L, little-endian
+	c := Ristretto255
+	coefficients := []*big.Int{
+		scalarFromHex(c, "1b25a55e463cfd15cf14a5d3acc3d15053f08da49c8afcf3ab265f2ebc4f970b"),
+		scalarFromHex(c, "410f8b744b19325891d73736923525a4f596c805d060dfb9c98009d34e3fec02"),
+	}
+	shares, _ := c.dealShares(coefficients, 3)
+	pkShares := []group.Point{shares[0].PublicKey, shares[1].PublicKey, shares[2].PublicKey}
+	pk := shares[0].GroupPublicKey
+	m := fromHex("74657374")
+	sig, _, _, err := signWith(c, []*KeyShare{shares[0], shares[2]}, pkShares, m)
+	if err != nil {
+		panic(err)
+	}
+	h := sha512.New()
+	h.Write([]byte("FROST-RISTRETTO255-SHA512-v1chal"))
+	h.Write(c.mustSerializeElement(sig.R))
+	h.Write(c.mustSerializeElement(pk))
+	h.Write(m)
+	challenge := new(big.Int).Mod(leToInt(h.Sum(nil)), c.group.Order())
+	g := c.group
+	if !g.Equal(g.ScalarBaseMult(sig.Z), g.Add(sig.R, g.ScalarMult(pk, challenge))) {
+		t.Error("signature not verified with the challenge of RFC 9591")
+	}
+	if !c.Verify(pk, m, sig) {
+		t.Error("signature not verified")
+	}
+}
+
+// signWith runs the two rounds with the given shares, the messages go through
+// their encodings
+func signWith(c *Ciphersuite, shares []*KeyShare, pkShares []group.Point, m []byte) (*Signature, []*Commitment, []*SignatureShare, error) {
+	nonces := make([]*Nonce, len(shares))
+	var commitments []*Commitment
+	for i, s := range shares {
+		nonce, cm, err := c.Commit(s, nil)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		nonces[i] = nonce
+		if cm, err = c.CommitmentFromBytes(cm.Bytes(c)); err != nil {
+			return nil, nil, nil, err
+		}
+		commitments = append(commitments, cm)
+	}
+	SortCommitments(commitments)
+	pk := shares[0].GroupPublicKey
+	var sigShares []*SignatureShare
+	for i, s := range shares {
+		z, err := c.Sign(s, nonces[i], m, commitments)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if z, err = c.SignatureShareFromBytes(z.Bytes(c)); err != nil {
+			return nil, nil, nil, err
+		}
+		if !c.VerifyShare(pk, pkShares[s.ID.Int64()-1], m, commitments, z) {
+			return nil, nil, nil, errInvalidShare
+		}
+		sigShares = append(sigShares, z)
+	}
+	sig, err := c.Aggregate(pk, m, commitments, sigShares)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sig, err = c.SignatureFromBytes(sig.Bytes(c))
+	return sig, commitments, sigShares, err
+}
+
+func TestCiphersuite_Aggregate(t *testing.T) {
+	m := []byte("threshold message")
+	for _, c := range []*Ciphersuite{Ristretto255, P256} {
+		shares, commitment, err := c.TrustedDealerKeyGen(nil, 3, 5, nil)
+		if err != nil {
+			panic(err)
+		}
+		pk, pkShares, err := c.GroupInfo(5, commitment)
+		if err != nil {
+			panic(err)
+		}
+		signers := []*KeyShare{shares[4], shares[1], shares[2]}
+		sig, commitments, sigShares, err := signWith(c, signers, pkShares, m)
+		if err != nil {
+			panic(err)
+		}
+		if !c.Verify(pk, m, sig) {
+			t.Errorf("%s: signature not verified", c.ID())
+		}
+		if c.Verify(pk, []byte("another message"), sig) {
+			t.Errorf("%s: signature verified on another message", c.ID())
+		}
+		// a wrong share is found by VerifyShare and breaks the aggregate
+		sigShares[0].Z = new(big.Int).Mod(new(big.Int).Add(sigShares[0].Z, big.NewInt(1)), c.group.Order())
+		if c.VerifyShare(pk, pkShares[sigShares[0].ID.Int64()-1], m, commitments, sigShares[0]) {
+			t.Errorf("%s: wrong share verified", c.ID())
+		}
+		if sig, err = c.Aggregate(pk, m, commitments, sigShares); err != nil {
+			panic(err)
+		}
+		if c.Verify(pk, m, sig) {
+			t.Errorf("%s: aggregate of a wrong share verified", c.ID())
+		}
+		if _, err := c.Aggregate(pk, m, commitments, sigShares[1:]); err == nil {
+			t.Errorf("%s: aggregated without a signer", c.ID())
+		}
+		// the commitment list must be sorted
+		commitments[0], commitments[1] = commitments[1], commitments[0]
+		if _, err := c.Aggregate(pk, m, commitments, sigShares); err == nil {
+			t.Errorf("%s: unsorted commitment list accepted", c.ID())
+		}
+	}
+}
+
+func TestDKGParticipant_Finish(t *testing.T) {
+	for _, c := range []*Ciphersuite{Ristretto255, P256} {
+		const threshold, n = 2, 3
+		participants := make([]*DKGParticipant, n)
+		round1 := make([]*DKGRound1Msg, n)
+		for i := range participants {
+			p, msg, err := c.NewDKGParticipant(i+1, threshold, n, nil)
+			if err != nil {
+				panic(err)
+			}
+			participants[i], round1[i] = p, msg
+		}
+		inbox := make([][]*DKGRound2Msg, n)
+		for _, p := range participants {
+			msgs, err := p.Round2(round1)
+			if err != nil {
+				panic(err)
+			}
+			for _, msg := range msgs {
+				inbox[msg.To.Int64()-1] = append(inbox[msg.To.Int64()-1], msg)
+			}
+		}
+		shares := make([]*KeyShare, n)
+		var commitment []group.Point
+		for i, p := range participants {
+			share, cm, err := p.Finish(inbox[i])
+			if err != nil {
+				panic(err)
+			}
+			if !c.VSSVerify(share, cm) {
+				t.Errorf("%s: share %d not verified", c.ID(), i+1)
+			}
+			shares[i], commitment = share, cm
+		}
+		pk, pkShares, err := c.GroupInfo(n, commitment)
+		if err != nil {
+			panic(err)
+		}
+		sig, _, _, err := signWith(c, []*KeyShare{shares[0], shares[2]}, pkShares, []byte("m"))
+		if err != nil {
+			panic(err)
+		}
+		if !c.Verify(pk, []byte("m"), sig) {
+			t.Errorf("%s: signature of the DKG key not verified", c.ID())
+		}
+		// a proof of knowledge bound to another identifier is rejected
+		forged := *round1[1]
+		forged.ID = big.NewInt(3)
+		if _, err := participants[0].Round2([]*DKGRound1Msg{round1[0], round1[1], &forged}); err == nil {
+			t.Errorf("%s: replayed proof of knowledge accepted", c.ID())
+		}
+	}
+}
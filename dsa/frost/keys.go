@@ -0,0 +1,145 @@
+package frost
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"scrypto/ecc/group"
+)
+
+// KeyShare is the signing share of a participant: the evaluation at ID of a
+// polynomial of degree t - 1 whose constant term is the group secret key
+type KeyShare struct {
+	ID             *big.Int
+	Secret         *big.Int
+	PublicKey      group.Point // public key share [Secret]G
+	GroupPublicKey group.Point
+}
+
+var (
+	errInvalidThreshold  = errors.New("need 2 <= t <= n participants")
+	errInvalidIdentifier = errors.New("identifier is zero or out of range")
+	errInvalidShare      = errors.New("share does not match the commitment")
+)
+
+// checkParameters checks 2 <= t <= n and that n identifiers fit in the scalars
+func (c *Ciphersuite) checkParameters(t, n int) error {
+	if t < 2 || t > n || big.NewInt(int64(n)).Cmp(c.group.Order()) >= 0 {
+		return errInvalidThreshold
+	}
+	return nil
+}
+
+// polynomial returns the coefficients of a random polynomial of degree t - 1
+// with constant term secret
+func (c *Ciphersuite) polynomial(secret *big.Int, t int, random io.Reader) ([]*big.Int, error) {
+	coefficients := []*big.Int{new(big.Int).Mod(secret, c.group.Order())}
+	for i := 1; i < t; i++ {
+		a, err := group.RandomScalar(c.group, random)
+		if err != nil {
+			return nil, err
+		}
+		coefficients = append(coefficients, a)
+	}
+	return coefficients, nil
+}
+
+// evaluate returns f(x) with Horner's method
+func (c *Ciphersuite) evaluate(coefficients []*big.Int, x *big.Int) *big.Int {
+	res := new(big.Int)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		res.Mul(res, x)
+		res.Add(res, coefficients[i])
+		res.Mod(res, c.group.Order())
+	}
+	return res
+}
+
+// commit returns the VSS commitment [a_0]G, ..., [a_{t-1}]G of a polynomial
+func (c *Ciphersuite) commit(coefficients []*big.Int) []group.Point {
+	res := make([]group.Point, len(coefficients))
+	for i, a := range coefficients {
+		res[i] = c.group.ScalarBaseMult(a)
+	}
+	return res
+}
+
+// commitmentAt returns [f(x)]G = sum of [x^j]C_j from the VSS commitment of f
+func (c *Ciphersuite) commitmentAt(commitment []group.Point, x *big.Int) group.Point {
+	res := c.group.Identity()
+	power := big.NewInt(1)
+	for _, p := range commitment {
+		res = c.group.Add(res, c.group.ScalarMult(p, power))
+		power = new(big.Int).Mod(new(big.Int).Mul(power, x), c.group.Order())
+	}
+	return res
+}
+
+// TrustedDealerKeyGen splits secret in n shares, any t of which sign, and
+// returns them with the VSS commitment of the polynomial, a random secret is
+// drawn when secret is nil
+func (c *Ciphersuite) TrustedDealerKeyGen(secret *big.Int, t, n int, random io.Reader) ([]*KeyShare, []group.Point, error) {
+	if err := c.checkParameters(t, n); err != nil {
+		return nil, nil, err
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+	if secret == nil {
+		var err error
+		if secret, err = group.RandomScalar(c.group, random); err != nil {
+			return nil, nil, err
+		}
+	}
+	coefficients, err := c.polynomial(secret, t, random)
+	if err != nil {
+		return nil, nil, err
+	}
+	shares, commitment := c.dealShares(coefficients, n)
+	return shares, commitment, nil
+}
+
+// dealShares evaluates the polynomial at the identifiers 1 to n
+func (c *Ciphersuite) dealShares(coefficients []*big.Int, n int) ([]*KeyShare, []group.Point) {
+	commitment := c.commit(coefficients)
+	shares := make([]*KeyShare, n)
+	for i := range shares {
+		id := big.NewInt(int64(i + 1))
+		sk := c.evaluate(coefficients, id)
+		shares[i] = &KeyShare{
+			ID:             id,
+			Secret:         sk,
+			PublicKey:      c.group.ScalarBaseMult(sk),
+			GroupPublicKey: commitment[0],
+		}
+	}
+	return shares, commitment
+}
+
+// VSSVerify checks a share against the VSS commitment of the dealer
+func (c *Ciphersuite) VSSVerify(share *KeyShare, commitment []group.Point) bool {
+	if share == nil || len(commitment) == 0 || !c.isIdentifier(share.ID) {
+		return false
+	}
+	return c.group.Equal(c.group.ScalarBaseMult(share.Secret), c.commitmentAt(commitment, share.ID))
+}
+
+// GroupInfo derives from a VSS commitment the group public key and the public
+// key shares of the participants 1 to n
+func (c *Ciphersuite) GroupInfo(n int, commitment []group.Point) (group.Point, []group.Point, error) {
+	if err := c.checkParameters(len(commitment), n); err != nil {
+		return nil, nil, err
+	}
+	shares := make([]group.Point, n)
+	for i := range shares {
+		shares[i] = c.commitmentAt(commitment, big.NewInt(int64(i+1)))
+	}
+	return commitment[0], shares, nil
+}
+
+// isIdentifier reports whether id is a non-zero scalar
+func (c *Ciphersuite) isIdentifier(id *big.Int) bool {
+	return id != nil && id.Sign() > 0 && id.Cmp(c.group.Order()) < 0
+}
@@ -0,0 +1,169 @@
+package ring
+
+import (
+	"errors"
+	"math/big"
+
+	"scrypto/ecc/p256Utils"
+)
+
+// CLSAG signs for two keys at once: the signing key P_pi = xG and an auxiliary
+// key C_pi = zG at the same position of a second ring, e.g. a commitment to
+// zero in Monero. The two layers are merged with the aggregation coefficients
+// mu_P and mu_C, so the signature has one response per member like LSAG
+// instead of two like MLSAG. Only the key image of x links signatures.
+const (
+	clsagAgg0DST  = "SCRYPTO-RING-V01-CLSAG-AGG0-P256_XMD:SHA-256"
+	clsagAgg1DST  = "SCRYPTO-RING-V01-CLSAG-AGG1-P256_XMD:SHA-256"
+	clsagRoundDST = "SCRYPTO-RING-V01-CLSAG-ROUND-P256_XMD:SHA-256"
+)
+
+var errInvalidAuxiliary = errors.New("auxiliary keys do not match the ring")
+
+// CLSAGSignature is (c_0, s_0, ..., s_{n-1}) with the key image I = x H_p(P_pi)
+// and the auxiliary image D = z H_p(P_pi)
+type CLSAGSignature struct {
+	KeyImage *p256Utils.CurvePoint
+	D        *p256Utils.CurvePoint
+	C0       *big.Int
+	S        []*big.Int
+}
+
+// clsagSession holds what the signer and the verifier derive from the rings,
+// the images and the message
+type clsagSession struct {
+	// prefix of the round challenges
+	prefix   []byte
+	muP, muC *big.Int
+	// w are the aggregated keys W_i = mu_P P_i + mu_C C_i
+	w Ring
+	// image is the aggregated image mu_P I + mu_C D
+	image *p256Utils.CurvePoint
+}
+
+func newCLSAGSession(ring, auxiliary Ring, keyImage, d *p256Utils.CurvePoint, message []byte) (*clsagSession, error) {
+	keys, err := ring.encode()
+	if err != nil {
+		return nil, err
+	}
+	aux, err := auxiliary.encode()
+	if err != nil || len(auxiliary) != len(ring) {
+		return nil, errInvalidAuxiliary
+	}
+	data := append(append([]byte{}, keys...), aux...)
+	data = append(append(data, marshal(keyImage)...), marshal(d)...)
+	cs := &clsagSession{muP: hashToScalar(clsagAgg0DST, data), muC: hashToScalar(clsagAgg1DST, data)}
+	cs.w = make(Ring, len(ring))
+	for i := range ring {
+		cs.w[i] = linearCombination(cs.muP, ring[i], cs.muC, auxiliary[i])
+	}
+	cs.image = linearCombination(cs.muP, keyImage, cs.muC, d)
+	cs.prefix = append(data, message...)
+	return cs, nil
+}
+
+// clsagChallenge returns c_{i+1} = H(prefix || L_i || R_i)
+func clsagChallenge(prefix []byte, l, r *p256Utils.CurvePoint) *big.Int {
+	data := append(append(append([]byte{}, prefix...), marshal(l)...), marshal(r)...)
+	return hashToScalar(clsagRoundDST, data)
+}
+
+// SignCLSAG signs message with sk, whose public key is in ring, and with z,
+// whose public key is at the same position in auxiliary
+func SignCLSAG(sk, z *big.Int, ring, auxiliary Ring, message []byte) (*CLSAGSignature, error) {
+	pi, err := ring.index(sk)
+	if err != nil {
+		return nil, err
+	}
+	if !isScalar(z) || z.Sign() == 0 || len(auxiliary) != len(ring) || !Link(auxiliary[pi], p256Utils.ScalarBaseMult(z)) {
+		return nil, errInvalidAuxiliary
+	}
+	hpPi, err := hashToPoint(ring[pi])
+	if err != nil {
+		return nil, err
+	}
+	keyImage, d := p256Utils.ScalarMult(hpPi, sk), p256Utils.ScalarMult(hpPi, z)
+	cs, err := newCLSAGSession(ring, auxiliary, keyImage, d, message)
+	if err != nil {
+		return nil, err
+	}
+	// the aggregated secret mu_P x + mu_C z is the logarithm of W_pi
+	secret := new(big.Int).Add(new(big.Int).Mul(cs.muP, sk), new(big.Int).Mul(cs.muC, z))
+	size := len(ring)
+	c, s := make([]*big.Int, size), make([]*big.Int, size)
+	alpha, err := randomScalar()
+	if err != nil {
+		return nil, err
+	}
+	c[(pi+1)%size] = clsagChallenge(cs.prefix, p256Utils.ScalarBaseMult(alpha), p256Utils.ScalarMult(hpPi, alpha))
+	for j := 1; j < size; j++ {
+		i := (pi + j) % size
+		if s[i], err = randomScalar(); err != nil {
+			return nil, err
+		}
+		hp, err := hashToPoint(ring[i])
+		if err != nil {
+			return nil, err
+		}
+		l := linearCombination(s[i], p256Utils.GetBaseGenerator(), c[i], cs.w[i])
+		r := linearCombination(s[i], hp, c[i], cs.image)
+		c[(i+1)%size] = clsagChallenge(cs.prefix, l, r)
+	}
+	s[pi] = secret.Mul(secret, c[pi])
+	s[pi].Sub(alpha, s[pi]).Mod(s[pi], n)
+	return &CLSAGSignature{KeyImage: keyImage, D: d, C0: c[0], S: s}, nil
+}
+
+// VerifyCLSAG checks that sig is a signature of message by a key of ring
+// together with the auxiliary key at the same position
+func VerifyCLSAG(ring, auxiliary Ring, message []byte, sig *CLSAGSignature) bool {
+	if sig == nil || !isPoint(sig.KeyImage) || !isPoint(sig.D) || !isScalar(sig.C0) || len(sig.S) != len(ring) {
+		return false
+	}
+	cs, err := newCLSAGSession(ring, auxiliary, sig.KeyImage, sig.D, message)
+	if err != nil {
+		return false
+	}
+	c := sig.C0
+	for i, p := range ring {
+		if !isScalar(sig.S[i]) {
+			return false
+		}
+		hp, err := hashToPoint(p)
+		if err != nil {
+			return false
+		}
+		l := linearCombination(sig.S[i], p256Utils.GetBaseGenerator(), c, cs.w[i])
+		r := linearCombination(sig.S[i], hp, c, cs.image)
+		c = clsagChallenge(cs.prefix, l, r)
+	}
+	return c.Cmp(sig.C0) == 0
+}
+
+// Bytes encodes sig as I || D || c_0 || s_0 || ... || s_{n-1}, 66 + 32 (n + 1)
+// bytes
+func (sig *CLSAGSignature) Bytes() []byte {
+	res := append(marshal(sig.KeyImage), marshal(sig.D)...)
+	return append(res, scalarsBytes(sig.C0, sig.S)...)
+}
+
+// CLSAGSignatureFromBytes decodes a signature, the ring size follows from the
+// length
+func CLSAGSignatureFromBytes(data []byte) (*CLSAGSignature, error) {
+	if len(data) < 2*PointSize {
+		return nil, errInvalidBytes
+	}
+	keyImage, err := unmarshal(data[:PointSize])
+	if err != nil {
+		return nil, err
+	}
+	d, err := unmarshal(data[PointSize : 2*PointSize])
+	if err != nil {
+		return nil, err
+	}
+	c0, s, err := scalarsFromBytes(data[2*PointSize:])
+	if err != nil {
+		return nil, err
+	}
+	return &CLSAGSignature{KeyImage: keyImage, D: d, C0: c0, S: s}, nil
+}
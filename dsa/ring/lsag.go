@@ -0,0 +1,122 @@
+package ring
+
+import (
+	"math/big"
+
+	"scrypto/ecc/p256Utils"
+)
+
+const lsagDST = "SCRYPTO-RING-V01-LSAG-P256_XMD:SHA-256"
+
+// LSAGSignature is (c_0, s_0, ..., s_{n-1}) with the key image I
+type LSAGSignature struct {
+	KeyImage *p256Utils.CurvePoint
+	C0       *big.Int
+	S        []*big.Int
+}
+
+// lsagChallenge returns c_{i+1} = H(prefix || L_i || R_i)
+func lsagChallenge(prefix []byte, l, r *p256Utils.CurvePoint) *big.Int {
+	data := append(append(append([]byte{}, prefix...), marshal(l)...), marshal(r)...)
+	return hashToScalar(lsagDST, data)
+}
+
+// lsagPrefix binds the challenges to the ring, the key image and the message
+func lsagPrefix(ring Ring, keyImage *p256Utils.CurvePoint, message []byte) ([]byte, error) {
+	res, err := ring.encode()
+	if err != nil {
+		return nil, err
+	}
+	res = append(res, marshal(keyImage)...)
+	return append(res, message...), nil
+}
+
+// SignLSAG signs message with sk, whose public key is in ring
+func SignLSAG(sk *big.Int, ring Ring, message []byte) (*LSAGSignature, error) {
+	pi, err := ring.index(sk)
+	if err != nil {
+		return nil, err
+	}
+	keyImage, err := KeyImage(sk)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := lsagPrefix(ring, keyImage, message)
+	if err != nil {
+		return nil, err
+	}
+	size := len(ring)
+	c, s := make([]*big.Int, size), make([]*big.Int, size)
+	alpha, err := randomScalar()
+	if err != nil {
+		return nil, err
+	}
+	hp, err := hashToPoint(ring[pi])
+	if err != nil {
+		return nil, err
+	}
+	c[(pi+1)%size] = lsagChallenge(prefix, p256Utils.ScalarBaseMult(alpha), p256Utils.ScalarMult(hp, alpha))
+	for j := 1; j < size; j++ {
+		i := (pi + j) % size
+		if s[i], err = randomScalar(); err != nil {
+			return nil, err
+		}
+		if hp, err = hashToPoint(ring[i]); err != nil {
+			return nil, err
+		}
+		l := linearCombination(s[i], p256Utils.GetBaseGenerator(), c[i], ring[i])
+		r := linearCombination(s[i], hp, c[i], keyImage)
+		c[(i+1)%size] = lsagChallenge(prefix, l, r)
+	}
+	// s_pi = alpha - c_pi x closes the ring
+	s[pi] = new(big.Int).Mul(c[pi], sk)
+	s[pi].Sub(alpha, s[pi]).Mod(s[pi], n)
+	return &LSAGSignature{KeyImage: keyImage, C0: c[0], S: s}, nil
+}
+
+// VerifyLSAG checks that sig is a signature of message by a key of ring
+func VerifyLSAG(ring Ring, message []byte, sig *LSAGSignature) bool {
+	if sig == nil || !isPoint(sig.KeyImage) || !isScalar(sig.C0) || len(sig.S) != len(ring) {
+		return false
+	}
+	prefix, err := lsagPrefix(ring, sig.KeyImage, message)
+	if err != nil {
+		return false
+	}
+	c := sig.C0
+	for i, p := range ring {
+		if !isScalar(sig.S[i]) {
+			return false
+		}
+		hp, err := hashToPoint(p)
+		if err != nil {
+			return false
+		}
+		l := linearCombination(sig.S[i], p256Utils.GetBaseGenerator(), c, p)
+		r := linearCombination(sig.S[i], hp, c, sig.KeyImage)
+		c = lsagChallenge(prefix, l, r)
+	}
+	return c.Cmp(sig.C0) == 0
+}
+
+// Bytes encodes sig as I || c_0 || s_0 || ... || s_{n-1}, 33 + 32 (n + 1) bytes
+func (sig *LSAGSignature) Bytes() []byte {
+	return append(marshal(sig.KeyImage), scalarsBytes(sig.C0, sig.S)...)
+}
+
+// LSAGSignatureFromBytes decodes a signature, the ring size follows from the
+// length
+func LSAGSignatureFromBytes(data []byte) (*LSAGSignature, error) {
+	if len(data) < PointSize {
+		return nil, errInvalidBytes
+	}
+	keyImage, err := unmarshal(data[:PointSize])
+	if err != nil {
+		return nil, err
+	}
+	c0, s, err := scalarsFromBytes(data[PointSize:])
+	if err != nil {
+		return nil, err
+	}
+	return &LSAGSignature{KeyImage: keyImage, C0: c0, S: s}, nil
+}
@@ -0,0 +1,171 @@
+// Package ring implements linkable ring signatures on P-256: LSAG of Liu, Wei
+// and Wong as used by Monero, and CLSAG of Goodell, Noether and RandomRun. A
+// signer proves that it holds the secret key of one of the public keys of a
+// ring without revealing which, and publishes the key image I = x H_p(P),
+// which is the same in every signature of the key x: two signatures are linked
+// when their key images are equal, e.g. a second vote of the same member.
+package ring
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"scrypto/ecc/p256Utils"
+	"scrypto/sutils/xmd"
+)
+
+// Ring is the ordered set of public keys among which the signer hides
+type Ring []*p256Utils.CurvePoint
+
+const (
+	// PointSize is the size of a compressed point
+	PointSize = 33
+	// ScalarSize is the size of a scalar
+	ScalarSize = 32
+	// keyImageDST separates H_p from the other uses of the hash to curve
+	keyImageDST = "SCRYPTO-RING-V01-CS01-with-P256_XMD:SHA-256_SSWU_RO_"
+	// hashToScalarL is the number of bytes reduced into a scalar
+	hashToScalarL = 48
+)
+
+var (
+	curve = elliptic.P256()
+	n     = p256Utils.N
+)
+
+var (
+	errRingTooSmall   = errors.New("ring needs at least 2 keys")
+	errInvalidRingKey = errors.New("invalid public key in the ring")
+	errNotInRing      = errors.New("public key of the signer not in the ring")
+	errInvalidSecret  = errors.New("secret key out of range")
+	errInvalidBytes   = errors.New("invalid signature encoding")
+)
+
+// KeyImage returns I = x H_p(xG), the linking tag of the secret key x
+func KeyImage(sk *big.Int) (*p256Utils.CurvePoint, error) {
+	if !isScalar(sk) || sk.Sign() == 0 {
+		return nil, errInvalidSecret
+	}
+	hp, err := hashToPoint(p256Utils.ScalarBaseMult(sk))
+	if err != nil {
+		return nil, err
+	}
+	return p256Utils.ScalarMult(hp, sk), nil
+}
+
+// Link reports whether two key images are equal, that is whether the two
+// signatures that carry them were made with the same secret key
+func Link(i1, i2 *p256Utils.CurvePoint) bool {
+	return i1 != nil && i2 != nil && i1.X.Cmp(i2.X) == 0 && i1.Y.Cmp(i2.Y) == 0
+}
+
+// hashToPoint is H_p of RFC 9380 on the compressed encoding of P
+func hashToPoint(p *p256Utils.CurvePoint) (*p256Utils.CurvePoint, error) {
+	return p256Utils.HashToPoint(marshal(p), []byte(keyImageDST))
+}
+
+// hashToScalar is hash_to_field of RFC 9380 into the scalars with the domain
+// separation tag dst
+func hashToScalar(dst string, data []byte) *big.Int {
+	uniform, err := xmd.Expand(data, []byte(dst), hashToScalarL)
+	if err != nil {
+		panic(err)
+	}
+	res := new(big.Int).SetBytes(uniform)
+	return res.Mod(res, n)
+}
+
+func marshal(p *p256Utils.CurvePoint) []byte {
+	return elliptic.MarshalCompressed(curve, p.X, p.Y)
+}
+
+// unmarshal decodes a compressed point, the identity has no such encoding
+func unmarshal(data []byte) (*p256Utils.CurvePoint, error) {
+	x, y := elliptic.UnmarshalCompressed(curve, data)
+	if x == nil {
+		return nil, errInvalidBytes
+	}
+	return &p256Utils.CurvePoint{Curve: curve, X: x, Y: y}, nil
+}
+
+func isScalar(k *big.Int) bool {
+	return k != nil && k.Sign() >= 0 && k.Cmp(n) < 0
+}
+
+// isPoint reports whether p is a point of the curve other than the identity
+func isPoint(p *p256Utils.CurvePoint) bool {
+	return p != nil && p.X != nil && p.Y != nil && p256Utils.IsOnCurve(p)
+}
+
+func randomScalar() (*big.Int, error) {
+	for {
+		k, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() > 0 {
+			return k, nil
+		}
+	}
+}
+
+// encode checks the ring and returns the concatenation of its keys
+func (r Ring) encode() ([]byte, error) {
+	if len(r) < 2 {
+		return nil, errRingTooSmall
+	}
+	res := make([]byte, 0, len(r)*PointSize)
+	for _, p := range r {
+		if !isPoint(p) {
+			return nil, errInvalidRingKey
+		}
+		res = append(res, marshal(p)...)
+	}
+	return res, nil
+}
+
+// index returns the position of the public key of sk in the ring
+func (r Ring) index(sk *big.Int) (int, error) {
+	if !isScalar(sk) || sk.Sign() == 0 {
+		return 0, errInvalidSecret
+	}
+	pk := p256Utils.ScalarBaseMult(sk)
+	for i, p := range r {
+		if Link(p, pk) {
+			return i, nil
+		}
+	}
+	return 0, errNotInRing
+}
+
+// linearCombination returns [s]a + [c]b
+func linearCombination(s *big.Int, a *p256Utils.CurvePoint, c *big.Int, b *p256Utils.CurvePoint) *p256Utils.CurvePoint {
+	return p256Utils.ScalarAdd(p256Utils.ScalarMult(a, s), p256Utils.ScalarMult(b, c))
+}
+
+// scalarsBytes encodes c0 and the responses
+func scalarsBytes(c0 *big.Int, s []*big.Int) []byte {
+	res := c0.FillBytes(make([]byte, ScalarSize))
+	for _, si := range s {
+		res = append(res, si.FillBytes(make([]byte, ScalarSize))...)
+	}
+	return res
+}
+
+// scalarsFromBytes decodes c0 and the responses, all lower than n
+func scalarsFromBytes(data []byte) (*big.Int, []*big.Int, error) {
+	if len(data)%ScalarSize != 0 || len(data) < 3*ScalarSize {
+		return nil, nil, errInvalidBytes
+	}
+	var scalars []*big.Int
+	for i := 0; i < len(data); i += ScalarSize {
+		k := new(big.Int).SetBytes(data[i : i+ScalarSize])
+		if !isScalar(k) {
+			return nil, nil, errInvalidBytes
+		}
+		scalars = append(scalars, k)
+	}
+	return scalars[0], scalars[1:], nil
+}
@@ -0,0 +1,160 @@
+package ring
+
+import (
+	"math/big"
+	"testing"
+
+	"scrypto/ecc/p256Utils"
+)
+
+// members returns n secret keys and the ring of their public keys
+func members(n int) ([]*big.Int, Ring) {
+	var sks []*big.Int
+	var ring Ring
+	for i := 0; i < n; i++ {
+		sk, pk, err := p256Utils.RandomKeyPair()
+		if err != nil {
+			panic(err)
+		}
+		sks, ring = append(sks, sk), append(ring, pk)
+	}
+	return sks, ring
+}
+
+func TestSignLSAG(t *testing.T) {
+	sks, ring := members(5)
+	m := []byte("vote: yes")
+	for i, sk := range sks {
+		sig, err := SignLSAG(sk, ring, m)
+		if err != nil {
+			panic(err)
+		}
+		if sig, err = LSAGSignatureFromBytes(sig.Bytes()); err != nil {
+			panic(err)
+		}
+		if len(sig.Bytes()) != PointSize+ScalarSize*(len(ring)+1) {
+			t.Error("signature size not match")
+		}
+		if !VerifyLSAG(ring, m, sig) {
+			t.Errorf("signature of member %d not verified", i)
+		}
+		if VerifyLSAG(ring, []byte("vote: no"), sig) {
+			t.Errorf("signature of member %d verified on another message", i)
+		}
+		// the ring is bound to the signature, its order included
+		swapped := append(Ring{}, ring...)
+		swapped[0], swapped[1] = swapped[1], swapped[0]
+		if VerifyLSAG(swapped, m, sig) || VerifyLSAG(ring[1:], m, sig) {
+			t.Errorf("signature of member %d verified under another ring", i)
+		}
+	}
+	outsider, _, _ := p256Utils.RandomKeyPair()
+	if _, err := SignLSAG(outsider, ring, m); err == nil {
+		t.Error("signed by a key outside of the ring")
+	}
+	if _, err := SignLSAG(sks[0], ring[:1], m); err == nil {
+		t.Error("signed with a ring of one key")
+	}
+}
+
+func TestLink(t *testing.T) {
+	sks, ring := members(4)
+	sig1, err := SignLSAG(sks[2], ring, []byte("vote: yes"))
+	if err != nil {
+		panic(err)
+	}
+	sig2, err := SignLSAG(sks[2], ring, []byte("vote: no"))
+	if err != nil {
+		panic(err)
+	}
+	sig3, err := SignLSAG(sks[1], ring, []byte("vote: no"))
+	if err != nil {
+		panic(err)
+	}
+	if !Link(sig1.KeyImage, sig2.KeyImage) {
+		t.Error("two signatures of the same key not linked")
+	}
+	if Link(sig1.KeyImage, sig3.KeyImage) {
+		t.Error("signatures of different keys linked")
+	}
+	// the key image does not depend on the ring nor on the scheme
+	_, others := members(3)
+	sig4, err := SignLSAG(sks[2], append(others, ring[2]), []byte("vote: yes"))
+	if err != nil {
+		panic(err)
+	}
+	zs, aux := members(4)
+	sig5, err := SignCLSAG(sks[2], zs[2], ring, aux, []byte("vote: yes"))
+	if err != nil {
+		panic(err)
+	}
+	if !Link(sig1.KeyImage, sig4.KeyImage) || !Link(sig1.KeyImage, sig5.KeyImage) {
+		t.Error("signatures of the same key under another ring or scheme not linked")
+	}
+	// a forged key image breaks the signature
+	image, err := KeyImage(sks[1])
+	if err != nil {
+		panic(err)
+	}
+	sig1.KeyImage = image
+	if VerifyLSAG(ring, []byte("vote: yes"), sig1) {
+		t.Error("signature verified with the key image of another member")
+	}
+}
+
+func TestSignCLSAG(t *testing.T) {
+	sks, ring := members(4)
+	zs, aux := members(4)
+	m := []byte("transfer")
+	for i := range sks {
+		sig, err := SignCLSAG(sks[i], zs[i], ring, aux, m)
+		if err != nil {
+			panic(err)
+		}
+		if sig, err = CLSAGSignatureFromBytes(sig.Bytes()); err != nil {
+			panic(err)
+		}
+		if len(sig.Bytes()) != 2*PointSize+ScalarSize*(len(ring)+1) {
+			t.Error("signature size not match")
+		}
+		if !VerifyCLSAG(ring, aux, m, sig) {
+			t.Errorf("signature of member %d not verified", i)
+		}
+		if VerifyCLSAG(ring, aux, []byte("another"), sig) || VerifyCLSAG(aux, ring, m, sig) {
+			t.Errorf("signature of member %d verified on another message or rings", i)
+		}
+	}
+	// the auxiliary key must be at the position of the signing key
+	if _, err := SignCLSAG(sks[0], zs[1], ring, aux, m); err == nil {
+		t.Error("signed with the auxiliary key of another position")
+	}
+	if _, err := SignCLSAG(sks[0], zs[0], ring, aux[1:], m); err == nil {
+		t.Error("signed with rings of different sizes")
+	}
+}
+
+func TestLSAGSignatureFromBytes(t *testing.T) {
+	sks, ring := members(3)
+	sig, err := SignLSAG(sks[0], ring, []byte("m"))
+	if err != nil {
+		panic(err)
+	}
+	data := sig.Bytes()
+	if _, err := LSAGSignatureFromBytes(data[:len(data)-1]); err == nil {
+		t.Error("truncated signature decoded")
+	}
+	// a response equal to n is rejected
+	bad := append([]byte{}, data...)
+	p256Utils.N.FillBytes(bad[len(bad)-ScalarSize:])
+	if _, err := LSAGSignatureFromBytes(bad); err == nil {
+		t.Error("response out of range decoded")
+	}
+	bad = append([]byte{}, data...)
+	bad[0] = 0x05
+	if _, err := LSAGSignatureFromBytes(bad); err == nil {
+		t.Error("invalid key image decoded")
+	}
+	if _, err := CLSAGSignatureFromBytes(data[:PointSize]); err == nil {
+		t.Error("short CLSAG signature decoded")
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"golang.org/x/crypto/bn256"
 	"math/big"
 	"scrypto/ecc/bn256Utils"
 	sherMath "scrypto/smath"
@@ -34,11 +33,11 @@ const (
 )
 
 type RingersPK struct {
-	Q  *bn256.G2   `json:"Q"`
-	A  *bn256.G2   `json:"A"`
-	As []*bn256.G2 `json:"As"`
-	N  int         `json:"N"`
-	Z  *bn256.G2   `json:"Z"`
+	Q  *bn256Utils.G2   `json:"Q"`
+	A  *bn256Utils.G2   `json:"A"`
+	As []*bn256Utils.G2 `json:"As"`
+	N  int              `json:"N"`
+	Z  *bn256Utils.G2   `json:"Z"`
 }
 
 // Serialize RingersPK
@@ -74,9 +73,9 @@ func (pk *RingersPK) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	Q, res := new(bn256.G2).Unmarshal(Qbytes)
-	A, res := new(bn256.G2).Unmarshal(Abytes)
-	Z, res := new(bn256.G2).Unmarshal(Zbytes)
+	Q, res := new(bn256Utils.G2).Unmarshal(Qbytes)
+	A, res := new(bn256Utils.G2).Unmarshal(Abytes)
+	Z, res := new(bn256Utils.G2).Unmarshal(Zbytes)
 	if !res {
 		return errors.New("error when unmarshal G2 point")
 	}
@@ -86,7 +85,7 @@ func (pk *RingersPK) UnmarshalJSON(data []byte) error {
 		if err != nil {
 			return err
 		}
-		Ai, res := new(bn256.G2).Unmarshal(AiBytes)
+		Ai, res := new(bn256Utils.G2).Unmarshal(AiBytes)
 		if !res {
 			return errors.New("error when unmarshal G2 point")
 		}
@@ -101,13 +100,13 @@ func (pk *RingersPK) UnmarshalJSON(data []byte) error {
 
 // Ringers Algorithm Signature
 type Sigma struct {
-	Kappa *big.Int    `json:"Kappa"`
-	K     *bn256.G1   `json:"K"`
-	S     *bn256.G1   `json:"S"`
-	Ss    []*bn256.G1 `json:"Ss"`
-	N     int         `json:"N"`
-	C     *bn256.G1   `json:"C"`
-	T     *bn256.G1   `json:"T"`
+	Kappa *big.Int         `json:"Kappa"`
+	K     *bn256Utils.G1   `json:"K"`
+	S     *bn256Utils.G1   `json:"S"`
+	Ss    []*bn256Utils.G1 `json:"Ss"`
+	N     int              `json:"N"`
+	C     *bn256Utils.G1   `json:"C"`
+	T     *bn256Utils.G1   `json:"T"`
 }
 
 // serialize Sigma
@@ -151,10 +150,10 @@ func (sigma *Sigma) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	Kappa := new(big.Int).SetBytes(KappaBytes)
-	K, res := new(bn256.G1).Unmarshal(KBytes)
-	S, res := new(bn256.G1).Unmarshal(SBytes)
-	C, res := new(bn256.G1).Unmarshal(CBytes)
-	T, res := new(bn256.G1).Unmarshal(TBytes)
+	K, res := new(bn256Utils.G1).Unmarshal(KBytes)
+	S, res := new(bn256Utils.G1).Unmarshal(SBytes)
+	C, res := new(bn256Utils.G1).Unmarshal(CBytes)
+	T, res := new(bn256Utils.G1).Unmarshal(TBytes)
 	if !res {
 		return errors.New("error when unmarshal G2 point")
 	}
@@ -164,7 +163,7 @@ func (sigma *Sigma) UnmarshalJSON(data []byte) error {
 		if err != nil {
 			return err
 		}
-		Si, res := new(bn256.G1).Unmarshal(SiBytes)
+		Si, res := new(bn256Utils.G1).Unmarshal(SiBytes)
 		if !res {
 			return errors.New("error when unmarshal G2 point")
 		}
@@ -181,7 +180,7 @@ func (sigma *Sigma) UnmarshalJSON(data []byte) error {
 
 func NewSigOfRingers() (ringersSigner *sigOfRingers) {
 	ringersSigner = &sigOfRingers{
-		P: bn256.Order,
+		P: bn256Utils.Order,
 	}
 	return ringersSigner
 }
@@ -190,7 +189,7 @@ func (this *sigOfRingers) KeyGen(n int) (sk []*big.Int, pk *RingersPK, err error
 	// new RingersPK
 	pk = new(RingersPK)
 	// Q \in_R G_2
-	_, Q, err := bn256.RandomG2(rand.Reader)
+	_, Q, err := bn256Utils.RandomG2(rand.Reader)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -242,7 +241,7 @@ func (this *sigOfRingers) Sign(ks []*big.Int, sk []*big.Int) (sigma *Sigma, err
 		return nil, err
 	}
 	// K \in_R G_1
-	_, K, err := bn256.RandomG1(rand.Reader)
+	_, K, err := bn256Utils.RandomG1(rand.Reader)
 	if err != nil {
 		return nil, err
 	}
@@ -307,13 +306,13 @@ func (this *sigOfRingers) Verify(ks []*big.Int, sigma *Sigma, pk *RingersPK) (re
 		Sr_Si_ri_prod = bn256Utils.G1Add(Sr_Si_ri_prod, Si_ri)
 		Ar_Ai_ri_prod = bn256Utils.G2Add(Ar_Ai_ri_prod, Ai_ri)
 	}
-	eSQ := bn256.Pair(Sr_Si_ri_prod, pk.Q)
-	eKA := bn256.Pair(sigma.K, Ar_Ai_ri_prod)
+	eSQ := bn256Utils.Pair(Sr_Si_ri_prod, pk.Q)
+	eKA := bn256Utils.Pair(sigma.K, Ar_Ai_ri_prod)
 	if eSQ.String() != eKA.String() {
 		return false, nil
 	}
-	eTQ := bn256.Pair(sigma.T, pk.Q)
-	eCZ := bn256.Pair(sigma.C, pk.Z)
+	eTQ := bn256Utils.Pair(sigma.T, pk.Q)
+	eCZ := bn256Utils.Pair(sigma.C, pk.Z)
 	if eTQ.String() != eCZ.String() {
 		return false, nil
 	}
@@ -403,18 +402,18 @@ func TryOnce() {
 func TryOnce2() {
 	fmt.Println("-----------Self-blindable Attribute-based Credential start-------------")
 	// private keys
-	a, _ := rand.Int(rand.Reader, bn256.Order)
-	a1, _ := rand.Int(rand.Reader, bn256.Order)
-	a2, _ := rand.Int(rand.Reader, bn256.Order)
-	z, _ := rand.Int(rand.Reader, bn256.Order)
+	a, _ := rand.Int(rand.Reader, bn256Utils.Order)
+	a1, _ := rand.Int(rand.Reader, bn256Utils.Order)
+	a2, _ := rand.Int(rand.Reader, bn256Utils.Order)
+	z, _ := rand.Int(rand.Reader, bn256Utils.Order)
 	// public keys: Q,A,A_1,Z
-	_, P, _ := bn256.RandomG1(rand.Reader)
-	_, Q, _ := bn256.RandomG2(rand.Reader)
+	_, P, _ := bn256Utils.RandomG1(rand.Reader)
+	_, Q, _ := bn256Utils.RandomG2(rand.Reader)
 	// ---------pair test start--------------
-	ePaQ := bn256.Pair(bn256Utils.G1ScalarMult(P, a), Q)
-	ePQa := bn256.Pair(P, bn256Utils.G2ScalarMult(Q, a))
-	ePQ := bn256.Pair(P, Q)
-	ePQ = new(bn256.GT).ScalarMult(ePQ, a)
+	ePaQ := bn256Utils.Pair(bn256Utils.G1ScalarMult(P, a), Q)
+	ePQa := bn256Utils.Pair(P, bn256Utils.G2ScalarMult(Q, a))
+	ePQ := bn256Utils.Pair(P, Q)
+	ePQ = new(bn256Utils.GT).ScalarMult(ePQ, a)
 	fmt.Println("-----------Pair test start-------------")
 	fmt.Println("ePaQ == ePQa == ePQ:", hex.EncodeToString(ePaQ.Marshal()) == hex.EncodeToString(ePQa.Marshal()) &&
 		hex.EncodeToString(ePaQ.Marshal()) == hex.EncodeToString(ePQ.Marshal()) &&
@@ -428,9 +427,9 @@ func TryOnce2() {
 	A2 := bn256Utils.G2ScalarMult(Q, a2)
 	Z := bn256Utils.G2ScalarMult(Q, z)
 	// kappa \in Z_p
-	kappa, _ := rand.Int(rand.Reader, bn256.Order)
+	kappa, _ := rand.Int(rand.Reader, bn256Utils.Order)
 	// K \in G_1
-	_, K, _ := bn256.RandomG1(rand.Reader)
+	_, K, _ := bn256Utils.RandomG1(rand.Reader)
 	// S = K^a
 	S := bn256Utils.G1ScalarMult(K, a)
 	// S_i = K^{a_i}
@@ -451,28 +450,28 @@ func TryOnce2() {
 	// sigma = (kappa,K,S,S_i,T)
 	// verify
 	// check if e(S,Q) == e(K,A)
-	eSQ := bn256.Pair(S, Q)
-	eKA := bn256.Pair(K, A)
+	eSQ := bn256Utils.Pair(S, Q)
+	eKA := bn256Utils.Pair(K, A)
 	fmt.Println("-----------Verify dsa test start-------------")
 	fmt.Println("eSQ==eKA:", eSQ.String() == eKA.String())
 	// check if e(S_i,Q) == e(K,A_i)
 	// eS1Q eKA1
-	eS1Q := bn256.Pair(S1, Q)
-	eKA1 := bn256.Pair(K, A1)
+	eS1Q := bn256Utils.Pair(S1, Q)
+	eKA1 := bn256Utils.Pair(K, A1)
 	fmt.Println("eS1Q==eKA1:", eS1Q.String() == eKA1.String())
 	// eS2Q eKA2
-	eS2Q := bn256.Pair(S2, Q)
-	eKA2 := bn256.Pair(K, A2)
+	eS2Q := bn256Utils.Pair(S2, Q)
+	eKA2 := bn256Utils.Pair(K, A2)
 	fmt.Println("eS2Q==eKA2:", eS2Q.String() == eKA2.String())
 	// check if e(T,Q) == e(C,Z)
-	eTQ := bn256.Pair(T, Q)
-	eCZ := bn256.Pair(C, Z)
+	eTQ := bn256Utils.Pair(T, Q)
+	eCZ := bn256Utils.Pair(C, Z)
 	fmt.Println("eTQ==eCZ:", eTQ.String() == eCZ.String())
 	fmt.Println("-----------Verify dsa test end-------------")
 
 	// start privacy attribute
-	alpha, _ := rand.Int(rand.Reader, bn256.Order)
-	beta, _ := rand.Int(rand.Reader, bn256.Order)
+	alpha, _ := rand.Int(rand.Reader, bn256Utils.Order)
+	beta, _ := rand.Int(rand.Reader, bn256Utils.Order)
 	K_ba := bn256Utils.G1ScalarMult(K, alpha)
 	//S_ba := bn256Utils.G1ScalarMult(S, alpha)
 	//S1_ba := bn256Utils.G1ScalarMult(S1, alpha)
@@ -488,18 +487,18 @@ func TryOnce2() {
 	T_ba := bn256Utils.G1ScalarMult(C_ba, z)
 	// verify dsa
 	// check if e(S_ba,Q) == e(K_ba,A)
-	eS_baQ := bn256.Pair(S_ba, Q)
-	eK_baA := bn256.Pair(K_ba, A)
+	eS_baQ := bn256Utils.Pair(S_ba, Q)
+	eK_baA := bn256Utils.Pair(K_ba, A)
 	fmt.Println("-----------Verify modified dsa start-------------")
 	fmt.Println("eS_baQ == eK_baA:", eS_baQ.String() == eK_baA.String())
-	eS1_baQ := bn256.Pair(S1_ba, Q)
-	eK_baA1 := bn256.Pair(K_ba, A1)
+	eS1_baQ := bn256Utils.Pair(S1_ba, Q)
+	eK_baA1 := bn256Utils.Pair(K_ba, A1)
 	fmt.Println("eS1_baQ == eK_baA1:", eS1_baQ.String() == eK_baA1.String())
-	eS2_baQ := bn256.Pair(S2_ba, Q)
-	eK_baA2 := bn256.Pair(K_ba, A2)
+	eS2_baQ := bn256Utils.Pair(S2_ba, Q)
+	eK_baA2 := bn256Utils.Pair(K_ba, A2)
 	fmt.Println("eS2_baQ == eK_baA2:", eS2_baQ.String() == eK_baA2.String())
-	eT_baQ := bn256.Pair(T_ba, Q)
-	eC_baZ := bn256.Pair(C_ba, Z)
+	eT_baQ := bn256Utils.Pair(T_ba, Q)
+	eC_baZ := bn256Utils.Pair(C_ba, Z)
 	fmt.Println("eS2_baQ == eK_baA2:", eT_baQ.String() == eC_baZ.String())
 	fmt.Println("-----------Verify modified dsa end-------------")
 	fmt.Println("-----------Self-blindable Attribute-based Credential end-------------")
@@ -0,0 +1,110 @@
+package schnorr
+
+import (
+	"errors"
+	"math/big"
+
+	"scrypto/ecc/group"
+)
+
+// Adaptor signatures: for the statement T = [t]G, the signer draws k and sets
+// R = [k]G + T and s' = k + e x with e = H(R, P, m). The pre-signature checks
+// as [s']G = R - T + [e]P, whoever knows t adapts it to (R, s' + t), a plain
+// signature, and from s and s' anyone extracts t = s - s'.
+
+var (
+	errStatement    = errors.New("witness is not the discrete logarithm of the statement")
+	errPreSignature = errors.New("signature is not the adaptation of the pre-signature")
+)
+
+// PreSignature is a Schnorr pre-signature bound to the statement T, R is the
+// nonce point of the adapted signature
+type PreSignature struct {
+	T []byte
+	R []byte
+	S *big.Int
+}
+
+// PreSign returns the pre-signature of message with sk for the statement T
+func (s *Schnorr) PreSign(sk *big.Int, message []byte, T group.Point) (*PreSignature, error) {
+	pk, err := s.checkSecret(sk)
+	if err != nil {
+		return nil, err
+	}
+	g := s.group
+	for {
+		k, err := group.RandomScalar(g, nil)
+		if err != nil {
+			return nil, err
+		}
+		r := g.Add(g.ScalarBaseMult(k), T)
+		if g.IsIdentity(r) {
+			continue
+		}
+		e := s.challenge(r, pk, message)
+		return &PreSignature{T: g.Marshal(T), R: g.Marshal(r), S: s.response(k, e, sk)}, nil
+	}
+}
+
+// preSignature decodes the points T and R of pre
+func (s *Schnorr) preSignature(pre *PreSignature) (t, r group.Point, ok bool) {
+	if pre == nil || !s.isScalar(pre.S) {
+		return nil, nil, false
+	}
+	t, err := s.group.Unmarshal(pre.T)
+	if err != nil {
+		return nil, nil, false
+	}
+	r, ok = s.nonce(pre.R)
+	return t, r, ok
+}
+
+// PreVerify reports whether pre is a pre-signature of message under pk for
+// the statement T
+func (s *Schnorr) PreVerify(pk group.Point, message []byte, T group.Point, pre *PreSignature) bool {
+	if pk == nil || T == nil || s.group.IsIdentity(pk) {
+		return false
+	}
+	t, r, ok := s.preSignature(pre)
+	g := s.group
+	if !ok || !g.Equal(t, T) {
+		return false
+	}
+	// [s']G == R - T + [e]P
+	e := s.challenge(r, pk, message)
+	return g.Equal(g.ScalarBaseMult(pre.S), g.Add(group.Sub(g, r, t), g.ScalarMult(pk, e)))
+}
+
+// Adapt returns the signature of pre with the witness t of its statement
+func (s *Schnorr) Adapt(pre *PreSignature, t *big.Int) (*Signature, error) {
+	T, _, ok := s.preSignature(pre)
+	if !ok {
+		return nil, errPreSignature
+	}
+	if !s.isScalar(t) || !s.group.Equal(s.group.ScalarBaseMult(t), T) {
+		return nil, errStatement
+	}
+	res := new(big.Int).Add(pre.S, t)
+	res.Mod(res, s.group.Order())
+	return &Signature{R: append([]byte{}, pre.R...), S: res}, nil
+}
+
+// Extract returns the witness of the statement of pre from the signature sig
+// adapted from pre
+func (s *Schnorr) Extract(sig *Signature, pre *PreSignature) (*big.Int, error) {
+	T, r, ok := s.preSignature(pre)
+	if !ok || sig == nil || !s.isScalar(sig.S) {
+		return nil, errPreSignature
+	}
+	sigR, ok := s.nonce(sig.R)
+	if !ok || !s.group.Equal(sigR, r) {
+		return nil, errPreSignature
+	}
+	// t = s - s'
+	t := new(big.Int).Sub(sig.S, pre.S)
+	t.Mod(t, s.group.Order())
+	if !s.group.Equal(s.group.ScalarBaseMult(t), T) {
+		return nil, errPreSignature
+	}
+	return t, nil
+}
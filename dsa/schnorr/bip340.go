@@ -0,0 +1,202 @@
+package schnorr
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	"scrypto/ecc/secp256k1Utils"
+)
+
+// BIP-340 (https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki):
+// Schnorr signatures on secp256k1 with 32-byte x-only public keys, whose
+// points have an even y, and tagged hashes. A signature is bytes(R) || s.
+
+var (
+	errBip340Nonce     = errors.New("bip340 nonce is zero")
+	errBip340Signature = errors.New("bip340 signature not verified")
+	errAuxRand         = errors.New("bip340 auxiliary randomness should be 32 bytes")
+)
+
+// TaggedHash returns SHA256(SHA256(tag) || SHA256(tag) || data)
+func TaggedHash(tag string, data ...[]byte) []byte {
+	t := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(t[:])
+	h.Write(t[:])
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// liftX returns the point of x-coordinate x with an even y
+func liftX(x []byte) (*secp256k1Utils.CurvePoint, bool) {
+	if len(x) != 32 {
+		return nil, false
+	}
+	px, py := elliptic.UnmarshalCompressed(secp256k1Utils.S256(), append([]byte{2}, x...))
+	if px == nil {
+		return nil, false
+	}
+	return &secp256k1Utils.CurvePoint{Curve: secp256k1Utils.S256(), X: px, Y: py}, true
+}
+
+// bytes32 returns x in 32 big-endian bytes
+func bytes32(x *big.Int) []byte {
+	return x.FillBytes(make([]byte, 32))
+}
+
+// bip340Challenge returns int(hash_BIP0340/challenge(r || P || m)) mod n
+func bip340Challenge(r, pk, message []byte) *big.Int {
+	e := new(big.Int).SetBytes(TaggedHash("BIP0340/challenge", r, pk, message))
+	return e.Mod(e, secp256k1Utils.N)
+}
+
+// Bip340PublicKey returns the x-only public key of sk
+func Bip340PublicKey(sk *big.Int) ([]byte, error) {
+	if sk == nil || sk.Sign() <= 0 || sk.Cmp(secp256k1Utils.N) >= 0 {
+		return nil, errInvalidKey
+	}
+	return bytes32(secp256k1Utils.ScalarBaseMult(sk).X), nil
+}
+
+// Bip340Sign signs message with sk, auxRand is the 32 bytes of auxiliary
+// randomness of the nonce, nil draws them from crypto/rand
+func Bip340Sign(sk *big.Int, message, auxRand []byte) ([]byte, error) {
+	n := secp256k1Utils.N
+	if sk == nil || sk.Sign() <= 0 || sk.Cmp(n) >= 0 {
+		return nil, errInvalidKey
+	}
+	if auxRand == nil {
+		auxRand = make([]byte, 32)
+		if _, err := rand.Read(auxRand); err != nil {
+			return nil, err
+		}
+	}
+	if len(auxRand) != 32 {
+		return nil, errAuxRand
+	}
+	p := secp256k1Utils.ScalarBaseMult(sk)
+	d := new(big.Int).Set(sk)
+	if p.Y.Bit(0) == 1 {
+		d.Sub(n, d)
+	}
+	pk := bytes32(p.X)
+	// t = bytes(d) xor hash_BIP0340/aux(a)
+	t := bytes32(d)
+	for i, b := range TaggedHash("BIP0340/aux", auxRand) {
+		t[i] ^= b
+	}
+	k := new(big.Int).SetBytes(TaggedHash("BIP0340/nonce", t, pk, message))
+	k.Mod(k, n)
+	if k.Sign() == 0 {
+		return nil, errBip340Nonce
+	}
+	r := secp256k1Utils.ScalarBaseMult(k)
+	if r.Y.Bit(0) == 1 {
+		k.Sub(n, k)
+	}
+	rx := bytes32(r.X)
+	// s = k + e d
+	s := bip340Challenge(rx, pk, message)
+	s.Mul(s, d)
+	s.Add(s, k)
+	s.Mod(s, n)
+	sig := append(rx, bytes32(s)...)
+	if !Bip340Verify(pk, message, sig) {
+		return nil, errBip340Signature
+	}
+	return sig, nil
+}
+
+// bip340Parse decodes the public key and the signature, R is lifted from r
+func bip340Parse(pk, sig []byte) (p, r *secp256k1Utils.CurvePoint, s *big.Int, ok bool) {
+	if len(sig) != 64 {
+		return nil, nil, nil, false
+	}
+	if p, ok = liftX(pk); !ok {
+		return nil, nil, nil, false
+	}
+	s = new(big.Int).SetBytes(sig[32:])
+	if s.Cmp(secp256k1Utils.N) >= 0 {
+		return nil, nil, nil, false
+	}
+	if r, ok = liftX(sig[:32]); !ok {
+		return nil, nil, nil, false
+	}
+	return p, r, s, true
+}
+
+// Bip340Verify reports whether sig is a signature of message under the x-only
+// public key pk
+func Bip340Verify(pk, message, sig []byte) bool {
+	if len(sig) != 64 {
+		return false
+	}
+	p, ok := liftX(pk)
+	if !ok {
+		return false
+	}
+	s := new(big.Int).SetBytes(sig[32:])
+	n := secp256k1Utils.N
+	if s.Cmp(n) >= 0 {
+		return false
+	}
+	// R = [s]G - [e]P must have an even y and x = r, r >= p fails here too
+	e := bip340Challenge(sig[:32], pk, message)
+	r := secp256k1Utils.ScalarAdd(secp256k1Utils.ScalarBaseMult(s), secp256k1Utils.ScalarMult(p, e.Sub(n, e)))
+	if r.X.Sign() == 0 && r.Y.Sign() == 0 {
+		return false
+	}
+	return r.Y.Bit(0) == 0 && r.X.Cmp(new(big.Int).SetBytes(sig[:32])) == 0
+}
+
+// Bip340BatchVerify reports whether every sigs[i] is a signature of
+// messages[i] under pks[i]: with a_1 = 1 and random a_i it checks
+// [sum a_i s_i]G = sum [a_i]R_i + sum [a_i e_i]P_i in one multi-scalar
+// multiplication. It fails if any signature fails, use Bip340Verify to find
+// which one.
+func Bip340BatchVerify(pks, messages, sigs [][]byte) bool {
+	if len(pks) != len(messages) || len(pks) != len(sigs) {
+		return false
+	}
+	n := secp256k1Utils.N
+	points := make([]*secp256k1Utils.CurvePoint, 0, 2*len(sigs)+1)
+	scalars := make([]*big.Int, 0, 2*len(sigs)+1)
+	sum := new(big.Int)
+	for i := range sigs {
+		p, r, s, ok := bip340Parse(pks[i], sigs[i])
+		if !ok {
+			return false
+		}
+		a := big.NewInt(1)
+		if i > 0 {
+			var err error
+			if a, err = randomNonZero(n); err != nil {
+				return false
+			}
+		}
+		e := bip340Challenge(sigs[i][:32], pks[i], messages[i])
+		sum.Add(sum, new(big.Int).Mul(a, s))
+		points = append(points, r, p)
+		scalars = append(scalars, a, e.Mul(e, a))
+	}
+	// sum [a_i]R_i + [a_i e_i]P_i - [sum a_i s_i]G is the identity
+	sum.Mod(sum, n)
+	points = append(points, secp256k1Utils.GetBaseGenerator())
+	scalars = append(scalars, sum.Sub(n, sum))
+	res := secp256k1Utils.MultiScalarMult(points, scalars)
+	return res.X.Sign() == 0 && res.Y.Sign() == 0
+}
+
+// randomNonZero returns a random integer in [1, n)
+func randomNonZero(n *big.Int) (*big.Int, error) {
+	k, err := rand.Int(rand.Reader, new(big.Int).Sub(n, big.NewInt(1)))
+	if err != nil {
+		return nil, err
+	}
+	return k.Add(k, big.NewInt(1)), nil
+}
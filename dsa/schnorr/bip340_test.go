@@ -0,0 +1,307 @@
+package schnorr
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"scrypto/ecc/group"
+	"scrypto/ecc/secp256k1Utils"
+)
+
+func fromHex(s string) []byte {
+	res, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// bip340Vectors are the test vectors of BIP-340, test-vectors.csv
+var bip340Vectors = []struct {
+	secret, pk, aux, message, sig string
+	valid                         bool
+}{
+	{
+		"0000000000000000000000000000000000000000000000000000000000000003",
+		"F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"E907831F80848D1069A5371B402410364BDF1C5F8307B0084C55F1CE2DCA821525F66A4A85EA8B71E482A74F382D2CE5EBEEE8FDB2172F477DF4900D310536C0",
+		true,
+	},
+	{
+		"B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"6896BD60EEAE296DB48A229FF71DFE071BDE413E6D43F917DC8DCF8C78DE33418906D11AC976ABCCB20B091292BFF4EA897EFCB639EA871CFA95F6DE339E4B0A",
+		true,
+	},
+	{
+		"C90FDAA22168C234C4C6628B80DC1CD129024E088A67CC74020BBEA63B14E5C9",
+		"DD308AFEC5777E13121FA72B9CC1B7CC0139715309B086C960E18FD969774EB8",
+		"C87AA53824B4D7AE2EB035A2B5BBBCCC080E76CDC6D1692C4B0B62D798E6D906",
+		"7E2D58D8B3BCDF1ABADEC7829054F90DDA9805AAB56C77333024B9D0A508B75C",
+		"5831AAEED7B44BB74E5EAB94BA9D4294C49BCF2A60728D8B4C200F50DD313C1BAB745879A5AD954A72C45A91C3A51D3C7ADEA98D82F8481E0E1E03674A6F3FB7",
+		true,
+	},
+	{
+		"0B432B2677937381AEF05BB02A66ECD012773062CF3FA2549E44F58ED2401710",
+		"25D1DFF95105F5253C4022F628A996AD3A0D95FBF21D468A1B33F8C160D8F517",
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF",
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF",
+		"7EB0509757E246F19449885651611CB965ECC1A187DD51B64FDA1EDC9637D5EC97582B9CB13DB3933705B32BA982AF5AF25FD78881EBB32771FC5922EFC66EA3",
+		true,
+	},
+	{
+		"",
+		"D69C3509BB99E412E68B0FE8544E72837DFA30746D8BE2AA65975F29D22DC7B9",
+		"",
+		"4DF3C3F68FCC83B27E9D42C90431A72499F17875C81A599B566C9889B9696703",
+		"00000000000000000000003B78CE563F89A0ED9414F5AA28AD0D96D6795F9C6376AFB1548AF603B3EB45C9F8207DEE1060CB71C04E80F593060B07D28308D7F4",
+		true,
+	},
+	// public key not on the curve
+	{
+		"",
+		"EEFDEA4CDB677750A420FEE807EACF21EB9898AE79B9768766E4FAA04A2D4A34",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"6CFF5C3BA86C69EA4B7376F31A9BCB4F74C1976089B2D9963DA2E5543E17776969E89B4C5564D00349106B8497785DD7D1D713A8AE82B32FA79D5F7FC407D39B",
+		false,
+	},
+	// R has an odd y
+	{
+		"",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"FFF97BD5755EEEA420453A14355235D382F6472F8568A18B2F057A14602975563CC27944640AC607CD107AE10923D9EF7A73C643E166BE5EBEAFA34B1AC553E2",
+		false,
+	},
+	// negated message
+	{
+		"",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"1FA62E331EDBC21C394792D2AB1100A7B432B013DF3F6FF4F99FCB33E0E1515F28890B3EDB6E7189B630448B515CE4F8622A954CFE545735AAEA5134FCCDB2BD",
+		false,
+	},
+	// negated s
+	{
+		"",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"6CFF5C3BA86C69EA4B7376F31A9BCB4F74C1976089B2D9963DA2E5543E177769961764B3AA9B2FFCB6EF947B6887A226E8D7C93E00C5ED0C1834FF0D0C2E6DA6",
+		false,
+	},
+	// sG - eP is the point at infinity
+	{
+		"",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"0000000000000000000000000000000000000000000000000000000000000000123DDA8328AF9C23A94C1FEECFD123BA4FB73476F0D594DCB65C6425BD186051",
+		false,
+	},
+	{
+		"",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"00000000000000000000000000000000000000000000000000000000000000017615FBAF5AE28864013C099742DEADB4DBA87F11AC6754F93780D5A1837CF197",
+		false,
+	},
+	// r is not the x-coordinate of a point
+	{
+		"",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"4A298DACAE57395A15D0795DDBFD1DCB564DA82B0F269BC70A74F8220429BA1D69E89B4C5564D00349106B8497785DD7D1D713A8AE82B32FA79D5F7FC407D39B",
+		false,
+	},
+	// r is the field size
+	{
+		"",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F69E89B4C5564D00349106B8497785DD7D1D713A8AE82B32FA79D5F7FC407D39B",
+		false,
+	},
+	// s is the curve order
+	{
+		"",
+		"DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"6CFF5C3BA86C69EA4B7376F31A9BCB4F74C1976089B2D9963DA2E5543E177769FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141",
+		false,
+	},
+	// public key exceeds the field size
+	{
+		"",
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC30",
+		"",
+		"243F6A8885A308D313198A2E03707344A4093822299F31D0082EFA98EC4E6C89",
+		"6CFF5C3BA86C69EA4B7376F31A9BCB4F74C1976089B2D9963DA2E5543E17776969E89B4C5564D00349106B8497785DD7D1D713A8AE82B32FA79D5F7FC407D39B",
+		false,
+	},
+	// messages of 0, 1, 17 and 100 bytes
+	{
+		"0340034003400340034003400340034003400340034003400340034003400340",
+		"778CAA53B4393AC467774D09497A87224BF9FAB6F6E68B23086497324D6FD117",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"",
+		"71535DB165ECD9FBBC046E5FFAEA61186BB6AD436732FCCC25291A55895464CF6069CE26BF03466228F19A3A62DB8A649F2D560FAC652827D1AF0574E427AB63",
+		true,
+	},
+	{
+		"0340034003400340034003400340034003400340034003400340034003400340",
+		"778CAA53B4393AC467774D09497A87224BF9FAB6F6E68B23086497324D6FD117",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"11",
+		"08A20A0AFEF64124649232E0693C583AB1B9934AE63B4C3511F3AE1134C6A303EA3173BFEA6683BD101FA5AA5DBC1996FE7CACFC5A577D33EC14564CEC2BACBF",
+		true,
+	},
+	{
+		"0340034003400340034003400340034003400340034003400340034003400340",
+		"778CAA53B4393AC467774D09497A87224BF9FAB6F6E68B23086497324D6FD117",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0102030405060708090A0B0C0D0E0F1011",
+		"5130F39A4059B43BC7CAC09A19ECE52B5D8699D1A71E3C52DA9AFDB6B50AC370C4A482B77BF960F8681540E25B6771ECE1E5A37FD80E5A51897C5566A97EA5A5",
+		true,
+	},
+	{
+		"0340034003400340034003400340034003400340034003400340034003400340",
+		"778CAA53B4393AC467774D09497A87224BF9FAB6F6E68B23086497324D6FD117",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		strings.Repeat("99", 100),
+		"403B12B0D8555A344175EA7EC746566303321E5DBFA8BE6F091635163ECA79A8585ED3E3170807E7C03B720FC54C7B23897FCBA0E9D0B4A06894CFD249F22367",
+		true,
+	},
+}
+
+func TestBip340Sign(t *testing.T) {
+	for i, v := range bip340Vectors {
+		pk, message, sig := fromHex(v.pk), fromHex(v.message), fromHex(v.sig)
+		if v.secret != "" {
+			sk := new(big.Int).SetBytes(fromHex(v.secret))
+			got, err := Bip340PublicKey(sk)
+			if err != nil {
+				panic(err)
+			}
+			if !bytes.Equal(got, pk) {
+				t.Errorf("vector %d: public key not match", i)
+			}
+			res, err := Bip340Sign(sk, message, fromHex(v.aux))
+			if err != nil {
+				t.Errorf("vector %d: %v", i, err)
+			} else if !bytes.Equal(res, sig) {
+				t.Errorf("vector %d: signature not match", i)
+			}
+		}
+		if Bip340Verify(pk, message, sig) != v.valid {
+			t.Errorf("vector %d: verification is not %v", i, v.valid)
+		}
+		if Bip340BatchVerify([][]byte{pk}, [][]byte{message}, [][]byte{sig}) != v.valid {
+			t.Errorf("vector %d: batch verification is not %v", i, v.valid)
+		}
+	}
+}
+
+func TestBip340BatchVerify(t *testing.T) {
+	var pks, messages, sigs [][]byte
+	for i := 0; i < 20; i++ {
+		sk, err := randomNonZero(secp256k1Utils.N)
+		if err != nil {
+			panic(err)
+		}
+		pk, err := Bip340PublicKey(sk)
+		if err != nil {
+			panic(err)
+		}
+		m := []byte{byte(i)}
+		sig, err := Bip340Sign(sk, m, nil)
+		if err != nil {
+			panic(err)
+		}
+		pks, messages, sigs = append(pks, pk), append(messages, m), append(sigs, sig)
+	}
+	if !Bip340BatchVerify(pks, messages, sigs) {
+		t.Fatal("batch not verified")
+	}
+	if Bip340BatchVerify(pks, messages, sigs[1:]) {
+		t.Error("batch of different sizes verified")
+	}
+	// two swapped signatures fail, even if their errors would cancel without
+	// the random coefficients
+	sigs[3], sigs[4] = sigs[4], sigs[3]
+	messages[3], messages[4] = messages[4], messages[3]
+	if Bip340BatchVerify(pks, messages, sigs) {
+		t.Error("batch with swapped signatures verified")
+	}
+}
+
+func TestSchnorr_BatchVerify(t *testing.T) {
+	for _, s := range schnorrs {
+		name := s.Group().Name()
+		var pks []group.Point
+		var messages [][]byte
+		var sigs []*Signature
+		for i := 0; i < 10; i++ {
+			sk, pk, err := s.GenerateKeys()
+			if err != nil {
+				panic(err)
+			}
+			m := []byte{byte(i)}
+			sig, err := s.Sign(sk, m)
+			if err != nil {
+				panic(err)
+			}
+			pks, messages, sigs = append(pks, pk), append(messages, m), append(sigs, sig)
+		}
+		if !s.BatchVerify(pks, messages, sigs) {
+			t.Errorf("%s: batch not verified", name)
+		}
+		// s_0 + 1 and s_1 - 1 cancel in an unweighted sum
+		one := big.NewInt(1)
+		order := s.Group().Order()
+		s0, s1 := sigs[0].S, sigs[1].S
+		sigs[0] = &Signature{R: sigs[0].R, S: new(big.Int).Mod(new(big.Int).Add(s0, one), order)}
+		sigs[1] = &Signature{R: sigs[1].R, S: new(big.Int).Mod(new(big.Int).Sub(s1, one), order)}
+		if s.BatchVerify(pks, messages, sigs) {
+			t.Errorf("%s: batch with cancelling errors verified", name)
+		}
+	}
+}
+
+func BenchmarkBip340BatchVerify(b *testing.B) {
+	const n = 64
+	var pks, messages, sigs [][]byte
+	for i := 0; i < n; i++ {
+		sk, _ := randomNonZero(secp256k1Utils.N)
+		pk, _ := Bip340PublicKey(sk)
+		m := []byte{byte(i)}
+		sig, _ := Bip340Sign(sk, m, nil)
+		pks, messages, sigs = append(pks, pk), append(messages, m), append(sigs, sig)
+	}
+	b.Run("Batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Bip340BatchVerify(pks, messages, sigs)
+		}
+	})
+	b.Run("OneByOne", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range sigs {
+				Bip340Verify(pks[j], messages[j], sigs[j])
+			}
+		}
+	})
+}
@@ -0,0 +1,393 @@
+package schnorr
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"sync"
+
+	"scrypto/ecc/secp256k1Utils"
+)
+
+// MuSig2 (BIP-327, https://github.com/bitcoin/bips/blob/master/bip-0327.mediawiki):
+// n-of-n multi-signatures whose aggregate is a BIP-340 signature under the
+// x-only aggregate key Q = sum a_i P_i. Signers publish two nonces each, any
+// party sums them per slot into the aggregate nonce, then each signer sends
+// its partial signature and any party adds them. Individual keys are 33-byte
+// compressed points. Key tweaking is not implemented.
+
+const (
+	// MuSig2PubNonceSize is the size of a public nonce and of an aggregate nonce
+	MuSig2PubNonceSize = 66
+	// MuSig2PartialSignatureSize is the size of a partial signature
+	MuSig2PartialSignatureSize = 32
+)
+
+var (
+	errMuSig2Keys       = errors.New("invalid musig2 public keys")
+	errMuSig2Signer     = errors.New("public key of the signer is not in the musig2 keys")
+	errMuSig2Nonce      = errors.New("invalid musig2 nonce")
+	errMuSig2NonceReuse = errors.New("musig2 secret nonce already used")
+	errMuSig2Partial    = errors.New("invalid musig2 partial signature")
+	errMuSig2Count      = errors.New("musig2 partial signatures and keys size not match")
+)
+
+// MuSig2Keys is the aggregation of the public keys of the signers
+type MuSig2Keys struct {
+	pks  [][]byte
+	list []byte
+	pk2  []byte
+	q    *secp256k1Utils.CurvePoint
+}
+
+// MuSig2PubNonce is the public nonce of a signer, sent in the first round
+type MuSig2PubNonce struct {
+	R1, R2 *secp256k1Utils.CurvePoint
+}
+
+// MuSig2AggNonce is the sum of the public nonces, infinity allowed
+type MuSig2AggNonce struct {
+	R1, R2 *secp256k1Utils.CurvePoint
+}
+
+// MuSig2PartialSignature is the partial signature of a signer, sent in the
+// second round
+type MuSig2PartialSignature struct {
+	S *big.Int
+}
+
+// musig2SecNonce is the secret nonce of a signer, erased when used
+type musig2SecNonce struct {
+	mu     sync.Mutex
+	k1, k2 *big.Int
+}
+
+// MuSig2Signer is the session of a signer for one message: its secret nonce
+// signs at most once, copies of a signer share the nonce
+type MuSig2Signer struct {
+	keys     *MuSig2Keys
+	sk       *big.Int
+	pk       []byte
+	message  []byte
+	secNonce *musig2SecNonce
+	pubNonce *MuSig2PubNonce
+}
+
+// MuSig2PublicKey returns the compressed public key of sk
+func MuSig2PublicKey(sk *big.Int) ([]byte, error) {
+	if sk == nil || sk.Sign() <= 0 || sk.Cmp(secp256k1Utils.N) >= 0 {
+		return nil, errInvalidKey
+	}
+	p := secp256k1Utils.ScalarBaseMult(sk)
+	return elliptic.MarshalCompressed(secp256k1Utils.S256(), p.X, p.Y), nil
+}
+
+// decodeCompressed decodes a compressed point of secp256k1
+func decodeCompressed(data []byte) (*secp256k1Utils.CurvePoint, bool) {
+	x, y := elliptic.UnmarshalCompressed(secp256k1Utils.S256(), data)
+	if x == nil {
+		return nil, false
+	}
+	return &secp256k1Utils.CurvePoint{Curve: secp256k1Utils.S256(), X: x, Y: y}, true
+}
+
+// isInfinity reports whether p is the point at infinity (0, 0)
+func isInfinity(p *secp256k1Utils.CurvePoint) bool {
+	return p.X.Sign() == 0 && p.Y.Sign() == 0
+}
+
+// compressedExt encodes p compressed, infinity as 33 zero bytes
+func compressedExt(p *secp256k1Utils.CurvePoint) []byte {
+	if isInfinity(p) {
+		return make([]byte, 33)
+	}
+	return elliptic.MarshalCompressed(secp256k1Utils.S256(), p.X, p.Y)
+}
+
+// MuSig2AggregateKeys aggregates the compressed public keys pks, in order:
+// Q = sum a_i P_i with a_i = H(L || P_i), L = H(P_1 || ... || P_n), and
+// a_i = 1 for the first key other than P_1
+func MuSig2AggregateKeys(pks [][]byte) (*MuSig2Keys, error) {
+	if len(pks) == 0 {
+		return nil, errMuSig2Keys
+	}
+	k := &MuSig2Keys{pk2: make([]byte, 33)}
+	points := make([]*secp256k1Utils.CurvePoint, len(pks))
+	for i, pk := range pks {
+		p, ok := decodeCompressed(pk)
+		if !ok {
+			return nil, errMuSig2Keys
+		}
+		points[i] = p
+		k.pks = append(k.pks, append([]byte{}, pk...))
+	}
+	k.list = TaggedHash("KeyAgg list", k.pks...)
+	for _, pk := range k.pks[1:] {
+		if !bytes.Equal(pk, k.pks[0]) {
+			k.pk2 = pk
+			break
+		}
+	}
+	scalars := make([]*big.Int, len(pks))
+	for i, pk := range k.pks {
+		scalars[i] = k.coefficient(pk)
+	}
+	k.q = secp256k1Utils.MultiScalarMult(points, scalars)
+	if isInfinity(k.q) {
+		return nil, errMuSig2Keys
+	}
+	return k, nil
+}
+
+// coefficient returns a_i of the compressed key pk
+func (k *MuSig2Keys) coefficient(pk []byte) *big.Int {
+	if bytes.Equal(pk, k.pk2) {
+		return big.NewInt(1)
+	}
+	a := new(big.Int).SetBytes(TaggedHash("KeyAgg coefficient", k.list, pk))
+	return a.Mod(a, secp256k1Utils.N)
+}
+
+// contains reports whether pk is one of the keys
+func (k *MuSig2Keys) contains(pk []byte) bool {
+	for _, p := range k.pks {
+		if bytes.Equal(p, pk) {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicKey returns the x-only aggregate key, the BIP-340 public key of the
+// signatures
+func (k *MuSig2Keys) PublicKey() []byte {
+	return bytes32(k.q.X)
+}
+
+// Bytes returns R1 || R2 compressed
+func (n *MuSig2PubNonce) Bytes() []byte {
+	return append(compressedExt(n.R1), compressedExt(n.R2)...)
+}
+
+// MuSig2PubNonceFromBytes decodes a public nonce, its points are not infinity
+func MuSig2PubNonceFromBytes(data []byte) (*MuSig2PubNonce, error) {
+	if len(data) != MuSig2PubNonceSize {
+		return nil, errMuSig2Nonce
+	}
+	r1, ok1 := decodeCompressed(data[:33])
+	r2, ok2 := decodeCompressed(data[33:])
+	if !ok1 || !ok2 {
+		return nil, errMuSig2Nonce
+	}
+	return &MuSig2PubNonce{R1: r1, R2: r2}, nil
+}
+
+// Bytes returns R1 || R2 compressed, infinity as 33 zero bytes
+func (n *MuSig2AggNonce) Bytes() []byte {
+	return append(compressedExt(n.R1), compressedExt(n.R2)...)
+}
+
+// MuSig2AggNonceFromBytes decodes an aggregate nonce
+func MuSig2AggNonceFromBytes(data []byte) (*MuSig2AggNonce, error) {
+	if len(data) != MuSig2PubNonceSize {
+		return nil, errMuSig2Nonce
+	}
+	var points [2]*secp256k1Utils.CurvePoint
+	for i := range points {
+		enc := data[33*i : 33*(i+1)]
+		if bytes.Equal(enc, make([]byte, 33)) {
+			points[i] = &secp256k1Utils.CurvePoint{Curve: secp256k1Utils.S256(), X: new(big.Int), Y: new(big.Int)}
+			continue
+		}
+		p, ok := decodeCompressed(enc)
+		if !ok {
+			return nil, errMuSig2Nonce
+		}
+		points[i] = p
+	}
+	return &MuSig2AggNonce{R1: points[0], R2: points[1]}, nil
+}
+
+// Bytes returns s in 32 bytes
+func (s *MuSig2PartialSignature) Bytes() []byte {
+	return bytes32(s.S)
+}
+
+// MuSig2PartialSignatureFromBytes decodes a partial signature, s < n
+func MuSig2PartialSignatureFromBytes(data []byte) (*MuSig2PartialSignature, error) {
+	if len(data) != MuSig2PartialSignatureSize {
+		return nil, errMuSig2Partial
+	}
+	s := new(big.Int).SetBytes(data)
+	if s.Cmp(secp256k1Utils.N) >= 0 {
+		return nil, errMuSig2Partial
+	}
+	return &MuSig2PartialSignature{S: s}, nil
+}
+
+// musig2Nonce returns k_i of NonceGen for the seed rand
+func musig2Nonce(seed, pk, aggPk, message []byte, i byte) *big.Int {
+	// msg_prefixed = 1 || len(m) in 8 bytes || m, the extra input is empty
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(len(message)))
+	k := new(big.Int).SetBytes(TaggedHash("MuSig/nonce",
+		seed, []byte{byte(len(pk))}, pk, []byte{byte(len(aggPk))}, aggPk,
+		[]byte{1}, buf[:], message, []byte{0, 0, 0, 0}, []byte{i}))
+	return k.Mod(k, secp256k1Utils.N)
+}
+
+// NewSigner starts the session of the signer of sk on message, its public
+// nonce is the message of the first round
+func (k *MuSig2Keys) NewSigner(sk *big.Int, message []byte) (*MuSig2Signer, *MuSig2PubNonce, error) {
+	pk, err := MuSig2PublicKey(sk)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !k.contains(pk) {
+		return nil, nil, errMuSig2Signer
+	}
+	// rand = bytes(sk) xor hash_MuSig/aux(rand')
+	seed := make([]byte, 32)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, nil, err
+	}
+	seed = TaggedHash("MuSig/aux", seed)
+	for i, b := range bytes32(sk) {
+		seed[i] ^= b
+	}
+	aggPk := k.PublicKey()
+	k1, k2 := musig2Nonce(seed, pk, aggPk, message, 0), musig2Nonce(seed, pk, aggPk, message, 1)
+	if k1.Sign() == 0 || k2.Sign() == 0 {
+		return nil, nil, errMuSig2Nonce
+	}
+	nonce := &MuSig2PubNonce{R1: secp256k1Utils.ScalarBaseMult(k1), R2: secp256k1Utils.ScalarBaseMult(k2)}
+	return &MuSig2Signer{
+		keys:     k,
+		sk:       new(big.Int).Set(sk),
+		pk:       pk,
+		message:  append([]byte{}, message...),
+		secNonce: &musig2SecNonce{k1: k1, k2: k2},
+		pubNonce: nonce,
+	}, nonce, nil
+}
+
+// MuSig2AggregateNonces sums the public nonces per slot
+func MuSig2AggregateNonces(nonces []*MuSig2PubNonce) (*MuSig2AggNonce, error) {
+	if len(nonces) == 0 {
+		return nil, errMuSig2Nonce
+	}
+	infinity := &secp256k1Utils.CurvePoint{Curve: secp256k1Utils.S256(), X: new(big.Int), Y: new(big.Int)}
+	res := &MuSig2AggNonce{R1: infinity, R2: infinity}
+	for _, n := range nonces {
+		if n == nil || n.R1 == nil || n.R2 == nil {
+			return nil, errMuSig2Nonce
+		}
+		res.R1 = secp256k1Utils.ScalarAdd(res.R1, n.R1)
+		res.R2 = secp256k1Utils.ScalarAdd(res.R2, n.R2)
+	}
+	return res, nil
+}
+
+// session returns the nonce coefficient b, the final nonce R and the
+// challenge e of the session of aggNonce on message
+func (k *MuSig2Keys) session(aggNonce *MuSig2AggNonce, message []byte) (b *big.Int, r *secp256k1Utils.CurvePoint, e *big.Int) {
+	n := secp256k1Utils.N
+	b = new(big.Int).SetBytes(TaggedHash("MuSig/noncecoef", aggNonce.Bytes(), k.PublicKey(), message))
+	b.Mod(b, n)
+	// R = R1 + b R2, G if infinity
+	r = secp256k1Utils.ScalarAdd(aggNonce.R1, secp256k1Utils.ScalarMult(aggNonce.R2, b))
+	if isInfinity(r) {
+		r = secp256k1Utils.GetBaseGenerator()
+	}
+	return b, r, bip340Challenge(bytes32(r.X), k.PublicKey(), message)
+}
+
+// negIfOdd returns n - x if p has an odd y, x otherwise
+func negIfOdd(p *secp256k1Utils.CurvePoint, x *big.Int) *big.Int {
+	if p.Y.Bit(0) == 1 {
+		return new(big.Int).Sub(secp256k1Utils.N, x)
+	}
+	return x
+}
+
+// Sign returns the partial signature of the signer in the session of
+// aggNonce, the secret nonce is erased and a second call fails
+func (s *MuSig2Signer) Sign(aggNonce *MuSig2AggNonce) (*MuSig2PartialSignature, error) {
+	s.secNonce.mu.Lock()
+	k1, k2 := s.secNonce.k1, s.secNonce.k2
+	s.secNonce.k1, s.secNonce.k2 = nil, nil
+	s.secNonce.mu.Unlock()
+	if k1 == nil || k2 == nil {
+		return nil, errMuSig2NonceReuse
+	}
+	if aggNonce == nil || aggNonce.R1 == nil || aggNonce.R2 == nil {
+		return nil, errMuSig2Nonce
+	}
+	n := secp256k1Utils.N
+	b, r, e := s.keys.session(aggNonce, s.message)
+	k1, k2 = negIfOdd(r, k1), negIfOdd(r, k2)
+	// d = g sk with g = -1 if Q has an odd y, s = k1 + b k2 + e a d
+	d := negIfOdd(s.keys.q, s.sk)
+	res := new(big.Int).Mul(e, s.keys.coefficient(s.pk))
+	res.Mul(res, d)
+	res.Add(res, k1)
+	res.Add(res, new(big.Int).Mul(b, k2))
+	res.Mod(res, n)
+	psig := &MuSig2PartialSignature{S: res}
+	if !s.keys.PartialVerify(s.pk, s.pubNonce, aggNonce, s.message, psig) {
+		return nil, errMuSig2Partial
+	}
+	return psig, nil
+}
+
+// PartialVerify reports whether psig is the partial signature of the signer
+// of pk with the public nonce pubNonce in the session of aggNonce on message
+func (k *MuSig2Keys) PartialVerify(pk []byte, pubNonce *MuSig2PubNonce, aggNonce *MuSig2AggNonce, message []byte, psig *MuSig2PartialSignature) bool {
+	n := secp256k1Utils.N
+	if psig == nil || psig.S == nil || psig.S.Sign() < 0 || psig.S.Cmp(n) >= 0 || !k.contains(pk) {
+		return false
+	}
+	if pubNonce == nil || pubNonce.R1 == nil || pubNonce.R2 == nil || aggNonce == nil || aggNonce.R1 == nil || aggNonce.R2 == nil {
+		return false
+	}
+	p, ok := decodeCompressed(pk)
+	if !ok {
+		return false
+	}
+	b, r, e := k.session(aggNonce, message)
+	// [s]G == Re + [e a g]P with Re = R1 + b R2, negated if R has an odd y
+	re := secp256k1Utils.ScalarAdd(pubNonce.R1, secp256k1Utils.ScalarMult(pubNonce.R2, b))
+	if r.Y.Bit(0) == 1 && !isInfinity(re) {
+		re = &secp256k1Utils.CurvePoint{Curve: re.Curve, X: re.X, Y: new(big.Int).Sub(re.Curve.Params().P, re.Y)}
+	}
+	c := new(big.Int).Mul(e, k.coefficient(pk))
+	c = negIfOdd(k.q, c.Mod(c, n))
+	right := secp256k1Utils.ScalarAdd(re, secp256k1Utils.ScalarMult(p, c))
+	left := secp256k1Utils.ScalarBaseMult(psig.S)
+	return left.X.Cmp(right.X) == 0 && left.Y.Cmp(right.Y) == 0
+}
+
+// Aggregate returns the BIP-340 signature of message from the partial
+// signatures of all the signers in the session of aggNonce
+func (k *MuSig2Keys) Aggregate(aggNonce *MuSig2AggNonce, message []byte, psigs []*MuSig2PartialSignature) ([]byte, error) {
+	if len(psigs) != len(k.pks) {
+		return nil, errMuSig2Count
+	}
+	if aggNonce == nil || aggNonce.R1 == nil || aggNonce.R2 == nil {
+		return nil, errMuSig2Nonce
+	}
+	n := secp256k1Utils.N
+	s := new(big.Int)
+	for _, psig := range psigs {
+		if psig == nil || psig.S == nil || psig.S.Sign() < 0 || psig.S.Cmp(n) >= 0 {
+			return nil, errMuSig2Partial
+		}
+		s.Add(s, psig.S)
+	}
+	s.Mod(s, n)
+	_, r, _ := k.session(aggNonce, message)
+	return append(bytes32(r.X), bytes32(s)...), nil
+}
@@ -0,0 +1,210 @@
+package schnorr
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"scrypto/ecc/secp256k1Utils"
+)
+
+func TestMuSig2AggregateKeys(t *testing.T) {
+	// key_agg_vectors.json of BIP-327
+	x1 := fromHex("02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9")
+	x2 := fromHex("03DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659")
+	x3 := fromHex("023590A94E768F8E1815C2F24B4D80A8E3149316C3518CE7B7AD338368D038CA66")
+	vectors := []struct {
+		pks      [][]byte
+		expected string
+	}{
+		{[][]byte{x1, x2, x3}, "90539EEDE565F5D054F32CC0C220126889ED1E5D193BAF15AEF344FE59D4610C"},
+		{[][]byte{x3, x2, x1}, "6204DE8B083426DC6EAF9502D27024D53FC826BF7D2012148A0575435DF54B2B"},
+		{[][]byte{x1, x1, x1}, "B436E3BAD62B8CD409969A224731C193D051162D8C5AE8B109306127DA3AA935"},
+		{[][]byte{x1, x1, x2, x2}, "69BC22BFA5D106306E48A20679DE1D7389386124D07571D0D872686028C26A3E"},
+	}
+	for i, v := range vectors {
+		k, err := MuSig2AggregateKeys(v.pks)
+		if err != nil {
+			panic(err)
+		}
+		if !bytes.Equal(k.PublicKey(), fromHex(v.expected)) {
+			t.Errorf("vector %d: aggregate key not match", i)
+		}
+	}
+	// invalid public key, x exceeds the field size
+	bad := fromHex("02FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC30")
+	if _, err := MuSig2AggregateKeys([][]byte{x1, bad}); err == nil {
+		t.Error("invalid public key aggregated")
+	}
+	if _, err := MuSig2AggregateKeys(nil); err == nil {
+		t.Error("empty keys aggregated")
+	}
+}
+
+// musig2Signers returns n secret keys and their compressed public keys
+func musig2Signers(n int) ([]*big.Int, [][]byte) {
+	var sks []*big.Int
+	var pks [][]byte
+	for i := 0; i < n; i++ {
+		sk, err := randomNonZero(secp256k1Utils.N)
+		if err != nil {
+			panic(err)
+		}
+		pk, err := MuSig2PublicKey(sk)
+		if err != nil {
+			panic(err)
+		}
+		sks, pks = append(sks, sk), append(pks, pk)
+	}
+	return sks, pks
+}
+
+func TestMuSig2Signer_Sign(t *testing.T) {
+	sks, pks := musig2Signers(3)
+	keys, err := MuSig2AggregateKeys(pks)
+	if err != nil {
+		panic(err)
+	}
+	m := []byte("spend the custody output")
+	// round 1: the public nonces go through their encodings
+	signers := make([]*MuSig2Signer, len(sks))
+	nonces := make([]*MuSig2PubNonce, len(sks))
+	for i, sk := range sks {
+		s, nonce, err := keys.NewSigner(sk, m)
+		if err != nil {
+			panic(err)
+		}
+		signers[i] = s
+		if nonces[i], err = MuSig2PubNonceFromBytes(nonce.Bytes()); err != nil {
+			panic(err)
+		}
+	}
+	agg, err := MuSig2AggregateNonces(nonces)
+	if err != nil {
+		panic(err)
+	}
+	if agg, err = MuSig2AggNonceFromBytes(agg.Bytes()); err != nil {
+		panic(err)
+	}
+	// round 2
+	psigs := make([]*MuSig2PartialSignature, len(sks))
+	for i, s := range signers {
+		psig, err := s.Sign(agg)
+		if err != nil {
+			panic(err)
+		}
+		if psigs[i], err = MuSig2PartialSignatureFromBytes(psig.Bytes()); err != nil {
+			panic(err)
+		}
+		if !keys.PartialVerify(pks[i], nonces[i], agg, m, psigs[i]) {
+			t.Errorf("partial signature %d not verified", i)
+		}
+	}
+	if keys.PartialVerify(pks[0], nonces[1], agg, m, psigs[0]) || keys.PartialVerify(pks[1], nonces[0], agg, m, psigs[0]) {
+		t.Error("partial signature verified with another nonce or key")
+	}
+	sig, err := keys.Aggregate(agg, m, psigs)
+	if err != nil {
+		panic(err)
+	}
+	if !Bip340Verify(keys.PublicKey(), m, sig) {
+		t.Error("aggregate signature not verified by BIP-340")
+	}
+	if Bip340Verify(keys.PublicKey(), []byte("another message"), sig) {
+		t.Error("aggregate signature verified on another message")
+	}
+	if _, err := keys.Aggregate(agg, m, psigs[1:]); err == nil {
+		t.Error("aggregated without a signer")
+	}
+	// a wrong partial signature breaks the aggregate
+	psigs[2] = &MuSig2PartialSignature{S: new(big.Int).Add(psigs[2].S, big.NewInt(1))}
+	if keys.PartialVerify(pks[2], nonces[2], agg, m, psigs[2]) {
+		t.Error("wrong partial signature verified")
+	}
+	if sig, err = keys.Aggregate(agg, m, psigs); err != nil {
+		panic(err)
+	}
+	if Bip340Verify(keys.PublicKey(), m, sig) {
+		t.Error("aggregate of a wrong partial signature verified")
+	}
+}
+
+func TestMuSig2Signer_NonceReuse(t *testing.T) {
+	sks, pks := musig2Signers(2)
+	keys, err := MuSig2AggregateKeys(pks)
+	if err != nil {
+		panic(err)
+	}
+	m := []byte("message")
+	s0, n0, err := keys.NewSigner(sks[0], m)
+	if err != nil {
+		panic(err)
+	}
+	_, n1, err := keys.NewSigner(sks[1], m)
+	if err != nil {
+		panic(err)
+	}
+	agg, err := MuSig2AggregateNonces([]*MuSig2PubNonce{n0, n1})
+	if err != nil {
+		panic(err)
+	}
+	// a copy of the signer shares its secret nonce
+	copied := *s0
+	if _, err := s0.Sign(agg); err != nil {
+		panic(err)
+	}
+	if _, err := s0.Sign(agg); err == nil {
+		t.Error("secret nonce signed twice")
+	}
+	// two partial signatures on the same nonce and different sessions would
+	// reveal the secret key
+	other, err := MuSig2AggregateNonces([]*MuSig2PubNonce{n0, n0})
+	if err != nil {
+		panic(err)
+	}
+	if _, err := copied.Sign(other); err == nil {
+		t.Error("copy of the signer reused the secret nonce")
+	}
+	_, outsider := musig2Signers(1)
+	if _, _, err := keys.NewSigner(big.NewInt(42), m); err == nil {
+		t.Error("signer outside of the keys accepted")
+	}
+	if keys.PartialVerify(outsider[0], n0, agg, m, &MuSig2PartialSignature{S: big.NewInt(1)}) {
+		t.Error("partial signature of an outsider verified")
+	}
+}
+
+func TestMuSig2AggNonceFromBytes(t *testing.T) {
+	_, pks := musig2Signers(1)
+	keys, err := MuSig2AggregateKeys(pks)
+	if err != nil {
+		panic(err)
+	}
+	// R1 + (-R1) and R2 + (-R2) sum to infinity, the final nonce is then G
+	nonce := &MuSig2PubNonce{R1: secp256k1Utils.ScalarBaseMult(big.NewInt(5)), R2: secp256k1Utils.ScalarBaseMult(big.NewInt(6))}
+	neg := &MuSig2PubNonce{
+		R1: secp256k1Utils.ScalarBaseMult(new(big.Int).Sub(secp256k1Utils.N, big.NewInt(5))),
+		R2: secp256k1Utils.ScalarBaseMult(new(big.Int).Sub(secp256k1Utils.N, big.NewInt(6))),
+	}
+	agg, err := MuSig2AggregateNonces([]*MuSig2PubNonce{nonce, neg})
+	if err != nil {
+		panic(err)
+	}
+	data := agg.Bytes()
+	if !bytes.Equal(data, make([]byte, MuSig2PubNonceSize)) {
+		t.Error("infinity not encoded as zero bytes")
+	}
+	decoded, err := MuSig2AggNonceFromBytes(data)
+	if err != nil {
+		panic(err)
+	}
+	if _, r, _ := keys.session(decoded, []byte("m")); r.X.Cmp(secp256k1Utils.GetBaseGenerator().X) != 0 {
+		t.Error("final nonce of infinity is not G")
+	}
+	if _, err := MuSig2PubNonceFromBytes(data); err == nil {
+		t.Error("public nonce of infinity decoded")
+	}
+	if _, err := MuSig2PartialSignatureFromBytes(bytes32(secp256k1Utils.N)); err == nil {
+		t.Error("partial signature of n decoded")
+	}
+}
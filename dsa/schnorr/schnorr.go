@@ -0,0 +1,156 @@
+// Package schnorr implements Schnorr signatures on any group of ecc/group:
+// the signature of m under P = [x]G is (R, s) with R = [k]G, s = k + e x and
+// e = H(R, P, m), and it verifies as [s]G = R + [e]P. The challenge runs on a
+// Merlin transcript, NewSchnorr(group.P256()) signs on P-256.
+//
+// BIP-340, the Schnorr signatures of Bitcoin, are in bip340.go.
+package schnorr
+
+import (
+	"errors"
+	"math/big"
+
+	"scrypto/ecc/group"
+	"scrypto/proof/transcript"
+)
+
+// Schnorr signs on its group
+type Schnorr struct {
+	group group.Group
+}
+
+// Signature is a Schnorr signature, R is the encoded nonce point
+type Signature struct {
+	R []byte
+	S *big.Int
+}
+
+var errInvalidKey = errors.New("invalid schnorr secret key")
+
+// NewSchnorr returns a signer on g
+func NewSchnorr(g group.Group) *Schnorr {
+	return &Schnorr{group: g}
+}
+
+// Group returns the group of s
+func (s *Schnorr) Group() group.Group {
+	return s.group
+}
+
+// GenerateKeys returns a secret key x and its public key [x]G
+func (s *Schnorr) GenerateKeys() (sk *big.Int, pk group.Point, err error) {
+	sk, err = group.RandomScalar(s.group, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sk, s.group.ScalarBaseMult(sk), nil
+}
+
+// challenge returns e = H(R, P, m) modulo the order of the group
+func (s *Schnorr) challenge(r, pk group.Point, message []byte) *big.Int {
+	g := s.group
+	tr := transcript.New("schnorr-signature")
+	tr.AppendMessage("group", []byte(g.Name()))
+	tr.AppendPoint("R", g, r)
+	tr.AppendPoint("P", g, pk)
+	tr.AppendMessage("m", message)
+	return tr.ChallengeScalar("e", g.Order())
+}
+
+// checkSecret returns the public key of sk, sk must be in [1, order)
+func (s *Schnorr) checkSecret(sk *big.Int) (group.Point, error) {
+	if sk == nil || sk.Sign() <= 0 || sk.Cmp(s.group.Order()) >= 0 {
+		return nil, errInvalidKey
+	}
+	return s.group.ScalarBaseMult(sk), nil
+}
+
+// response returns k + e x mod order
+func (s *Schnorr) response(k, e, x *big.Int) *big.Int {
+	res := new(big.Int).Mul(e, x)
+	res.Add(res, k)
+	return res.Mod(res, s.group.Order())
+}
+
+// Sign signs message with sk
+func (s *Schnorr) Sign(sk *big.Int, message []byte) (*Signature, error) {
+	pk, err := s.checkSecret(sk)
+	if err != nil {
+		return nil, err
+	}
+	k, err := group.RandomScalar(s.group, nil)
+	if err != nil {
+		return nil, err
+	}
+	r := s.group.ScalarBaseMult(k)
+	e := s.challenge(r, pk, message)
+	return &Signature{R: s.group.Marshal(r), S: s.response(k, e, sk)}, nil
+}
+
+// nonce decodes the nonce point R, the identity is rejected
+func (s *Schnorr) nonce(data []byte) (group.Point, bool) {
+	r, err := s.group.Unmarshal(data)
+	if err != nil || s.group.IsIdentity(r) {
+		return nil, false
+	}
+	return r, true
+}
+
+// isScalar reports whether k is in [0, order)
+func (s *Schnorr) isScalar(k *big.Int) bool {
+	return k != nil && k.Sign() >= 0 && k.Cmp(s.group.Order()) < 0
+}
+
+// Verify reports whether sig is a signature of message under pk
+func (s *Schnorr) Verify(pk group.Point, message []byte, sig *Signature) bool {
+	if pk == nil || sig == nil || !s.isScalar(sig.S) || s.group.IsIdentity(pk) {
+		return false
+	}
+	r, ok := s.nonce(sig.R)
+	if !ok {
+		return false
+	}
+	// [s]G == R + [e]P
+	g := s.group
+	e := s.challenge(r, pk, message)
+	return g.Equal(g.ScalarBaseMult(sig.S), g.Add(r, g.ScalarMult(pk, e)))
+}
+
+// BatchVerify reports whether every sigs[i] is a signature of messages[i]
+// under pks[i]: with a_1 = 1 and random a_i it checks
+// [sum a_i s_i]G = sum [a_i]R_i + sum [a_i e_i]P_i in one multi-scalar
+// multiplication of the group. It fails if any signature fails.
+func (s *Schnorr) BatchVerify(pks []group.Point, messages [][]byte, sigs []*Signature) bool {
+	if len(pks) != len(messages) || len(pks) != len(sigs) {
+		return false
+	}
+	g := s.group
+	points := make([]group.Point, 0, 2*len(sigs)+1)
+	scalars := make([]*big.Int, 0, 2*len(sigs)+1)
+	sum := new(big.Int)
+	for i, sig := range sigs {
+		if pks[i] == nil || sig == nil || !s.isScalar(sig.S) || g.IsIdentity(pks[i]) {
+			return false
+		}
+		r, ok := s.nonce(sig.R)
+		if !ok {
+			return false
+		}
+		a := big.NewInt(1)
+		if i > 0 {
+			var err error
+			if a, err = group.RandomScalar(g, nil); err != nil {
+				return false
+			}
+		}
+		e := s.challenge(r, pks[i], messages[i])
+		sum.Add(sum, new(big.Int).Mul(a, sig.S))
+		points = append(points, r, pks[i])
+		scalars = append(scalars, a, e.Mul(e, a))
+	}
+	sum.Mod(sum, g.Order())
+	points = append(points, g.Generator())
+	scalars = append(scalars, sum.Sub(g.Order(), sum))
+	res, err := group.MultiScalarMult(g, points, scalars)
+	return err == nil && g.IsIdentity(res)
+}
@@ -0,0 +1,124 @@
+package schnorr
+
+import (
+	"math/big"
+	"testing"
+
+	"scrypto/ecc/group"
+)
+
+var schnorrs = []*Schnorr{NewSchnorr(group.P256()), NewSchnorr(group.Secp256k1())}
+
+func TestSchnorr_Sign(t *testing.T) {
+	for _, s := range schnorrs {
+		name := s.Group().Name()
+		sk, pk, err := s.GenerateKeys()
+		if err != nil {
+			panic(err)
+		}
+		m := []byte("message")
+		sig, err := s.Sign(sk, m)
+		if err != nil {
+			panic(err)
+		}
+		if !s.Verify(pk, m, sig) {
+			t.Errorf("%s: signature not verified", name)
+		}
+		if s.Verify(pk, []byte("another message"), sig) {
+			t.Errorf("%s: signature of another message verified", name)
+		}
+		_, other, err := s.GenerateKeys()
+		if err != nil {
+			panic(err)
+		}
+		if s.Verify(other, m, sig) {
+			t.Errorf("%s: signature verified under another key", name)
+		}
+		bad := &Signature{R: sig.R, S: new(big.Int).Add(sig.S, big.NewInt(1))}
+		if s.Verify(pk, m, bad) {
+			t.Errorf("%s: tampered signature verified", name)
+		}
+		bad = &Signature{R: sig.R, S: new(big.Int).Add(sig.S, s.Group().Order())}
+		if s.Verify(pk, m, bad) {
+			t.Errorf("%s: unreduced signature verified", name)
+		}
+		if _, err := s.Sign(s.Group().Order(), m); err == nil {
+			t.Errorf("%s: secret key out of range accepted", name)
+		}
+	}
+}
+
+func TestSchnorr_Adaptor(t *testing.T) {
+	for _, s := range schnorrs {
+		name := s.Group().Name()
+		g := s.Group()
+		alice, alicePk, err := s.GenerateKeys()
+		if err != nil {
+			panic(err)
+		}
+		bob, bobPk, err := s.GenerateKeys()
+		if err != nil {
+			panic(err)
+		}
+		// Alice knows t and both sign their transfer as a pre-signature on T
+		secret, T, err := s.GenerateKeys()
+		if err != nil {
+			panic(err)
+		}
+		toBob, toAlice := []byte("alice pays bob"), []byte("bob pays alice")
+		preA, err := s.PreSign(alice, toBob, T)
+		if err != nil {
+			panic(err)
+		}
+		preB, err := s.PreSign(bob, toAlice, T)
+		if err != nil {
+			panic(err)
+		}
+		if !s.PreVerify(alicePk, toBob, T, preA) || !s.PreVerify(bobPk, toAlice, T, preB) {
+			t.Fatalf("%s: pre-signature not verified", name)
+		}
+		if s.PreVerify(bobPk, toBob, T, preA) || s.PreVerify(alicePk, toAlice, T, preA) {
+			t.Errorf("%s: pre-signature verified under another key or message", name)
+		}
+		if s.PreVerify(alicePk, toBob, g.Generator(), preA) {
+			t.Errorf("%s: pre-signature verified for another statement", name)
+		}
+		// a pre-signature is not a signature
+		if s.Verify(alicePk, toBob, &Signature{R: preA.R, S: preA.S}) {
+			t.Errorf("%s: pre-signature verified as a signature", name)
+		}
+		if _, err := s.Adapt(preB, new(big.Int).Add(secret, big.NewInt(1))); err == nil {
+			t.Errorf("%s: adapted with a wrong witness", name)
+		}
+		// Alice claims her payment, which reveals t to Bob
+		sigB, err := s.Adapt(preB, secret)
+		if err != nil {
+			panic(err)
+		}
+		if !s.Verify(bobPk, toAlice, sigB) {
+			t.Errorf("%s: adapted signature not verified", name)
+		}
+		extracted, err := s.Extract(sigB, preB)
+		if err != nil {
+			panic(err)
+		}
+		if extracted.Cmp(secret) != 0 {
+			t.Errorf("%s: extracted witness not match", name)
+		}
+		sigA, err := s.Adapt(preA, extracted)
+		if err != nil {
+			panic(err)
+		}
+		if !s.Verify(alicePk, toBob, sigA) {
+			t.Errorf("%s: signature of Bob not verified", name)
+		}
+		// an unrelated signature reveals nothing
+		plain, err := s.Sign(bob, toAlice)
+		if err != nil {
+			panic(err)
+		}
+		if _, err := s.Extract(plain, preB); err == nil {
+			t.Errorf("%s: witness extracted from an unrelated signature", name)
+		}
+	}
+}
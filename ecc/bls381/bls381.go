@@ -58,16 +58,16 @@ func initBLS381() {
 	// A, B coeffs of the curve in Mont form
 	bls381.B.SetUint64(4)
 
-	// Setting G1Jac
-	bls381.G1Gen.X.SetString("2407661716269791519325591009883849385849641130669941829988413640673772478386903154468379397813974815295049686961384")
-	bls381.G1Gen.Y.SetString("821462058248938975967615814494474302717441302457255475448080663619194518120412959273482223614332657512049995916067")
+	// Setting G1Jac, the standard generator of the IETF and ZCash specifications
+	bls381.G1Gen.X.SetString("3685416753713387016781088315183077757961620795782546409894578378688607592378376318836054947676345821548104185464507")
+	bls381.G1Gen.Y.SetString("1339506544944476473020471379941921221584933875938349620426543736416511423956333506472724655353366534992391756441569")
 	bls381.G1Gen.Z.SetString("1")
 
-	// Setting G2Jac
-	bls381.G2Gen.X.SetString("3914881020997020027725320596272602335133880006033342744016315347583472833929664105802124952724390025419912690116411",
-		"277275454976865553761595788585036366131740173742845697399904006633521909118147462773311856983264184840438626176168")
-	bls381.G2Gen.Y.SetString("253800087101532902362860387055050889666401414686580130872654083467859828854605749525591159464755920666929166876282",
-		"1710145663789443622734372402738721070158916073226464929008132596760920130516982819361355832232719175024697380252309")
+	// Setting G2Jac, the standard generator of the IETF and ZCash specifications
+	bls381.G2Gen.X.SetString("352701069587466618187139116011060144890029952792775240219908644239793785735715026873347600343865175952761926303160",
+		"3059144344244213709971259814753781636986470325476647558659373206291635324768958432433509563104347017837885763365758")
+	bls381.G2Gen.Y.SetString("1985150602287291935568054521177171638300868978215655730859378665066344726373823718423869104263333984641494340347905",
+		"927553665492332455747201965776037880757740193453592970025027978793976877002675564980949289727957565575433344219582")
 	bls381.G2Gen.Z.SetString("1",
 		"0")
 
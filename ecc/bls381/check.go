@@ -0,0 +1,53 @@
+package bls381
+
+import (
+	"scrypto/ecc/bls381/fp"
+)
+
+// IsOnCurve reports whether p is on y^2 = x^3 + 4, p is not the infinity
+func (p *G1Affine) IsOnCurve(curve *Curve) bool {
+	var left, right fp.Element
+	left.Square(&p.Y)
+	right.Square(&p.X).Mul(&right, &p.X).Add(&right, &curve.B)
+	return left.Equal(&right)
+}
+
+// IsOnCurve reports whether p is on the twist y^2 = x^3 + 4(u+1), p is not
+// the infinity
+func (p *G2Affine) IsOnCurve() bool {
+	var left, right, bTwist e2
+	bTwist.SetString("4", "4")
+	left.Square(&p.Y)
+	right.Square(&p.X).Mul(&right, &p.X).Add(&right, &bTwist)
+	return left.Equal(&right)
+}
+
+// xAbs is |x|, x = -0xd201000000010000 is the parameter of the curve
+const xAbs uint64 = 0xd201000000010000
+
+// cubeRoot is the cube root of unity of fp such that phi(x, y) = (cubeRoot x, y)
+// acts on G1 as [-x^2]
+var cubeRoot fp.Element
+
+func init() {
+	cubeRoot.SetString("793479390729215512621379701633421447060886740281060493010456487427281649075476305620758731620350")
+}
+
+// IsInSubGroup reports whether p, a point of the curve, is in G1 with the
+// endomorphism test phi(p) = [-x^2]p (Scott, ePrint 2021/1130)
+func (p *G1Jac) IsInSubGroup(curve *Curve) bool {
+	var q, phi G1Jac
+	q.ScalarMulUint64(curve, p, xAbs).ScalarMulUint64(curve, &q, xAbs).Neg(&q)
+	phi.Set(p)
+	phi.X.MulAssign(&cubeRoot)
+	return phi.Equal(&q)
+}
+
+// IsInSubGroup reports whether p, a point of the twist, is in G2 with the
+// endomorphism test psi(p) = [x]p (Scott, ePrint 2021/1130)
+func (p *G2Jac) IsInSubGroup(curve *Curve) bool {
+	var q, psi G2Jac
+	q.ScalarMulUint64(curve, p, xAbs).Neg(&q)
+	psi.psi(p)
+	return psi.Equal(&q)
+}
@@ -233,6 +233,56 @@ func (z *e2) Conjugate(x *e2) *e2 {
 	return z
 }
 
+// Legendre returns 1 if z is a nonzero square, -1 if it is not a square and 0
+// if z == 0: z is a square in e2 iff its norm A0^2 + A1^2 is a square in fp
+func (z *e2) Legendre() int {
+	var n, t fp.Element
+	n.Square(&z.A0)
+	t.Square(&z.A1)
+	n.Add(&n, &t)
+	return n.Legendre()
+}
+
+// Sqrt sets z to a square root of x and returns z, if x is not a square it
+// leaves z unchanged and returns nil
+func (z *e2) Sqrt(x *e2) *e2 {
+	// complex method: with n = sqrt(A0^2 + A1^2), sqrt(x) = a + A1/(2a)u
+	// where a^2 = (A0 + n)/2 or (A0 - n)/2
+	var a, t fp.Element
+	if x.A1.IsZero() {
+		if t.Sqrt(&x.A0) != nil {
+			z.A0.Set(&t)
+			z.A1.SetZero()
+			return z
+		}
+		// every element of fp is a square in e2: -A0 is a square in fp
+		a.Neg(&x.A0)
+		t.Sqrt(&a)
+		z.A0.SetZero()
+		z.A1.Set(&t)
+		return z
+	}
+	var n, half fp.Element
+	n.Square(&x.A0)
+	t.Square(&x.A1)
+	n.Add(&n, &t)
+	if n.Sqrt(&n) == nil {
+		return nil
+	}
+	half.SetUint64(2).Inverse(&half)
+	a.Add(&x.A0, &n).MulAssign(&half)
+	if t.Sqrt(&a) == nil {
+		a.Sub(&x.A0, &n).MulAssign(&half)
+		if t.Sqrt(&a) == nil {
+			return nil
+		}
+	}
+	a.Double(&t).Inverse(&a)
+	z.A1.Mul(&x.A1, &a)
+	z.A0.Set(&t)
+	return z
+}
+
 // MulByNonResidue multiplies a fp.Element by -1
 // It would be nice to make this a method of fp.Element but fp.Element is outside this package
 func MulByNonResidue(out, in *fp.Element) *fp.Element {
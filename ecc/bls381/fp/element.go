@@ -103,6 +103,11 @@ func (z *Element) IsZero() bool {
 var _elementModulusBigInt big.Int
 var onceelementModulus sync.Once
 
+// ElementModulus returns the field modulus, do not modify it
+func ElementModulus() *big.Int {
+	return elementModulusBigInt()
+}
+
 func elementModulusBigInt() *big.Int {
 	onceelementModulus.Do(func() {
 		_elementModulusBigInt.SetString("4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559787", 10)
@@ -477,24 +482,85 @@ func (z *Element) SubAssign(x *Element) *Element {
 	return z
 }
 
-// Exp z = x^e mod q
-func (z *Element) Exp(x Element, e uint64) *Element {
-	if e == 0 {
+// Exp z = x^exponent mod q
+// (not optimized)
+// exponent (non-montgomery form) is ordered from least significant word to most significant word
+func (z *Element) Exp(x Element, exponent ...uint64) *Element {
+	r := 0
+	msb := 0
+	for i := len(exponent) - 1; i >= 0; i-- {
+		if exponent[i] == 0 {
+			r++
+		} else {
+			msb = (i * 64) + bits.Len64(exponent[i])
+			break
+		}
+	}
+	exponent = exponent[:len(exponent)-r]
+	if len(exponent) == 0 {
 		return z.SetOne()
 	}
 
 	z.Set(&x)
 
-	l := bits.Len64(e) - 2
+	l := msb - 2
 	for i := l; i >= 0; i-- {
 		z.Square(z)
-		if e&(1<<uint(i)) != 0 {
+		if exponent[i/64]&(1<<uint(i%64)) != 0 {
 			z.MulAssign(&x)
 		}
 	}
 	return z
 }
 
+// Legendre returns the Legendre symbol of z (either +1, -1, or 0.)
+func (z *Element) Legendre() int {
+	var l Element
+	// z^((q-1)/2)
+	l.Exp(*z,
+		15924587544893707605,
+		1105070755758604287,
+		12941209323636816658,
+		12843041017062132063,
+		2706051889235351147,
+		936899308823769933,
+	)
+
+	if l.IsZero() {
+		return 0
+	}
+
+	// if l == 1
+	var one Element
+	if l.Equal(one.SetOne()) {
+		return 1
+	}
+	return -1
+}
+
+// Sqrt z = √x mod q
+// if the square root doesn't exist (x is not a square mod q)
+// Sqrt leaves z unchanged and returns nil
+func (z *Element) Sqrt(x *Element) *Element {
+	// q ≡ 3 (mod 4)
+	// using  z ≡ ± x^((p+1)/4) (mod q)
+	var y, square Element
+	y.Exp(*x,
+		17185665809301629611,
+		552535377879302143,
+		15693976698673184137,
+		15644892545385841839,
+		10576397981472451381,
+		468449654411884966,
+	)
+	// as we didn't compute the legendre symbol, ensure we found y such that y * y = x
+	square.Square(&y)
+	if square.Equal(x) {
+		return z.Set(&y)
+	}
+	return nil
+}
+
 // FromMont converts z in place (i.e. mutates) from Montgomery to regular representation
 // sets and returns z = z * 1
 func (z *Element) FromMont() *Element {
@@ -650,6 +716,27 @@ func (z *Element) SetBigInt(v *big.Int) *Element {
 	return z.ToMont()
 }
 
+// Bytes returns the regular (non montgomery) value
+// of z as a big-endian byte slice.
+func (z *Element) Bytes() []byte {
+	var _z Element
+	_z.Set(z).FromMont()
+	res := make([]byte, ElementLimbs*8)
+	for i := 0; i < ElementLimbs; i++ {
+		binary.BigEndian.PutUint64(res[i*8:(i+1)*8], _z[ElementLimbs-1-i])
+	}
+	return res
+}
+
+// SetBytes interprets e as the bytes of a big-endian unsigned integer,
+// sets z to that value mod q (in Montgomery form), and returns z.
+func (z *Element) SetBytes(e []byte) *Element {
+	var tmp big.Int
+	tmp.SetBytes(e)
+	tmp.Mod(&tmp, elementModulusBigInt())
+	return z.SetBigInt(&tmp)
+}
+
 // SetString creates a big.Int with s (in base 10) and calls SetBigInt on z
 func (z *Element) SetString(s string) *Element {
 	x, ok := new(big.Int).SetString(s, 10)
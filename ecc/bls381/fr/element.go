@@ -93,6 +93,11 @@ func (z *Element) IsZero() bool {
 var _elementModulusBigInt big.Int
 var onceelementModulus sync.Once
 
+// ElementModulus returns the field modulus, do not modify it
+func ElementModulus() *big.Int {
+	return elementModulusBigInt()
+}
+
 func elementModulusBigInt() *big.Int {
 	onceelementModulus.Do(func() {
 		_elementModulusBigInt.SetString("52435875175126190479447740508185965837690552500527637822603658699938581184513", 10)
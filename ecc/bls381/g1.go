@@ -2,6 +2,7 @@
 package bls381
 
 import (
+	"math/bits"
 	"runtime"
 	"scrypto/ecc/bls381/fp"
 	"scrypto/ecc/bls381/fr"
@@ -371,6 +372,26 @@ func (p *G1Jac) ScalarMul(curve *Curve, a *G1Jac, scalar fr.Element) *G1Jac {
 	return p.pippenger(curve, []G1Jac{*a}, []fr.Element{scalar}, s, b, T[:], computeT)
 }
 
+// ScalarMulUint64 multiplies a by the 64 bits scalar k with double-and-add,
+// cheaper than ScalarMul for small scalars
+func (p *G1Jac) ScalarMulUint64(curve *Curve, a *G1Jac, k uint64) *G1Jac {
+	if k == 0 {
+		p.X.SetOne()
+		p.Y.SetOne()
+		p.Z.SetZero()
+		return p
+	}
+	var res G1Jac
+	res.Set(a)
+	for i := bits.Len64(k) - 2; i >= 0; i-- {
+		res.Double()
+		if (k>>uint(i))&1 == 1 {
+			res.Add(curve, a)
+		}
+	}
+	return p.Set(&res)
+}
+
 // ScalarMulByGen multiplies curve.G1Gen by scalar
 // algorithm: a special case of Pippenger described by Bootle:
 // https://jbootle.github.io/Misc/pippenger.pdf
@@ -3,6 +3,7 @@
 package bls381
 
 import (
+	"math/bits"
 	"runtime"
 	"sync"
 
@@ -372,6 +373,26 @@ func (p *G2Jac) ScalarMul(curve *Curve, a *G2Jac, scalar fr.Element) *G2Jac {
 	return p.pippenger(curve, []G2Jac{*a}, []fr.Element{scalar}, s, b, T[:], computeT)
 }
 
+// ScalarMulUint64 multiplies a by the 64 bits scalar k with double-and-add,
+// cheaper than ScalarMul for small scalars
+func (p *G2Jac) ScalarMulUint64(curve *Curve, a *G2Jac, k uint64) *G2Jac {
+	if k == 0 {
+		p.X.SetOne()
+		p.Y.SetOne()
+		p.Z.SetZero()
+		return p
+	}
+	var res G2Jac
+	res.Set(a)
+	for i := bits.Len64(k) - 2; i >= 0; i-- {
+		res.Double()
+		if (k>>uint(i))&1 == 1 {
+			res.Add(curve, a)
+		}
+	}
+	return p.Set(&res)
+}
+
 // ScalarMulByGen multiplies curve.G2Gen by scalar
 // algorithm: a special case of Pippenger described by Bootle:
 // https://jbootle.github.io/Misc/pippenger.pdf
@@ -0,0 +1,406 @@
+package bls381
+
+import (
+	"sync"
+
+	"scrypto/ecc/bls381/fp"
+	"scrypto/sutils/xmd"
+)
+
+// Hashing to G1 and G2 with the suites BLS12381G1_XMD:SHA-256_SSWU_RO_ and
+// BLS12381G2_XMD:SHA-256_SSWU_RO_ of RFC 9380: hash_to_field with
+// expand_message_xmd, the simplified SWU map to a curve isogenous to E or
+// Etwist, the isogeny and the cofactor clearing.
+
+// hashToFieldL is the number of bytes hashed into one element of fp
+const hashToFieldL = 64
+
+// coefficients of the isogenies, lowest degree first, the denominators are
+// monic and their leading coefficient is omitted
+var (
+	g1IsoXNum = []string{
+		"2712959285290305970661081772124144179193819192423276218370281158706191519995889425075952244140278856085036081760695",
+		"3564859427549639835253027846704205725951033235539816243131874237388832081954622352624080767121604606753339903542203",
+		"2051387046688339481714726479723076305756384619135044672831882917686431912682625619320120082313093891743187631791280",
+		"3612713941521031012780325893181011392520079402153354595775735142359240110423346445050803899623018402874731133626465",
+		"2247053637822768981792833880270996398470828564809439728372634811976089874056583714987807553397615562273407692740057",
+		"3415427104483187489859740871640064348492611444552862448295571438270821994900526625562705192993481400731539293415811",
+		"2067521456483432583860405634125513059912765526223015704616050604591207046392807563217109432457129564962571408764292",
+		"3650721292069012982822225637849018828271936405382082649291891245623305084633066170122780668657208923883092359301262",
+		"1239271775787030039269460763652455868148971086016832054354147730155061349388626624328773377658494412538595239256855",
+		"3479374185711034293956731583912244564891370843071137483962415222733470401948838363051960066766720884717833231600798",
+		"2492756312273161536685660027440158956721981129429869601638362407515627529461742974364729223659746272460004902959995",
+		"1058488477413994682556770863004536636444795456512795473806825292198091015005841418695586811009326456605062948114985",
+	}
+	g1IsoXDen = []string{
+		"1353092447850172218905095041059784486169131709710991428415161466575141675351394082965234118340787683181925558786844",
+		"2822220997908397120956501031591772354860004534930174057793539372552395729721474912921980407622851861692773516917759",
+		"1717937747208385987946072944131378949849282930538642983149296304709633281382731764122371874602115081850953846504985",
+		"501624051089734157816582944025690868317536915684467868346388760435016044027032505306995281054569109955275640941784",
+		"3025903087998593826923738290305187197829899948335370692927241015584233559365859980023579293766193297662657497834014",
+		"2224140216975189437834161136818943039444741035168992629437640302964164227138031844090123490881551522278632040105125",
+		"1146414465848284837484508420047674663876992808692209238763293935905506532411661921697047880549716175045414621825594",
+		"3179090966864399634396993677377903383656908036827452986467581478509513058347781039562481806409014718357094150199902",
+		"1549317016540628014674302140786462938410429359529923207442151939696344988707002602944342203885692366490121021806145",
+		"1442797143427491432630626390066422021593505165588630398337491100088557278058060064930663878153124164818522816175370",
+	}
+	g1IsoYNum = []string{
+		"1393399195776646641963150658816615410692049723305861307490980409834842911816308830479576739332720113414154429643571",
+		"2968610969752762946134106091152102846225411740689724909058016729455736597929366401532929068084731548131227395540630",
+		"122933100683284845219599644396874530871261396084070222155796123161881094323788483360414289333111221370374027338230",
+		"303251954782077855462083823228569901064301365507057490567314302006681283228886645653148231378803311079384246777035",
+		"1353972356724735644398279028378555627591260676383150667237975415318226973994509601413730187583692624416197017403099",
+		"3443977503653895028417260979421240655844034880950251104724609885224259484262346958661845148165419691583810082940400",
+		"718493410301850496156792713845282235942975872282052335612908458061560958159410402177452633054233549648465863759602",
+		"1466864076415884313141727877156167508644960317046160398342634861648153052436926062434809922037623519108138661903145",
+		"1536886493137106337339531461344158973554574987550750910027365237255347020572858445054025958480906372033954157667719",
+		"2171468288973248519912068884667133903101171670397991979582205855298465414047741472281361964966463442016062407908400",
+		"3915937073730221072189646057898966011292434045388986394373682715266664498392389619761133407846638689998746172899634",
+		"3802409194827407598156407709510350851173404795262202653149767739163117554648574333789388883640862266596657730112910",
+		"1707589313757812493102695021134258021969283151093981498394095062397393499601961942449581422761005023512037430861560",
+		"349697005987545415860583335313370109325490073856352967581197273584891698473628451945217286148025358795756956811571",
+		"885704436476567581377743161796735879083481447641210566405057346859953524538988296201011389016649354976986251207243",
+		"3370924952219000111210625390420697640496067348723987858345031683392215988129398381698161406651860675722373763741188",
+	}
+	g1IsoYDen = []string{
+		"3396434800020507717552209507749485772788165484415495716688989613875369612529138640646200921379825018840894888371137",
+		"3907278185868397906991868466757978732688957419873771881240086730384895060595583602347317992689443299391009456758845",
+		"854914566454823955479427412036002165304466268547334760894270240966182605542146252771872707010378658178126128834546",
+		"3496628876382137961119423566187258795236027183112131017519536056628828830323846696121917502443333849318934945158166",
+		"1828256966233331991927609917644344011503610008134915752990581590799656305331275863706710232159635159092657073225757",
+		"1362317127649143894542621413133849052553333099883364300946623208643344298804722863920546222860227051989127113848748",
+		"3443845896188810583748698342858554856823966611538932245284665132724280883115455093457486044009395063504744802318172",
+		"3484671274283470572728732863557945897902920439975203610275006103818288159899345245633896492713412187296754791689945",
+		"3755735109429418587065437067067640634211015783636675372165599470771975919172394156249639331555277748466603540045130",
+		"3459661102222301807083870307127272890283709299202626530836335779816726101522661683404130556379097384249447658110805",
+		"742483168411032072323733249644347333168432665415341249073150659015707795549260947228694495111018381111866512337576",
+		"1662231279858095762833829698537304807741442669992646287950513237989158777254081548205552083108208170765474149568658",
+		"1668238650112823419388205992952852912407572045257706138925379268508860023191233729074751042562151098884528280913356",
+		"369162719928976119195087327055926326601627748362769544198813069133429557026740823593067700396825489145575282378487",
+		"2164195715141237148945939585099633032390257748382945597506236650132835917087090097395995817229686247227784224263055",
+	}
+	g2IsoXNum = [][2]string{
+		{"889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235542", "889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235542"},
+		{"0", "2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706522"},
+		{"2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706526", "1334136518407222464472596608578634718852294273313002628444019378708010550163612621480895876376338554679298090853261"},
+		{"3557697382419259905260257622876359250272784728834673675850718343221361467102966990615722337003569479144794908942033", "0"},
+	}
+	g2IsoXDen = [][2]string{
+		{"0", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559715"},
+		{"12", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559775"},
+	}
+	g2IsoYNum = [][2]string{
+		{"3261222600550988246488569487636662646083386001431784202863158481286248011511053074731078808919938689216061999863558", "3261222600550988246488569487636662646083386001431784202863158481286248011511053074731078808919938689216061999863558"},
+		{"0", "889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235518"},
+		{"2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706524", "1334136518407222464472596608578634718852294273313002628444019378708010550163612621480895876376338554679298090853263"},
+		{"2816510427748580758331037284777117739799287910327449993381818688383577828123182200904113516794492504322962636245776", "0"},
+	}
+	g2IsoYDen = [][2]string{
+		{"4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559355", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559355"},
+		{"0", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559571"},
+		{"18", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559769"},
+	}
+)
+
+// hashToCurve holds the constants of the maps, parsed once
+var hashToCurve struct {
+	once sync.Once
+
+	g1A, g1B, g1Z                  fp.Element
+	g1XNum, g1XDen, g1YNum, g1YDen []fp.Element
+
+	g2A, g2B, g2Z                  e2
+	g2XNum, g2XDen, g2YNum, g2YDen []e2
+
+	// psi(x, y) = (conj(x) * psiX, conj(y) * psiY), 1/(1+u)^((p-1)/3) and 1/(1+u)^((p-1)/2)
+	psiX, psiY e2
+}
+
+func initHashToCurve() {
+	h := &hashToCurve
+
+	// E1': y^2 = x^3 + A'x + B', Z = 11
+	h.g1A.SetString("12190336318893619529228877361869031420615612348429846051986726275283378313155663745811710833465465981901188123677")
+	h.g1B.SetString("2906670324641927570491258158026293881577086121416628140204402091718288198173574630967936031029026176254968826637280")
+	h.g1Z.SetUint64(11)
+	h.g1XNum = fpElements(g1IsoXNum)
+	h.g1XDen = fpElements(g1IsoXDen)
+	h.g1YNum = fpElements(g1IsoYNum)
+	h.g1YDen = fpElements(g1IsoYDen)
+
+	// E2': y^2 = x^3 + 240u x + 1012(1+u), Z = -(2+u)
+	h.g2A.A1.SetUint64(240)
+	h.g2B.A0.SetUint64(1012)
+	h.g2B.A1.SetUint64(1012)
+	h.g2Z.A0.SetUint64(2)
+	h.g2Z.A1.SetUint64(1)
+	h.g2Z.Neg(&h.g2Z)
+	h.g2XNum = e2Elements(g2IsoXNum)
+	h.g2XDen = e2Elements(g2IsoXDen)
+	h.g2YNum = e2Elements(g2IsoYNum)
+	h.g2YDen = e2Elements(g2IsoYDen)
+
+	h.psiX.SetString("0",
+		"4002409555221667392624310435006688643935503118305586438271171395842971157480381377015405980053539358417135540939437")
+	h.psiY.SetString("2973677408986561043442465346520108879172042883009249989176415018091420807192182638567116318576472649347015917690530",
+		"1028732146235106349975324479215795277384839936929757896155643118032610843298655225875571310552543014690878354869257")
+}
+
+func fpElements(s []string) []fp.Element {
+	res := make([]fp.Element, len(s))
+	for i := range s {
+		res[i].SetString(s[i])
+	}
+	return res
+}
+
+func e2Elements(s [][2]string) []e2 {
+	res := make([]e2, len(s))
+	for i := range s {
+		res[i].SetString(s[i][0], s[i][1])
+	}
+	return res
+}
+
+// hashToFp is hash_to_field of RFC 9380 to count elements of fp
+func hashToFp(msg, dst []byte, count int) ([]fp.Element, error) {
+	uniform, err := xmd.Expand(msg, dst, count*hashToFieldL)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]fp.Element, count)
+	for i := range res {
+		res[i].SetBytes(uniform[i*hashToFieldL : (i+1)*hashToFieldL])
+	}
+	return res, nil
+}
+
+// sgn0 returns the sign of x as defined in RFC 9380, the parity of its
+// regular form
+func sgn0(x *fp.Element) uint64 {
+	return x.ToRegular()[0] & 1
+}
+
+// sgn0 returns the sign of z as defined in RFC 9380 for m = 2
+func (z *e2) sgn0() uint64 {
+	sign := sgn0(&z.A0)
+	if z.A0.IsZero() {
+		sign |= sgn0(&z.A1)
+	}
+	return sign
+}
+
+// mapToCurve1 is the simplified SWU map of RFC 9380 (section 6.6.2) to E1'
+func mapToCurve1(u *fp.Element) G1Affine {
+	h := &hashToCurve
+	var tv1, x1, x2, gx, y, t fp.Element
+
+	// tv1 = 1 / (Z^2 u^4 + Z u^2)
+	t.Square(u).MulAssign(&h.g1Z)
+	tv1.Square(&t).AddAssign(&t)
+	if tv1.IsZero() {
+		// x1 = B / (Z A)
+		x1.Mul(&h.g1Z, &h.g1A)
+		x1.Div(&h.g1B, &x1)
+	} else {
+		// x1 = (-B / A) (1 + tv1)
+		tv1.Inverse(&tv1)
+		tv1.AddAssign(new(fp.Element).SetOne())
+		x1.Div(&h.g1B, &h.g1A).Neg(&x1).MulAssign(&tv1)
+	}
+	// x2 = Z u^2 x1
+	x2.Mul(&t, &x1)
+
+	res := G1Affine{X: x1}
+	g1IsoRHS(&gx, &x1)
+	if y.Sqrt(&gx) == nil {
+		res.X = x2
+		g1IsoRHS(&gx, &x2)
+		y.Sqrt(&gx)
+	}
+	if sgn0(u) != sgn0(&y) {
+		y.Neg(&y)
+	}
+	res.Y = y
+	return res
+}
+
+// g1IsoRHS sets z = x^3 + A'x + B'
+func g1IsoRHS(z, x *fp.Element) {
+	var t fp.Element
+	t.Square(x).AddAssign(&hashToCurve.g1A).MulAssign(x)
+	z.Add(&t, &hashToCurve.g1B)
+}
+
+// mapToCurve2 is the simplified SWU map of RFC 9380 (section 6.6.2) to E2'
+func mapToCurve2(u *e2) G2Affine {
+	h := &hashToCurve
+	var tv1, x1, x2, gx, y, t e2
+
+	// tv1 = 1 / (Z^2 u^4 + Z u^2)
+	t.Square(u).MulAssign(&h.g2Z)
+	tv1.Square(&t).AddAssign(&t)
+	if tv1.IsZero() {
+		// x1 = B / (Z A)
+		x1.Mul(&h.g2Z, &h.g2A).Inverse(&x1).MulAssign(&h.g2B)
+	} else {
+		// x1 = (-B / A) (1 + tv1)
+		tv1.Inverse(&tv1)
+		tv1.AddAssign(new(e2).SetOne())
+		x1.Inverse(&h.g2A).MulAssign(&h.g2B).Neg(&x1).MulAssign(&tv1)
+	}
+	// x2 = Z u^2 x1
+	x2.Mul(&t, &x1)
+
+	res := G2Affine{X: x1}
+	g2IsoRHS(&gx, &x1)
+	if y.Sqrt(&gx) == nil {
+		res.X = x2
+		g2IsoRHS(&gx, &x2)
+		y.Sqrt(&gx)
+	}
+	if u.sgn0() != y.sgn0() {
+		y.Neg(&y)
+	}
+	res.Y = y
+	return res
+}
+
+// g2IsoRHS sets z = x^3 + A'x + B'
+func g2IsoRHS(z, x *e2) {
+	var t e2
+	t.Square(x).AddAssign(&hashToCurve.g2A).MulAssign(x)
+	z.Add(&t, &hashToCurve.g2B)
+}
+
+// evalFp sets z to the polynomial of coefficients c at x, plus x^len(c) if monic
+func evalFp(z *fp.Element, c []fp.Element, monic bool, x *fp.Element) {
+	var res fp.Element
+	if monic {
+		res.SetOne()
+	} else {
+		res.Set(&c[len(c)-1])
+		c = c[:len(c)-1]
+	}
+	for i := len(c) - 1; i >= 0; i-- {
+		res.MulAssign(x).AddAssign(&c[i])
+	}
+	z.Set(&res)
+}
+
+// evalE2 sets z to the polynomial of coefficients c at x, plus x^len(c) if monic
+func evalE2(z *e2, c []e2, monic bool, x *e2) {
+	var res e2
+	if monic {
+		res.SetOne()
+	} else {
+		res.Set(&c[len(c)-1])
+		c = c[:len(c)-1]
+	}
+	for i := len(c) - 1; i >= 0; i-- {
+		res.MulAssign(x).AddAssign(&c[i])
+	}
+	z.Set(&res)
+}
+
+// g1Isogeny maps p from E1' to E, a point of the kernel goes to the infinity (0, 0)
+func g1Isogeny(p *G1Affine) {
+	h := &hashToCurve
+	var xNum, xDen, yNum, yDen fp.Element
+	evalFp(&xNum, h.g1XNum, false, &p.X)
+	evalFp(&xDen, h.g1XDen, true, &p.X)
+	evalFp(&yNum, h.g1YNum, false, &p.X)
+	evalFp(&yDen, h.g1YDen, true, &p.X)
+	p.X.Div(&xNum, &xDen)
+	p.Y.MulAssign(&yNum).MulAssign(yDen.Inverse(&yDen))
+}
+
+// g2Isogeny maps p from E2' to Etwist, a point of the kernel goes to the infinity (0, 0)
+func g2Isogeny(p *G2Affine) {
+	h := &hashToCurve
+	var xNum, xDen, yNum, yDen e2
+	evalE2(&xNum, h.g2XNum, false, &p.X)
+	evalE2(&xDen, h.g2XDen, true, &p.X)
+	evalE2(&yNum, h.g2YNum, false, &p.X)
+	evalE2(&yDen, h.g2YDen, true, &p.X)
+	p.X.Mul(&xNum, xDen.Inverse(&xDen))
+	p.Y.MulAssign(&yNum).MulAssign(yDen.Inverse(&yDen))
+}
+
+// ClearCofactor sets p = [h_eff]a, h_eff = 1 - x = 0xd201000000010001, and returns p
+func (p *G1Jac) ClearCofactor(curve *Curve, a *G1Jac) *G1Jac {
+	var res G1Jac
+	res.ScalarMulUint64(curve, a, xAbs).Add(curve, a)
+	return p.Set(&res)
+}
+
+// psi sets p to the untwist-Frobenius-twist endomorphism of a and returns p
+func (p *G2Jac) psi(a *G2Jac) *G2Jac {
+	initHashToCurveOnce()
+	p.X.Conjugate(&a.X).MulAssign(&hashToCurve.psiX)
+	p.Y.Conjugate(&a.Y).MulAssign(&hashToCurve.psiY)
+	p.Z.Conjugate(&a.Z)
+	return p
+}
+
+// ClearCofactor sets p = [h_eff]a with the endomorphism psi as in RFC 9380
+// (appendix G.3) and returns p
+func (p *G2Jac) ClearCofactor(curve *Curve, a *G2Jac) *G2Jac {
+	var t1, t2, t3, pa G2Jac
+	pa.Set(a)
+	t1.ScalarMulUint64(curve, &pa, xAbs)
+	t1.Neg(&t1)          // t1 = [x]P
+	t2.psi(&pa)          // t2 = psi(P)
+	t3.Set(&pa).Double() // t3 = 2P
+	t3.psi(&t3).psi(&t3) // t3 = psi^2(2P)
+	t3.Sub(curve, t2)    // t3 = psi^2(2P) - psi(P)
+	t2.Add(curve, &t1)   // t2 = [x]P + psi(P)
+	t2.ScalarMulUint64(curve, &t2, xAbs)
+	t2.Neg(&t2)        // t2 = [x]([x]P + psi(P))
+	t3.Add(curve, &t2) // t3 = t3 + t2
+	t3.Sub(curve, t1)  // t3 = t3 - [x]P
+	t3.Sub(curve, pa)  // t3 = t3 - P
+	return p.Set(&t3)
+}
+
+func initHashToCurveOnce() {
+	hashToCurve.once.Do(initHashToCurve)
+}
+
+// HashToG1 hashes msg to G1 with the suite BLS12381G1_XMD:SHA-256_SSWU_RO_
+// of RFC 9380 and the domain separation tag dst
+func HashToG1(msg, dst []byte) (*G1Jac, error) {
+	initHashToCurveOnce()
+	u, err := hashToFp(msg, dst, 2)
+	if err != nil {
+		return nil, err
+	}
+	curve := BLS381()
+	var q0, q1 G1Jac
+	for i, q := range []*G1Jac{&q0, &q1} {
+		p := mapToCurve1(&u[i])
+		g1Isogeny(&p)
+		p.ToJacobian(q)
+	}
+	q0.Add(curve, &q1)
+	return new(G1Jac).ClearCofactor(curve, &q0), nil
+}
+
+// HashToG2 hashes msg to G2 with the suite BLS12381G2_XMD:SHA-256_SSWU_RO_
+// of RFC 9380 and the domain separation tag dst
+func HashToG2(msg, dst []byte) (*G2Jac, error) {
+	initHashToCurveOnce()
+	u, err := hashToFp(msg, dst, 4)
+	if err != nil {
+		return nil, err
+	}
+	curve := BLS381()
+	var q0, q1 G2Jac
+	for i, q := range []*G2Jac{&q0, &q1} {
+		p := mapToCurve2(&e2{A0: u[2*i], A1: u[2*i+1]})
+		g2Isogeny(&p)
+		p.ToJacobian(q)
+	}
+	q0.Add(curve, &q1)
+	return new(G2Jac).ClearCofactor(curve, &q0), nil
+}
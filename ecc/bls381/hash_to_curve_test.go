@@ -0,0 +1,163 @@
+package bls381
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"scrypto/ecc/bls381/fp"
+)
+
+// test vectors of RFC 9380, appendix J.9.1, J.10.1 and K.1
+
+type hashToCurveVector struct {
+	msg  string
+	u    []string
+	x, y string
+}
+
+func fpHex(z *fp.Element) string {
+	return hex.EncodeToString(z.Bytes())
+}
+
+func e2Hex(z *e2) string {
+	return fpHex(&z.A0) + "," + fpHex(&z.A1)
+}
+
+func TestHashToG1(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-BLS12381G1_XMD:SHA-256_SSWU_RO_")
+	curve := BLS381()
+	for _, v := range hashToG1Vectors {
+		u, err := hashToFp([]byte(v.msg), dst, 2)
+		if err != nil {
+			panic(err)
+		}
+		if fpHex(&u[0]) != v.u[0] || fpHex(&u[1]) != v.u[1] {
+			t.Errorf("hash_to_field(%q) not match", v.msg)
+		}
+		p, err := HashToG1([]byte(v.msg), dst)
+		if err != nil {
+			panic(err)
+		}
+		var a G1Affine
+		p.ToAffineFromJac(&a)
+		if fpHex(&a.X) != v.x || fpHex(&a.Y) != v.y {
+			t.Errorf("HashToG1(%q) not match", v.msg)
+		}
+		if !a.IsOnCurve(curve) || !p.IsInSubGroup(curve) {
+			t.Errorf("HashToG1(%q) not in G1", v.msg)
+		}
+	}
+}
+
+func TestHashToG2(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_")
+	curve := BLS381()
+	for _, v := range hashToG2Vectors {
+		u, err := hashToFp([]byte(v.msg), dst, 4)
+		if err != nil {
+			panic(err)
+		}
+		if strings.Join([]string{fpHex(&u[0]), fpHex(&u[1])}, ",") != v.u[0] ||
+			strings.Join([]string{fpHex(&u[2]), fpHex(&u[3])}, ",") != v.u[1] {
+			t.Errorf("hash_to_field(%q) not match", v.msg)
+		}
+		p, err := HashToG2([]byte(v.msg), dst)
+		if err != nil {
+			panic(err)
+		}
+		var a G2Affine
+		p.ToAffineFromJac(&a)
+		if e2Hex(&a.X) != v.x || e2Hex(&a.Y) != v.y {
+			t.Errorf("HashToG2(%q) not match", v.msg)
+		}
+		if !a.IsOnCurve() || !p.IsInSubGroup(curve) {
+			t.Errorf("HashToG2(%q) not in G2", v.msg)
+		}
+	}
+}
+
+func TestE2_Sqrt(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		var x, sq, r e2
+		x.SetRandom()
+		if i%10 == 0 {
+			x.A1.SetZero()
+		}
+		sq.Square(&x)
+		if r.Sqrt(&sq) == nil {
+			t.Fatal("a square has no square root")
+		}
+		if r.Square(&r); !r.Equal(&sq) {
+			t.Error("Sqrt is not a square root")
+		}
+		if sq.Legendre() != 1 {
+			t.Error("Legendre of a square should be 1")
+		}
+		// 1 + u is not a square, so neither is its product with a square
+		var z e2
+		z.A0.SetUint64(1)
+		z.A1.SetUint64(1)
+		if z.Legendre() == 1 {
+			t.Error("1 + u should not be a square")
+		}
+		sq.MulAssign(&z)
+		if r.Sqrt(&sq) != nil {
+			t.Error("a non square has a square root")
+		}
+	}
+}
+
+var hashToG1Vectors = []hashToCurveVector{
+	{
+		msg: "",
+		u:   []string{"0ba14bd907ad64a016293ee7c2d276b8eae71f25a4b941eece7b0d89f17f75cb3ae5438a614fb61d6835ad59f29c564f", "019b9bd7979f12657976de2884c7cce192b82c177c80e0ec604436a7f538d231552f0d96d9f7babe5fa3b19b3ff25ac9"},
+		x:   "052926add2207b76ca4fa57a8734416c8dc95e24501772c814278700eed6d1e4e8cf62d9c09db0fac349612b759e79a1",
+		y:   "08ba738453bfed09cb546dbb0783dbb3a5f1f566ed67bb6be0e8c67e2e81a4cc68ee29813bb7994998f3eae0c9c6a265",
+	},
+	{
+		msg: "abc",
+		u:   []string{"0d921c33f2bad966478a03ca35d05719bdf92d347557ea166e5bba579eea9b83e9afa5c088573c2281410369fbd32951", "003574a00b109ada2f26a37a91f9d1e740dffd8d69ec0c35e1e9f4652c7dba61123e9dd2e76c655d956e2b3462611139"},
+		x:   "03567bc5ef9c690c2ab2ecdf6a96ef1c139cc0b2f284dca0a9a7943388a49a3aee664ba5379a7655d3c68900be2f6903",
+		y:   "0b9c15f3fe6e5cf4211f346271d7b01c8f3b28be689c8429c85b67af215533311f0b8dfaaa154fa6b88176c229f2885d",
+	},
+	{
+		msg: "abcdef0123456789",
+		u:   []string{"062d1865eb80ebfa73dcfc45db1ad4266b9f3a93219976a3790ab8d52d3e5f1e62f3b01795e36834b17b70e7b76246d4", "0cdc3e2f271f29c4ff75020857ce6c5d36008c9b48385ea2f2bf6f96f428a3deb798aa033cd482d1cdc8b30178b08e3a"},
+		x:   "11e0b079dea29a68f0383ee94fed1b940995272407e3bb916bbf268c263ddd57a6a27200a784cbc248e84f357ce82d98",
+		y:   "03a87ae2caf14e8ee52e51fa2ed8eefe80f02457004ba4d486d6aa1f517c0889501dc7413753f9599b099ebcbbd2d709",
+	},
+	{
+		msg: "q128_qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq",
+		u:   []string{"010476f6a060453c0b1ad0b628f3e57c23039ee16eea5e71bb87c3b5419b1255dc0e5883322e563b84a29543823c0e86", "0b1a912064fb0554b180e07af7e787f1f883a0470759c03c1b6509eb8ce980d1670305ae7b928226bb58fdc0a419f46e"},
+		x:   "15f68eaa693b95ccb85215dc65fa81038d69629f70aeee0d0f677cf22285e7bf58d7cb86eefe8f2e9bc3f8cb84fac488",
+		y:   "1807a1d50c29f430b8cafc4f8638dfeeadf51211e1602a5f184443076715f91bb90a48ba1e370edce6ae1062f5e6dd38",
+	},
+}
+
+var hashToG2Vectors = []hashToCurveVector{
+	{
+		msg: "",
+		u:   []string{"03dbc2cce174e91ba93cbb08f26b917f98194a2ea08d1cce75b2b9cc9f21689d80bd79b594a613d0a68eb807dfdc1cf8,05a2acec64114845711a54199ea339abd125ba38253b70a92c876df10598bd1986b739cad67961eb94f7076511b3b39a", "02f99798e8a5acdeed60d7e18e9120521ba1f47ec090984662846bc825de191b5b7641148c0dbc237726a334473eee94,145a81e418d4010cc027a68f14391b30074e89e60ee7a22f87217b2f6eb0c4b94c9115b436e6fa4607e95a98de30a435"},
+		x:   "0141ebfbdca40eb85b87142e130ab689c673cf60f1a3e98d69335266f30d9b8d4ac44c1038e9dcdd5393faf5c41fb78a,05cb8437535e20ecffaef7752baddf98034139c38452458baeefab379ba13dff5bf5dd71b72418717047f5b0f37da03d",
+		y:   "0503921d7f6a12805e72940b963c0cf3471c7b2a524950ca195d11062ee75ec076daf2d4bc358c4b190c0c98064fdd92,12424ac32561493f3fe3c260708a12b7c620e7be00099a974e259ddc7d1f6395c3c811cdd19f1e8dbf3e9ecfdcbab8d6",
+	},
+	{
+		msg: "abc",
+		u:   []string{"15f7c0aa8f6b296ab5ff9c2c7581ade64f4ee6f1bf18f55179ff44a2cf355fa53dd2a2158c5ecb17d7c52f63e7195771,01c8067bf4c0ba709aa8b9abc3d1cef589a4758e09ef53732d670fd8739a7274e111ba2fcaa71b3d33df2a3a0c8529dd", "187111d5e088b6b9acfdfad078c4dacf72dcd17ca17c82be35e79f8c372a693f60a033b461d81b025864a0ad051a06e4,08b852331c96ed983e497ebc6dee9b75e373d923b729194af8e72a051ea586f3538a6ebb1e80881a082fa2b24df9f566"},
+		x:   "02c2d18e033b960562aae3cab37a27ce00d80ccd5ba4b7fe0e7a210245129dbec7780ccc7954725f4168aff2787776e6,139cddbccdc5e91b9623efd38c49f81a6f83f175e80b06fc374de9eb4b41dfe4ca3a230ed250fbe3a2acf73a41177fd8",
+		y:   "1787327b68159716a37440985269cf584bcb1e621d3a7202be6ea05c4cfe244aeb197642555a0645fb87bf7466b2ba48,00aa65dae3c8d732d10ecd2c50f8a1baf3001578f71c694e03866e9f3d49ac1e1ce70dd94a733534f106d4cec0eddd16",
+	},
+	{
+		msg: "abcdef0123456789",
+		u:   []string{"0313d9325081b415bfd4e5364efaef392ecf69b087496973b229303e1816d2080971470f7da112c4eb43053130b785e1,062f84cb21ed89406890c051a0e8b9cf6c575cf6e8e18ecf63ba86826b0ae02548d83b483b79e48512b82a6c0686df8f", "1739123845406baa7be5c5dc74492051b6d42504de008c635f3535bb831d478a341420e67dcc7b46b2e8cba5379cca97,01897665d9cb5db16a27657760bbea7951f67ad68f8d55f7113f24ba6ddd82caef240a9bfa627972279974894701d975"},
+		x:   "121982811d2491fde9ba7ed31ef9ca474f0e1501297f68c298e9f4c0028add35aea8bb83d53c08cfc007c1e005723cd0,190d119345b94fbd15497bcba94ecf7db2cbfd1e1fe7da034d26cbba169fb3968288b3fafb265f9ebd380512a71c3f2c",
+		y:   "05571a0f8d3c08d094576981f4a3b8eda0a8e771fcdcc8ecceaf1356a6acf17574518acb506e435b639353c2e14827c8,0bb5e7572275c567462d91807de765611490205a941a5a6af3b1691bfe596c31225d3aabdf15faff860cb4ef17c7c3be",
+	},
+	{
+		msg: "q128_qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq",
+		u:   []string{"025820cefc7d06fd38de7d8e370e0da8a52498be9b53cba9927b2ef5c6de1e12e12f188bbc7bc923864883c57e49e253,034147b77ce337a52e5948f66db0bab47a8d038e712123bb381899b6ab5ad20f02805601e6104c29df18c254b8618c7b", "0930315cae1f9a6017c3f0c8f2314baa130e1cf13f6532bff0a8a1790cd70af918088c3db94bda214e896e1543629795,10c4df2cacf67ea3cb3108b00d4cbd0b3968031ebc8eac4b1ebcefe84d6b715fde66bef0219951ece29d1facc8a520ef"},
+		x:   "19a84dd7248a1066f737cc34502ee5555bd3c19f2ecdb3c7d9e24dc65d4e25e50d83f0f77105e955d78f4762d33c17da,0934aba516a52d8ae479939a91998299c76d39cc0c035cd18813bec433f587e2d7a4fef038260eef0cef4d02aae3eb91",
+		y:   "14f81cd421617428bc3b9fe25afbb751d934a00493524bc4e065635b0555084dd54679df1536101b2c979c0152d09192,09bcccfa036b4847c9950780733633f13619994394c23ff0b32fa6b795844f4a0673e20282d07bc69641cee04f5e5662",
+	},
+}
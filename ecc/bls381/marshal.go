@@ -0,0 +1,175 @@
+package bls381
+
+import (
+	"errors"
+	"math/big"
+
+	"scrypto/ecc/bls381/fp"
+)
+
+// Compressed encodings of G1 and G2 points as in the ZCash serialization
+// used by the IETF BLS signature draft: x big-endian (x.A1 || x.A0 in G2),
+// with three flags in the top bits of the first byte.
+
+const (
+	// SizeOfG1Compressed is the size of a compressed G1 point
+	SizeOfG1Compressed = 48
+	// SizeOfG2Compressed is the size of a compressed G2 point
+	SizeOfG2Compressed = 2 * SizeOfG1Compressed
+)
+
+const (
+	mCompressed = 0x80 // the encoding is compressed
+	mInfinity   = 0x40 // the point is the infinity
+	mLargest    = 0x20 // y is the largest of y and -y
+	mFlags      = mCompressed | mInfinity | mLargest
+)
+
+var (
+	ErrInvalidEncoding = errors.New("bls381: invalid point encoding")
+	ErrNotOnCurve      = errors.New("bls381: point not on curve")
+	ErrNotInSubGroup   = errors.New("bls381: point not in the subgroup of order r")
+)
+
+// isLargest reports whether y > -y as integers in [0, p)
+func isLargest(y *fp.Element) bool {
+	var neg fp.Element
+	var a, b big.Int
+	neg.Neg(y)
+	return y.ToBigIntRegular(&a).Cmp(neg.ToBigIntRegular(&b)) > 0
+}
+
+// isLargest reports whether z > -z, comparing A1 first then A0
+func (z *e2) isLargest() bool {
+	if z.A1.IsZero() {
+		return isLargest(&z.A0)
+	}
+	return isLargest(&z.A1)
+}
+
+// setCoordinate decodes a big-endian coordinate, it rejects values not below p
+func setCoordinate(z *fp.Element, buf []byte) bool {
+	v := new(big.Int).SetBytes(buf)
+	if v.Cmp(fp.ElementModulus()) >= 0 {
+		return false
+	}
+	z.SetBigInt(v)
+	return true
+}
+
+// infinityEncoding reports whether buf is the encoding of the infinity
+func infinityEncoding(buf []byte) bool {
+	if buf[0] != mCompressed|mInfinity {
+		return false
+	}
+	for _, b := range buf[1:] {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Bytes returns the compressed encoding of p in SizeOfG1Compressed bytes
+func (p *G1Affine) Bytes() []byte {
+	res := make([]byte, SizeOfG1Compressed)
+	if p.IsInfinity() {
+		res[0] = mCompressed | mInfinity
+		return res
+	}
+	copy(res, p.X.Bytes())
+	res[0] |= mCompressed
+	if isLargest(&p.Y) {
+		res[0] |= mLargest
+	}
+	return res
+}
+
+// SetBytes sets p to the point of the compressed encoding buf, it checks the
+// point is on the curve and in G1
+func (p *G1Affine) SetBytes(buf []byte) error {
+	if len(buf) != SizeOfG1Compressed || buf[0]&mCompressed == 0 {
+		return ErrInvalidEncoding
+	}
+	if buf[0]&mInfinity != 0 {
+		if !infinityEncoding(buf) {
+			return ErrInvalidEncoding
+		}
+		p.X.SetZero()
+		p.Y.SetZero()
+		return nil
+	}
+	x := append([]byte{}, buf...)
+	x[0] &^= mFlags
+	var a G1Affine
+	if !setCoordinate(&a.X, x) {
+		return ErrInvalidEncoding
+	}
+	curve := BLS381()
+	// y^2 = x^3 + 4
+	a.Y.Square(&a.X).MulAssign(&a.X).AddAssign(&curve.B)
+	if a.Y.Sqrt(&a.Y) == nil {
+		return ErrNotOnCurve
+	}
+	if isLargest(&a.Y) != (buf[0]&mLargest != 0) {
+		a.Y.Neg(&a.Y)
+	}
+	if !a.ToJacobian(new(G1Jac)).IsInSubGroup(curve) {
+		return ErrNotInSubGroup
+	}
+	*p = a
+	return nil
+}
+
+// Bytes returns the compressed encoding of p in SizeOfG2Compressed bytes
+func (p *G2Affine) Bytes() []byte {
+	res := make([]byte, SizeOfG2Compressed)
+	if p.IsInfinity() {
+		res[0] = mCompressed | mInfinity
+		return res
+	}
+	copy(res, p.X.A1.Bytes())
+	copy(res[SizeOfG1Compressed:], p.X.A0.Bytes())
+	res[0] |= mCompressed
+	if p.Y.isLargest() {
+		res[0] |= mLargest
+	}
+	return res
+}
+
+// SetBytes sets p to the point of the compressed encoding buf, it checks the
+// point is on the twist and in G2
+func (p *G2Affine) SetBytes(buf []byte) error {
+	if len(buf) != SizeOfG2Compressed || buf[0]&mCompressed == 0 {
+		return ErrInvalidEncoding
+	}
+	if buf[0]&mInfinity != 0 {
+		if !infinityEncoding(buf) {
+			return ErrInvalidEncoding
+		}
+		p.X.SetZero()
+		p.Y.SetZero()
+		return nil
+	}
+	x := append([]byte{}, buf...)
+	x[0] &^= mFlags
+	var a G2Affine
+	if !setCoordinate(&a.X.A1, x[:SizeOfG1Compressed]) || !setCoordinate(&a.X.A0, x[SizeOfG1Compressed:]) {
+		return ErrInvalidEncoding
+	}
+	// y^2 = x^3 + 4(u+1)
+	var bTwist e2
+	bTwist.SetString("4", "4")
+	a.Y.Square(&a.X).MulAssign(&a.X).AddAssign(&bTwist)
+	if a.Y.Sqrt(&a.Y) == nil {
+		return ErrNotOnCurve
+	}
+	if a.Y.isLargest() != (buf[0]&mLargest != 0) {
+		a.Y.Neg(&a.Y)
+	}
+	if !a.ToJacobian(new(G2Jac)).IsInSubGroup(BLS381()) {
+		return ErrNotInSubGroup
+	}
+	*p = a
+	return nil
+}
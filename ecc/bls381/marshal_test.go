@@ -0,0 +1,95 @@
+package bls381
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"scrypto/ecc/bls381/fp"
+	"scrypto/ecc/bls381/fr"
+)
+
+func TestG1Affine_Bytes(t *testing.T) {
+	curve := BLS381()
+	var g G1Affine
+	curve.G1Gen.ToAffineFromJac(&g)
+	expected := "97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb"
+	if hex.EncodeToString(g.Bytes()) != expected {
+		t.Error("encoding of the generator not match")
+	}
+	for i := 0; i < 10; i++ {
+		var k fr.Element
+		k.SetRandom().FromMont()
+		var p G1Affine
+		new(G1Jac).ScalarMulByGen(curve, k).ToAffineFromJac(&p)
+		var q G1Affine
+		if err := q.SetBytes(p.Bytes()); err != nil {
+			panic(err)
+		}
+		if !q.Equal(&p) {
+			t.Error("SetBytes(Bytes()) not match")
+		}
+	}
+	var inf, q G1Affine
+	if err := q.SetBytes(inf.Bytes()); err != nil || !q.IsInfinity() {
+		t.Error("infinity should round trip")
+	}
+	// a point of the curve out of G1
+	u := new(fp.Element).SetUint64(7)
+	initHashToCurveOnce()
+	p := mapToCurve1(u)
+	g1Isogeny(&p)
+	if err := q.SetBytes(p.Bytes()); err != ErrNotInSubGroup {
+		t.Error("point out of G1 should fail, got", err)
+	}
+	b := g.Bytes()
+	b[0] &^= mCompressed
+	if err := q.SetBytes(b); err != ErrInvalidEncoding {
+		t.Error("uncompressed flag should fail, got", err)
+	}
+	b = inf.Bytes()
+	b[47] = 1
+	if err := q.SetBytes(b); err != ErrInvalidEncoding {
+		t.Error("nonzero infinity should fail, got", err)
+	}
+}
+
+func TestG2Affine_Bytes(t *testing.T) {
+	curve := BLS381()
+	var g G2Affine
+	curve.G2Gen.ToAffineFromJac(&g)
+	expected := "93e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e" +
+		"024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8"
+	if hex.EncodeToString(g.Bytes()) != expected {
+		t.Error("encoding of the generator not match")
+	}
+	for i := 0; i < 10; i++ {
+		var k fr.Element
+		k.SetRandom().FromMont()
+		var p G2Affine
+		new(G2Jac).ScalarMulByGen(curve, k).ToAffineFromJac(&p)
+		var q G2Affine
+		if err := q.SetBytes(p.Bytes()); err != nil {
+			panic(err)
+		}
+		if !q.Equal(&p) {
+			t.Error("SetBytes(Bytes()) not match")
+		}
+	}
+	var inf, q G2Affine
+	if err := q.SetBytes(inf.Bytes()); err != nil || !q.IsInfinity() {
+		t.Error("infinity should round trip")
+	}
+	initHashToCurveOnce()
+	var u e2
+	u.SetString("7", "1")
+	p := mapToCurve2(&u)
+	g2Isogeny(&p)
+	if err := q.SetBytes(p.Bytes()); err != ErrNotInSubGroup {
+		t.Error("point out of G2 should fail, got", err)
+	}
+	b := g.Bytes()
+	b[0] |= 0x1f
+	if err := q.SetBytes(b); err != ErrInvalidEncoding {
+		t.Error("x not below p should fail, got", err)
+	}
+}
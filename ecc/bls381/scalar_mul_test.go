@@ -0,0 +1,31 @@
+package bls381
+
+import (
+	"testing"
+
+	"scrypto/ecc/bls381/fr"
+)
+
+func TestG1Jac_ScalarMulUint64(t *testing.T) {
+	curve := BLS381()
+	for _, k := range []uint64{0, 1, 2, 3, 0xd201000000010000, 1<<64 - 1} {
+		var p, q G1Jac
+		p.ScalarMulUint64(curve, &curve.G1Gen, k)
+		q.ScalarMul(curve, &curve.G1Gen, fr.Element{k})
+		if !p.Equal(&q) {
+			t.Errorf("[%d]g1 not match ScalarMul", k)
+		}
+	}
+}
+
+func TestG2Jac_ScalarMulUint64(t *testing.T) {
+	curve := BLS381()
+	for _, k := range []uint64{0, 1, 2, 3, 0xd201000000010000, 1<<64 - 1} {
+		var p, q G2Jac
+		p.ScalarMulUint64(curve, &curve.G2Gen, k)
+		q.ScalarMul(curve, &curve.G2Gen, fr.Element{k})
+		if !p.Equal(&q) {
+			t.Errorf("[%d]g2 not match ScalarMul", k)
+		}
+	}
+}
@@ -4,7 +4,8 @@ import (
 	"crypto/rand"
 	"math/big"
 	"scrypto/ecc/bls381"
-	"scrypto/ecc/bls381/fr"
+	"scrypto/ecc/internal/pool"
+	"sync"
 )
 
 var (
@@ -17,48 +18,40 @@ type G1 = bls381.G1Jac
 type G2 = bls381.G2Jac
 type GT = bls381.PairingResult
 
-const (
-	bits = 256
-)
+// G1MulBase returns [s]g1
+func G1MulBase(s *Scalar) *G1 {
+	return new(G1).ScalarMulByGen(BLSCurve, s.regular())
+}
 
-func G1ScalarBaseMult(a *big.Int) *G1 {
-	var b fr.Element
-	b.SetBigInt(a)
+// G1Mul returns [s]a
+func G1Mul(a *G1, s *Scalar) *G1 {
+	return new(G1).ScalarMul(BLSCurve, a, s.regular())
+}
 
-	return new(G1).ScalarMulByGen(BLSCurve, b)
+func G1ScalarBaseMult(a *big.Int) *G1 {
+	return G1MulBase(NewScalar(a))
 }
 
+// RandomG1 returns a uniform nonzero k and [k]g1
 func RandomG1() (k *big.Int, K *G1, err error) {
-	for {
-		k, err = rand.Prime(rand.Reader, bits)
-		if err != nil {
-			return nil, nil, err
-		}
-		if k.Sign() > 0 {
-			break
-		}
+	s, err := RandomNonZeroScalar(rand.Reader)
+	if err != nil {
+		return nil, nil, err
 	}
-	return k, G1ScalarBaseMult(k), nil
+	return s.BigInt(), G1MulBase(s), nil
 }
 
+// RandomG2 returns a uniform nonzero k and [k]g2
 func RandomG2() (k *big.Int, K *G2, err error) {
-	for {
-		k, err = rand.Prime(rand.Reader, bits)
-		if err != nil {
-			return nil, nil, err
-		}
-		if k.Sign() > 0 {
-			break
-		}
+	s, err := RandomNonZeroScalar(rand.Reader)
+	if err != nil {
+		return nil, nil, err
 	}
-	K = G2ScalarBaseMult(k)
-	return k, K, nil
+	return s.BigInt(), G2MulBase(s), nil
 }
 
 func G1ScalarMult(a *G1, b *big.Int) *G1 {
-	var c fr.Element
-	c.SetBigInt(b)
-	return new(G1).ScalarMul(BLSCurve, a, c)
+	return G1Mul(a, NewScalar(b))
 }
 
 func G1Add(a, b *G1) *G1 {
@@ -74,16 +67,22 @@ func G1Equal(a, b *G1) bool {
 	return a.Equal(b)
 }
 
+// G2MulBase returns [s]g2
+func G2MulBase(s *Scalar) *G2 {
+	return new(G2).ScalarMulByGen(BLSCurve, s.regular())
+}
+
+// G2Mul returns [s]a
+func G2Mul(a *G2, s *Scalar) *G2 {
+	return new(G2).ScalarMul(BLSCurve, a, s.regular())
+}
+
 func G2ScalarBaseMult(a *big.Int) *G2 {
-	var b fr.Element
-	b.SetBigInt(a)
-	return new(G2).ScalarMulByGen(BLSCurve, b)
+	return G2MulBase(NewScalar(a))
 }
 
 func G2ScalarMult(a *G2, b *big.Int) *G2 {
-	var c fr.Element
-	c.SetBigInt(b)
-	return new(G2).ScalarMul(BLSCurve, a, c)
+	return G2Mul(a, NewScalar(b))
 }
 
 func G2Add(a, b *G2) *G2 {
@@ -108,3 +107,32 @@ func BLSPair(a *G1, b *G2) *GT {
 	res = BLSCurve.FinalExponentiation(BLSCurve.MillerLoop(aA, bA, &res))
 	return &res
 }
+
+// PairingCheck reports whether e(a[0], b[0]) * ... * e(a[n-1], b[n-1]) is one,
+// the Miller loops run in parallel and share a single final exponentiation
+func PairingCheck(a []*G1, b []*G2) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	var lock sync.Mutex
+	var acc GT
+	acc.SetOne()
+	pool.Execute(0, len(a), func(start, end int) {
+		var prod, ml GT
+		prod.SetOne()
+		for i := start; i < end; i++ {
+			var aA bls381.G1Affine
+			var bA bls381.G2Affine
+			a[i].ToAffineFromJac(&aA)
+			b[i].ToAffineFromJac(&bA)
+			prod.Mul(&prod, BLSCurve.MillerLoop(aA, bA, &ml))
+		}
+		lock.Lock()
+		acc.Mul(&acc, &prod)
+		lock.Unlock()
+	}, false)
+	var one GT
+	one.SetOne()
+	res := BLSCurve.FinalExponentiation(&acc)
+	return res.Equal(&one)
+}
@@ -0,0 +1,171 @@
+package bls381Utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"scrypto/ecc/bls381/fr"
+)
+
+// ScalarSize is the size of an encoded scalar
+const ScalarSize = 32
+
+// Order is the order r of G1, G2 and GT
+var Order = fr.ElementModulus()
+
+// Scalar is an integer modulo r, stored as an fr.Element in Montgomery form
+type Scalar struct {
+	v fr.Element
+}
+
+// NewScalar returns k mod r
+func NewScalar(k *big.Int) *Scalar {
+	return new(Scalar).SetBigInt(k)
+}
+
+// RandomScalar draws a uniform scalar in [0, r) from random by rejection
+// sampling, crypto/rand if random is nil
+func RandomScalar(random io.Reader) (*Scalar, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	var buf [ScalarSize]byte
+	k := new(big.Int)
+	for {
+		if _, err := io.ReadFull(random, buf[:]); err != nil {
+			return nil, err
+		}
+		// r has 255 bits: keep 255 bits, so a draw is accepted with probability > 1/2
+		buf[0] &= 0x7f
+		k.SetBytes(buf[:])
+		if k.Cmp(Order) < 0 {
+			return NewScalar(k), nil
+		}
+	}
+}
+
+// RandomNonZeroScalar draws a uniform scalar in [1, r)
+func RandomNonZeroScalar(random io.Reader) (*Scalar, error) {
+	for {
+		s, err := RandomScalar(random)
+		if err != nil {
+			return nil, err
+		}
+		if !s.IsZero() {
+			return s, nil
+		}
+	}
+}
+
+func (s *Scalar) Set(a *Scalar) *Scalar {
+	s.v.Set(&a.v)
+	return s
+}
+
+func (s *Scalar) SetUint64(k uint64) *Scalar {
+	s.v.SetUint64(k)
+	return s
+}
+
+// SetBigInt sets s = k mod r
+func (s *Scalar) SetBigInt(k *big.Int) *Scalar {
+	s.v.SetBigInt(new(big.Int).Mod(k, Order))
+	return s
+}
+
+// BigInt returns s as an integer in [0, r)
+func (s *Scalar) BigInt() *big.Int {
+	return s.v.ToBigIntRegular(new(big.Int))
+}
+
+func (s *Scalar) Add(a, b *Scalar) *Scalar {
+	s.v.Add(&a.v, &b.v)
+	return s
+}
+
+func (s *Scalar) Sub(a, b *Scalar) *Scalar {
+	s.v.Sub(&a.v, &b.v)
+	return s
+}
+
+func (s *Scalar) Mul(a, b *Scalar) *Scalar {
+	s.v.Mul(&a.v, &b.v)
+	return s
+}
+
+func (s *Scalar) Neg(a *Scalar) *Scalar {
+	s.v.Neg(&a.v)
+	return s
+}
+
+// Inverse sets s = a^{-1}, s = 0 if a = 0
+func (s *Scalar) Inverse(a *Scalar) *Scalar {
+	s.v.Inverse(&a.v)
+	return s
+}
+
+func (s *Scalar) Equal(a *Scalar) bool {
+	return s.v.Equal(&a.v)
+}
+
+func (s *Scalar) IsZero() bool {
+	return s.v.IsZero()
+}
+
+// Element returns s as an fr.Element in Montgomery form
+func (s *Scalar) Element() fr.Element {
+	return s.v
+}
+
+// regular returns the limbs of s out of Montgomery form, as ScalarMul and
+// ScalarMulByGen of bls381 expect them
+func (s *Scalar) regular() fr.Element {
+	v := s.v
+	v.FromMont()
+	return v
+}
+
+// Bytes encodes s in ScalarSize big-endian bytes
+func (s *Scalar) Bytes() []byte {
+	res := make([]byte, ScalarSize)
+	return s.BigInt().FillBytes(res)
+}
+
+// SetBytes decodes ScalarSize big-endian bytes, it rejects values not below r
+func (s *Scalar) SetBytes(data []byte) (*Scalar, error) {
+	if len(data) != ScalarSize {
+		return nil, errors.New("scalar size not match")
+	}
+	k := new(big.Int).SetBytes(data)
+	if k.Cmp(Order) >= 0 {
+		return nil, errors.New("scalar not below the group order")
+	}
+	return s.SetBigInt(k), nil
+}
+
+func (s *Scalar) String() string {
+	return s.BigInt().String()
+}
+
+// serialize scalar
+func (s *Scalar) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(s.Bytes()))
+}
+
+// deserialize scalar
+func (s *Scalar) UnmarshalJSON(data []byte) error {
+	var str string
+	err := json.Unmarshal(data, &str)
+	if err != nil {
+		return err
+	}
+	b, err := hex.DecodeString(str)
+	if err != nil {
+		return err
+	}
+	_, err = s.SetBytes(b)
+	return err
+}
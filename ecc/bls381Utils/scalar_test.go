@@ -0,0 +1,71 @@
+package bls381Utils
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestScalar_Mul(t *testing.T) {
+	// [2]g1 = g1 + g1: scalars are not taken in Montgomery form
+	two := G1ScalarBaseMult(big.NewInt(2))
+	if !two.Equal(G1Add(&BaseG1, &BaseG1)) {
+		t.Error("[2]g1 not match g1 + g1")
+	}
+	if !G1ScalarMult(&BaseG1, new(big.Int).Sub(Order, big.NewInt(1))).Equal(G1Neg(&BaseG1)) {
+		t.Error("[r-1]g1 not match -g1")
+	}
+	a, _ := RandomScalar(rand.Reader)
+	b, _ := RandomScalar(rand.Reader)
+	ab := new(Scalar).Mul(a, b)
+	if !G1Mul(G1MulBase(a), b).Equal(G1MulBase(ab)) {
+		t.Error("[a][b]g1 not match [ab]g1")
+	}
+	if !G2Mul(G2MulBase(a), b).Equal(G2MulBase(ab)) {
+		t.Error("[a][b]g2 not match [ab]g2")
+	}
+	sum := new(Scalar).Add(a, b)
+	if !G1Add(G1MulBase(a), G1MulBase(b)).Equal(G1MulBase(sum)) {
+		t.Error("[a]g1 + [b]g1 not match [a+b]g1")
+	}
+	// e([a]g1, [b]g2) = e([ab]g1, g2)
+	lhs := BLSPair(G1MulBase(a), G2MulBase(b))
+	rhs := BLSPair(G1MulBase(ab), &BaseG2)
+	if !lhs.Equal(rhs) {
+		t.Error("pairing not bilinear")
+	}
+	// the big.Int adapters agree
+	if !G1ScalarBaseMult(a.BigInt()).Equal(G1MulBase(a)) {
+		t.Error("big.Int adapter not match")
+	}
+}
+
+func TestScalar_Bytes(t *testing.T) {
+	a, err := RandomNonZeroScalar(nil)
+	if err != nil {
+		panic(err)
+	}
+	b, err := new(Scalar).SetBytes(a.Bytes())
+	if err != nil || !a.Equal(b) {
+		t.Error("bytes round trip not match")
+	}
+	if _, err := new(Scalar).SetBytes(Order.Bytes()); err == nil {
+		t.Error("scalar equal to r accepted")
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		panic(err)
+	}
+	c := new(Scalar)
+	if err := json.Unmarshal(data, c); err != nil || !a.Equal(c) {
+		t.Error("json round trip not match")
+	}
+	inv := new(Scalar).Inverse(a)
+	if one := new(Scalar).Mul(a, inv); !one.Equal(new(Scalar).SetUint64(1)) {
+		t.Error("inverse not match")
+	}
+	if NewScalar(new(big.Int).Add(Order, big.NewInt(5))).BigInt().Int64() != 5 {
+		t.Error("NewScalar not reduced")
+	}
+}
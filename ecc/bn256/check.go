@@ -0,0 +1,51 @@
+package bn256
+
+import (
+	"sync"
+
+	"scrypto/ecc/bn256/fp"
+	"scrypto/ecc/bn256/fr"
+)
+
+// bTwist = 3 / (u + 9), the constant of the twist y^2 = x^3 + bTwist
+var bTwist e2
+var bTwistOnce sync.Once
+
+func initBTwist() {
+	var xi e2
+	xi.SetString("9", "1")
+	bTwist.Inverse(&xi)
+	var three e2
+	three.SetString("3", "0")
+	bTwist.Mul(&bTwist, &three)
+}
+
+// IsOnCurve reports whether p is on y^2 = x^3 + 3, p is not the infinity
+func (p *G1Affine) IsOnCurve(curve *Curve) bool {
+	var left, right fp.Element
+	left.Square(&p.Y)
+	right.Square(&p.X).Mul(&right, &p.X).Add(&right, &curve.B)
+	return left.Equal(&right)
+}
+
+// IsOnCurve reports whether p is on the twist y^2 = x^3 + 3/(u+9), p is not
+// the infinity
+func (p *G2Affine) IsOnCurve() bool {
+	bTwistOnce.Do(initBTwist)
+	var left, right e2
+	left.Square(&p.Y)
+	right.Square(&p.X).Mul(&right, &p.X).Add(&right, &bTwist)
+	return left.Equal(&right)
+}
+
+// IsInSubGroup reports whether [r]p is the infinity. Every point of the curve
+// of G1 is in G1, the twist has a cofactor so G2 points need this check.
+func (p *G2Jac) IsInSubGroup(curve *Curve) bool {
+	// r = (r-1) + 1, as a scalar below r
+	var rMinusOne fr.Element
+	rMinusOne.SetOne().Neg(&rMinusOne).FromMont()
+	var q G2Jac
+	q.ScalarMul(curve, p, rMinusOne)
+	q.Add(curve, p)
+	return q.Z.IsZero()
+}
@@ -0,0 +1,292 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"scrypto/ecc/bn256/fp"
+	"scrypto/ecc/bn256/fr"
+
+	eth "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	xcrypto "golang.org/x/crypto/bn256"
+)
+
+// Differential tests of the groups and the pairing. go-ethereum implements
+// the same curve and the same tower of extensions, so the points and the
+// pairings are compared byte for byte through the 64 bytes x || y encoding
+// of G1, the 128 bytes x.imag || x.real || y.imag || y.real encoding of G2
+// and the 384 bytes encoding of GT. x/crypto implements another BN curve:
+// the same scenario runs on both and they have to agree on every equality.
+
+// randomScalar returns a random k in [0, r)
+func randomScalar() *big.Int {
+	k, err := rand.Int(rand.Reader, fr.ElementModulus())
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+// regular returns k mod r out of Montgomery form, as scalar multiplications expect it
+func regular(k *big.Int) fr.Element {
+	var s fr.Element
+	s.SetBigInt(k).FromMont()
+	return s
+}
+
+func g1Bytes(p *G1Jac) []byte {
+	var a G1Affine
+	p.ToAffineFromJac(&a)
+	return append(a.X.Bytes(), a.Y.Bytes()...)
+}
+
+func g2Bytes(p *G2Jac) []byte {
+	var a G2Affine
+	p.ToAffineFromJac(&a)
+	res := append(a.X.A1.Bytes(), a.X.A0.Bytes()...)
+	res = append(res, a.Y.A1.Bytes()...)
+	return append(res, a.Y.A0.Bytes()...)
+}
+
+func gtBytes(e *PairingResult) []byte {
+	var res []byte
+	for _, c := range []*fp.Element{
+		&e.C1.B2.A1, &e.C1.B2.A0, &e.C1.B1.A1, &e.C1.B1.A0, &e.C1.B0.A1, &e.C1.B0.A0,
+		&e.C0.B2.A1, &e.C0.B2.A0, &e.C0.B1.A1, &e.C0.B1.A0, &e.C0.B0.A1, &e.C0.B0.A0,
+	} {
+		res = append(res, c.Bytes()...)
+	}
+	return res
+}
+
+// go-ethereum's Add doubles wrongly when the receiver is also the first
+// operand, so the reference sums below never call e.Add(e, e)
+
+// toEthG1 converts p to go-ethereum, the identity is all zero in both encodings
+func toEthG1(p *G1Jac) *eth.G1 {
+	res := new(eth.G1)
+	if _, err := res.Unmarshal(g1Bytes(p)); err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func toEthG2(p *G2Jac) *eth.G2 {
+	res := new(eth.G2)
+	if _, err := res.Unmarshal(g2Bytes(p)); err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func pair(curve *Curve, p *G1Jac, q *G2Jac) PairingResult {
+	var a G1Affine
+	var b G2Affine
+	var ml PairingResult
+	p.ToAffineFromJac(&a)
+	q.ToAffineFromJac(&b)
+	return curve.FinalExponentiation(curve.MillerLoop(a, b, &ml))
+}
+
+// checkG1 compares the G1 operations on [a]g and [b]g with go-ethereum
+func checkG1(t *testing.T, curve *Curve, a, b, k *big.Int) {
+	p := new(G1Jac).ScalarMulByGen(curve, regular(a))
+	q := new(G1Jac).ScalarMulByGen(curve, regular(b))
+	ep, eq := toEthG1(p), toEthG1(q)
+	gen := toEthG1(&curve.G1Gen)
+	if !bytes.Equal(g1Bytes(p), new(eth.G1).ScalarMult(gen, a).Marshal()) {
+		t.Errorf("G1 ScalarMulByGen(%v) not match go-ethereum", a)
+	}
+	if !bytes.Equal(g1Bytes(new(G1Jac).ScalarMul(curve, p, regular(k))), new(eth.G1).ScalarMult(ep, k).Marshal()) {
+		t.Errorf("G1 ScalarMul(%v) not match go-ethereum", k)
+	}
+	if !bytes.Equal(g1Bytes(p.Clone().Add(curve, q)), new(eth.G1).Add(ep, eq).Marshal()) {
+		t.Error("G1 Add not match go-ethereum")
+	}
+	if !bytes.Equal(g1Bytes(p.Clone().Add(curve, p)), new(eth.G1).Add(ep, ep).Marshal()) {
+		t.Error("G1 Add of a point to itself not match go-ethereum")
+	}
+	if !bytes.Equal(g1Bytes(p.Clone().Double()), new(eth.G1).Add(ep, ep).Marshal()) {
+		t.Error("G1 Double not match go-ethereum")
+	}
+	var a1 G1Affine
+	q.ToAffineFromJac(&a1)
+	if !bytes.Equal(g1Bytes(p.Clone().AddMixed(&a1)), new(eth.G1).Add(ep, eq).Marshal()) {
+		t.Error("G1 AddMixed not match go-ethereum")
+	}
+	if !bytes.Equal(g1Bytes(p.Clone().Sub(curve, *q)), new(eth.G1).Add(ep, new(eth.G1).Neg(eq)).Marshal()) {
+		t.Error("G1 Sub not match go-ethereum")
+	}
+	if !p.Clone().Sub(curve, *p).Z.IsZero() {
+		t.Error("G1 p - p not the infinity")
+	}
+}
+
+// checkG2 compares the G2 operations on [a]g and [b]g with go-ethereum
+func checkG2(t *testing.T, curve *Curve, a, b, k *big.Int) {
+	p := new(G2Jac).ScalarMulByGen(curve, regular(a))
+	q := new(G2Jac).ScalarMulByGen(curve, regular(b))
+	ep, eq := toEthG2(p), toEthG2(q)
+	gen := toEthG2(&curve.G2Gen)
+	if !bytes.Equal(g2Bytes(p), new(eth.G2).ScalarMult(gen, a).Marshal()) {
+		t.Errorf("G2 ScalarMulByGen(%v) not match go-ethereum", a)
+	}
+	if !bytes.Equal(g2Bytes(new(G2Jac).ScalarMul(curve, p, regular(k))), new(eth.G2).ScalarMult(ep, k).Marshal()) {
+		t.Errorf("G2 ScalarMul(%v) not match go-ethereum", k)
+	}
+	if !bytes.Equal(g2Bytes(p.Clone().Add(curve, q)), new(eth.G2).Add(ep, eq).Marshal()) {
+		t.Error("G2 Add not match go-ethereum")
+	}
+	if !bytes.Equal(g2Bytes(p.Clone().Add(curve, p)), new(eth.G2).Add(ep, ep).Marshal()) {
+		t.Error("G2 Add of a point to itself not match go-ethereum")
+	}
+	if !bytes.Equal(g2Bytes(p.Clone().Double()), new(eth.G2).Add(ep, ep).Marshal()) {
+		t.Error("G2 Double not match go-ethereum")
+	}
+	var a2 G2Affine
+	q.ToAffineFromJac(&a2)
+	if !bytes.Equal(g2Bytes(p.Clone().AddMixed(&a2)), new(eth.G2).Add(ep, eq).Marshal()) {
+		t.Error("G2 AddMixed not match go-ethereum")
+	}
+	if !bytes.Equal(g2Bytes(p.Clone().Sub(curve, *q)), new(eth.G2).Add(ep, new(eth.G2).Neg(eq)).Marshal()) {
+		t.Error("G2 Sub not match go-ethereum")
+	}
+	if !p.IsInSubGroup(curve) {
+		t.Error("G2 point not in the subgroup")
+	}
+}
+
+// checkPair compares e([a]g1, [b]g2) with go-ethereum
+func checkPair(t *testing.T, curve *Curve, a, b *big.Int) {
+	p := new(G1Jac).ScalarMulByGen(curve, regular(a))
+	q := new(G2Jac).ScalarMulByGen(curve, regular(b))
+	e := pair(curve, p, q)
+	if !bytes.Equal(gtBytes(&e), eth.Pair(toEthG1(p), toEthG2(q)).Marshal()) {
+		t.Errorf("pairing of [%v]g1, [%v]g2 not match go-ethereum", a, b)
+	}
+}
+
+func TestG1Jac_Differential(t *testing.T) {
+	curve := BN256()
+	r := fr.ElementModulus()
+	edges := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2), new(big.Int).Sub(r, big.NewInt(1))}
+	for _, a := range edges {
+		checkG1(t, curve, a, big.NewInt(1), randomScalar())
+		checkG1(t, curve, randomScalar(), a, a)
+	}
+	for i := 0; i < 32; i++ {
+		checkG1(t, curve, randomScalar(), randomScalar(), randomScalar())
+	}
+}
+
+func TestG2Jac_Differential(t *testing.T) {
+	curve := BN256()
+	r := fr.ElementModulus()
+	edges := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2), new(big.Int).Sub(r, big.NewInt(1))}
+	for _, a := range edges {
+		checkG2(t, curve, a, big.NewInt(1), randomScalar())
+		checkG2(t, curve, randomScalar(), a, a)
+	}
+	for i := 0; i < 16; i++ {
+		checkG2(t, curve, randomScalar(), randomScalar(), randomScalar())
+	}
+}
+
+func TestMultiExp_Differential(t *testing.T) {
+	curve := BN256()
+	// the windowed algorithm up to 50 points, the bucket one over
+	for _, n := range []int{1, 7, 50, 51, 300} {
+		points1 := make([]G1Affine, n)
+		points2 := make([]G2Affine, n)
+		scalars := make([]fr.Element, n)
+		sum1, sum2 := new(eth.G1).ScalarBaseMult(new(big.Int)), new(eth.G2).ScalarBaseMult(new(big.Int))
+		for i := 0; i < n; i++ {
+			k, s := randomScalar(), randomScalar()
+			if i%5 == 1 {
+				// repeated points land in the same buckets
+				k = big.NewInt(3)
+			}
+			p := new(G1Jac).ScalarMulByGen(curve, regular(k))
+			q := new(G2Jac).ScalarMulByGen(curve, regular(k))
+			p.ToAffineFromJac(&points1[i])
+			q.ToAffineFromJac(&points2[i])
+			scalars[i] = regular(s)
+			sum1 = new(eth.G1).Add(sum1, new(eth.G1).ScalarMult(toEthG1(p), s))
+			sum2 = new(eth.G2).Add(sum2, new(eth.G2).ScalarMult(toEthG2(q), s))
+		}
+		var res1 G1Jac
+		var res2 G2Jac
+		res1.Set(&curve.g1Infinity)
+		res2.Set(&curve.g2Infinity)
+		got1 := <-res1.MultiExp(curve, points1, scalars)
+		got2 := <-res2.MultiExp(curve, points2, scalars)
+		if !bytes.Equal(g1Bytes(&got1), sum1.Marshal()) {
+			t.Errorf("G1 MultiExp of %d points not match go-ethereum", n)
+		}
+		if !bytes.Equal(g2Bytes(&got2), sum2.Marshal()) {
+			t.Errorf("G2 MultiExp of %d points not match go-ethereum", n)
+		}
+	}
+}
+
+func TestPairing_Differential(t *testing.T) {
+	curve := BN256()
+	checkPair(t, curve, big.NewInt(1), big.NewInt(1))
+	checkPair(t, curve, big.NewInt(0), randomScalar())
+	checkPair(t, curve, randomScalar(), big.NewInt(0))
+	for i := 0; i < 8; i++ {
+		checkPair(t, curve, randomScalar(), randomScalar())
+	}
+}
+
+// observations runs the scenario on the scalars with the operations of one
+// implementation: g1(k), g2(k) and pair(a, b) encode [k]g1, [k]g2 and
+// e([a]g1, [b]g2)
+func observations(ks []*big.Int, g1, g2 func(k *big.Int) []byte, pair func(a, b *big.Int) []byte) []bool {
+	var res []bool
+	eq := func(a, b []byte) {
+		res = append(res, bytes.Equal(a, b))
+	}
+	for i := 0; i+1 < len(ks); i++ {
+		a, b := ks[i], ks[i+1]
+		ab := new(big.Int).Mul(a, b)
+		eq(g1(a), g1(b))
+		eq(g2(a), g2(b))
+		eq(pair(a, b), pair(ab, big.NewInt(1)))
+		eq(pair(a, b), pair(b, a))
+		eq(pair(a, b), pair(a, big.NewInt(1)))
+	}
+	return res
+}
+
+func TestXCrypto_Differential(t *testing.T) {
+	curve := BN256()
+	// scalars below both group orders, with a repeat so some equalities hold
+	ks := make([]*big.Int, 6)
+	for i := range ks {
+		ks[i] = randomScalar()
+	}
+	ks[3] = ks[2]
+	ks[5] = big.NewInt(1)
+	native := observations(ks,
+		func(k *big.Int) []byte { return g1Bytes(new(G1Jac).ScalarMulByGen(curve, regular(k))) },
+		func(k *big.Int) []byte { return g2Bytes(new(G2Jac).ScalarMulByGen(curve, regular(k))) },
+		func(a, b *big.Int) []byte {
+			e := pair(curve, new(G1Jac).ScalarMulByGen(curve, regular(a)), new(G2Jac).ScalarMulByGen(curve, regular(b)))
+			return gtBytes(&e)
+		})
+	// the products ab are not reduced, each side reduces them by its own order
+	other := observations(ks,
+		func(k *big.Int) []byte { return new(xcrypto.G1).ScalarBaseMult(k).Marshal() },
+		func(k *big.Int) []byte { return new(xcrypto.G2).ScalarBaseMult(k).Marshal() },
+		func(a, b *big.Int) []byte {
+			return xcrypto.Pair(new(xcrypto.G1).ScalarBaseMult(a), new(xcrypto.G2).ScalarBaseMult(b)).Marshal()
+		})
+	for i := range native {
+		if native[i] != other[i] {
+			t.Errorf("observation %d: bn256 %v, x/crypto %v", i, native[i], other[i])
+		}
+	}
+}
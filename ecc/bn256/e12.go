@@ -21,7 +21,7 @@ package bn256
 import (
 	"math/bits"
 
-	"github.com/consensys/gurvy/bn256/fp"
+	"scrypto/ecc/bn256/fp"
 )
 
 // e12 is a degree-two finite field extension of fp6:
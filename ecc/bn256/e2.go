@@ -19,7 +19,7 @@ package bn256
 // Code generated by internal/fp2 DO NOT EDIT
 
 import (
-	"github.com/consensys/gurvy/bn256/fp"
+	"scrypto/ecc/bn256/fp"
 )
 
 // e2 is a degree-two finite field extension of fp.Element:
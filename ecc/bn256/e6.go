@@ -16,7 +16,7 @@
 
 package bn256
 
-import "github.com/consensys/gurvy/bn256/fp"
+import "scrypto/ecc/bn256/fp"
 
 // Code generated by internal/fp6 DO NOT EDIT
 
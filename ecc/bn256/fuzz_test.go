@@ -0,0 +1,75 @@
+package bn256
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"scrypto/ecc/bn256/fr"
+
+	eth "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+// fuzz targets of the differential tests, run one with
+// go test -fuzz=FuzzG1Jac ./ecc/bn256
+
+func addSeeds(f *testing.F) {
+	r := fr.ElementModulus().Bytes()
+	f.Add([]byte{}, []byte{1}, []byte{2})
+	f.Add([]byte{1}, []byte{1}, []byte{0})
+	f.Add(r, []byte{3}, r)
+	f.Add(bytes.Repeat([]byte{0xff}, 32), []byte{7}, bytes.Repeat([]byte{0xff}, 40))
+}
+
+func FuzzG1Jac(f *testing.F) {
+	addSeeds(f)
+	curve := BN256()
+	f.Fuzz(func(t *testing.T, a, b, k []byte) {
+		checkG1(t, curve, new(big.Int).SetBytes(a), new(big.Int).SetBytes(b), new(big.Int).SetBytes(k))
+	})
+}
+
+func FuzzG2Jac(f *testing.F) {
+	addSeeds(f)
+	curve := BN256()
+	f.Fuzz(func(t *testing.T, a, b, k []byte) {
+		checkG2(t, curve, new(big.Int).SetBytes(a), new(big.Int).SetBytes(b), new(big.Int).SetBytes(k))
+	})
+}
+
+func FuzzPairing(f *testing.F) {
+	addSeeds(f)
+	curve := BN256()
+	f.Fuzz(func(t *testing.T, a, b, _ []byte) {
+		checkPair(t, curve, new(big.Int).SetBytes(a), new(big.Int).SetBytes(b))
+	})
+}
+
+// FuzzMultiExp reads the scalars of [i+1]g1, i < n, 8 bytes at a time
+func FuzzMultiExp(f *testing.F) {
+	f.Add([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9})
+	f.Add(bytes.Repeat([]byte{0xff}, 8*60))
+	curve := BN256()
+	f.Fuzz(func(t *testing.T, data []byte) {
+		n := len(data) / 8
+		if n == 0 {
+			return
+		}
+		points := make([]G1Affine, n)
+		scalars := make([]fr.Element, n)
+		sum := new(eth.G1).ScalarBaseMult(new(big.Int))
+		for i := 0; i < n; i++ {
+			s := new(big.Int).SetBytes(data[8*i : 8*i+8])
+			p := new(G1Jac).ScalarMulByGen(curve, regular(big.NewInt(int64(i+1))))
+			p.ToAffineFromJac(&points[i])
+			scalars[i] = regular(s)
+			sum = new(eth.G1).Add(sum, new(eth.G1).ScalarMult(toEthG1(p), s))
+		}
+		var res G1Jac
+		res.Set(&curve.g1Infinity)
+		got := <-res.MultiExp(curve, points, scalars)
+		if !bytes.Equal(g1Bytes(&got), sum.Marshal()) {
+			t.Errorf("G1 MultiExp of %d points not match go-ethereum", n)
+		}
+	})
+}
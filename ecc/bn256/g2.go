@@ -40,9 +40,9 @@ import (
 	"runtime"
 	"sync"
 
-	"github.com/consensys/gurvy/bn256/fr"
-	"github.com/consensys/gurvy/utils/debug"
-	"github.com/consensys/gurvy/utils/parallel"
+	"scrypto/ecc/bn256/fr"
+	"scrypto/ecc/internal/debug"
+	"scrypto/ecc/internal/pool"
 )
 
 // G2Jac is a point with e2 coordinates
@@ -517,7 +517,7 @@ func (p *G2Jac) ScalarMulByGen(curve *Curve, scalar fr.Element) *G2Jac {
 // uses all availables runtime.NumCPU()
 func (p *G2Jac) WindowedMultiExp(curve *Curve, points []G2Jac, scalars []fr.Element) *G2Jac {
 	var lock sync.Mutex
-	parallel.Execute(0, len(points), func(start, end int) {
+	pool.Execute(0, len(points), func(start, end int) {
 		var t G2Jac
 		t.multiExp(curve, points[start:end], scalars[start:end])
 		lock.Lock()
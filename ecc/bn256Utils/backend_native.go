@@ -0,0 +1,232 @@
+//go:build bn256_native
+// +build bn256_native
+
+package bn256Utils
+
+import (
+	"math/big"
+	"strings"
+
+	"scrypto/ecc/bn256"
+	"scrypto/ecc/bn256/fp"
+	"scrypto/ecc/bn256/fr"
+)
+
+// Backend names the implementation of the groups
+const Backend = "scrypto/ecc/bn256"
+
+var (
+	// Order is the order of G1, G2 and GT
+	Order = fr.ElementModulus()
+	// P is the modulus of the base field, the curve is y^2 = x^3 + 3
+	P = fp.ElementModulus()
+)
+
+// scalar returns k mod Order out of Montgomery form, as the scalar
+// multiplications of bn256 expect it
+func scalar(k *big.Int) fr.Element {
+	var s fr.Element
+	s.SetBigInt(k).FromMont()
+	return s
+}
+
+// G1 is an element of the group G1, the zero value is the identity
+type G1 struct {
+	p bn256.G1Jac
+}
+
+func (e *G1) String() string {
+	var a bn256.G1Affine
+	e.p.ToAffineFromJac(&a)
+	return "bn256.G1(" + a.X.String() + ", " + a.Y.String() + ")"
+}
+
+// ScalarBaseMult sets e to g*k where g is the generator of the group and
+// then returns e
+func (e *G1) ScalarBaseMult(k *big.Int) *G1 {
+	e.p.ScalarMulByGen(curve, scalar(k))
+	return e
+}
+
+// ScalarMult sets e to a*k and then returns e
+func (e *G1) ScalarMult(a *G1, k *big.Int) *G1 {
+	e.p.ScalarMul(curve, &a.p, scalar(k))
+	return e
+}
+
+// Add sets e to a+b and then returns e
+func (e *G1) Add(a, b *G1) *G1 {
+	t := b.p
+	e.p.Set(&a.p)
+	e.p.Add(curve, &t)
+	return e
+}
+
+// Neg sets e to -a and then returns e
+func (e *G1) Neg(a *G1) *G1 {
+	e.p.Neg(&a.p)
+	return e
+}
+
+// Marshal converts e to 64 bytes x || y, the identity is all zero
+func (e *G1) Marshal() []byte {
+	return MarshalEthG1(&e.p)
+}
+
+// Unmarshal sets e to the result of converting the output of Marshal back
+// into a group element and then returns e, it checks the point is on the curve
+func (e *G1) Unmarshal(m []byte) (*G1, bool) {
+	p, err := UnmarshalEthG1(m)
+	if err != nil {
+		return nil, false
+	}
+	e.p = *p
+	return e, true
+}
+
+// G2 is an element of the group G2, the zero value is the identity
+type G2 struct {
+	p bn256.G2Jac
+}
+
+func (e *G2) String() string {
+	var a bn256.G2Affine
+	e.p.ToAffineFromJac(&a)
+	return "bn256.G2((" + a.X.A1.String() + ", " + a.X.A0.String() + "), (" +
+		a.Y.A1.String() + ", " + a.Y.A0.String() + "))"
+}
+
+// ScalarBaseMult sets e to g*k where g is the generator of the group and
+// then returns e
+func (e *G2) ScalarBaseMult(k *big.Int) *G2 {
+	e.p.ScalarMulByGen(curve, scalar(k))
+	return e
+}
+
+// ScalarMult sets e to a*k and then returns e
+func (e *G2) ScalarMult(a *G2, k *big.Int) *G2 {
+	e.p.ScalarMul(curve, &a.p, scalar(k))
+	return e
+}
+
+// Add sets e to a+b and then returns e
+func (e *G2) Add(a, b *G2) *G2 {
+	t := b.p
+	e.p.Set(&a.p)
+	e.p.Add(curve, &t)
+	return e
+}
+
+// Marshal converts e to 128 bytes x.imag || x.real || y.imag || y.real,
+// the identity is all zero
+func (e *G2) Marshal() []byte {
+	return MarshalEthG2(&e.p)
+}
+
+// Unmarshal sets e to the result of converting the output of Marshal back
+// into a group element and then returns e, it checks the point is on the
+// twist and in the subgroup of order Order
+func (e *G2) Unmarshal(m []byte) (*G2, bool) {
+	p, err := UnmarshalEthG2(m)
+	if err != nil {
+		return nil, false
+	}
+	e.p = *p
+	return e, true
+}
+
+// G2Neg returns -a
+func G2Neg(a *G2) *G2 {
+	res := new(G2)
+	res.p.Neg(&a.p)
+	return res
+}
+
+// GT is an element of the target group, the result of Pair
+type GT struct {
+	v bn256.PairingResult
+}
+
+func (e *GT) String() string {
+	s := make([]string, 0, 12)
+	for _, c := range e.coefficients() {
+		s = append(s, c.String())
+	}
+	return "bn256.GT(" + strings.Join(s, ", ") + ")"
+}
+
+// ScalarMult sets e to a^k and then returns e
+func (e *GT) ScalarMult(a *GT, k *big.Int) *GT {
+	exp := new(big.Int).Mod(k, Order)
+	var res bn256.PairingResult
+	base := a.v
+	res.SetOne()
+	for i := exp.BitLen() - 1; i >= 0; i-- {
+		res.Square(&res)
+		if exp.Bit(i) == 1 {
+			res.Mul(&res, &base)
+		}
+	}
+	e.v = res
+	return e
+}
+
+// Add sets e to a*b, GT is written additively as in x/crypto/bn256, and
+// then returns e
+func (e *GT) Add(a, b *GT) *GT {
+	e.v.Mul(&a.v, &b.v)
+	return e
+}
+
+// Neg sets e to a^{-1}, the conjugate of a unitary element, and then returns e
+func (e *GT) Neg(a *GT) *GT {
+	e.v.Conjugate(&a.v)
+	return e
+}
+
+// coefficients returns the 12 coefficients of e in Fp, highest power first
+// and imaginary part first, the order of Marshal
+func (e *GT) coefficients() []*fp.Element {
+	v := &e.v
+	return []*fp.Element{
+		&v.C1.B2.A1, &v.C1.B2.A0, &v.C1.B1.A1, &v.C1.B1.A0, &v.C1.B0.A1, &v.C1.B0.A0,
+		&v.C0.B2.A1, &v.C0.B2.A0, &v.C0.B1.A1, &v.C0.B1.A0, &v.C0.B0.A1, &v.C0.B0.A0,
+	}
+}
+
+// Marshal converts e to 384 bytes
+func (e *GT) Marshal() []byte {
+	res := make([]byte, 12*numBytes)
+	for i, c := range e.coefficients() {
+		copy(res[i*numBytes:], c.Bytes())
+	}
+	return res
+}
+
+// Unmarshal sets e to the result of converting the output of Marshal back
+// into a group element and then returns e
+func (e *GT) Unmarshal(m []byte) (*GT, bool) {
+	if len(m) != 12*numBytes {
+		return nil, false
+	}
+	var res GT
+	for i, c := range res.coefficients() {
+		if !setCoordinate(c, m[i*numBytes:(i+1)*numBytes]) {
+			return nil, false
+		}
+	}
+	e.v = res.v
+	return e, true
+}
+
+// Pair returns e(g1, g2)
+func Pair(g1 *G1, g2 *G2) *GT {
+	var a bn256.G1Affine
+	var b bn256.G2Affine
+	g1.p.ToAffineFromJac(&a)
+	g2.p.ToAffineFromJac(&b)
+	var ml bn256.PairingResult
+	res := new(GT)
+	res.v = curve.FinalExponentiation(curve.MillerLoop(a, b, &ml))
+	return res
+}
@@ -0,0 +1,36 @@
+//go:build !bn256_native
+// +build !bn256_native
+
+package bn256Utils
+
+import (
+	"math/big"
+
+	"golang.org/x/crypto/bn256"
+)
+
+// Backend names the implementation of the groups
+const Backend = "golang.org/x/crypto/bn256"
+
+type (
+	G1 = bn256.G1
+	G2 = bn256.G2
+	GT = bn256.GT
+)
+
+var (
+	// Order is the order of G1, G2 and GT
+	Order = bn256.Order
+	// P is the modulus of the base field, the curve is y^2 = x^3 + 3
+	P, _ = new(big.Int).SetString("65000549695646603732796438742359905742825358107623003571877145026864184071783", 10)
+)
+
+// Pair returns e(g1, g2)
+func Pair(g1 *G1, g2 *G2) *GT {
+	return bn256.Pair(g1, g2)
+}
+
+// G2Neg returns -a, as [r-1]a since bn256.G2 has no Neg
+func G2Neg(a *G2) *G2 {
+	return new(G2).ScalarMult(a, new(big.Int).Sub(Order, big.NewInt(1)))
+}
@@ -1,42 +1,96 @@
+// Package bn256Utils implements the bilinear groups G1, G2 and GT of a BN
+// pairing curve with the API of golang.org/x/crypto/bn256.
+//
+// The groups are backed by golang.org/x/crypto/bn256 by default, so points,
+// signatures and proofs encoded by earlier versions keep decoding. Building
+// with the tag bn256_native switches to scrypto/ecc/bn256, which implements
+// BN254 (alt_bn128) and not the curve of x/crypto: the encodings have the same
+// layout, 64 bytes x || y for G1, 128 bytes for G2 and 384 bytes for GT, but
+// a point encoded under one backend does not decode under the other one, and
+// there is no migration between them.
 package bn256Utils
 
 import (
-	"golang.org/x/crypto/bn256"
+	"bytes"
+	"crypto/rand"
+	"io"
 	"math/big"
 )
 
-func G1ScalarBaseMult(a *big.Int) *bn256.G1 {
-	return new(bn256.G1).ScalarBaseMult(a)
+// numBytes is the size of an encoded coordinate
+const numBytes = 32
+
+// RandomG1 returns x and g1^x with x a random non-zero scalar read from r
+func RandomG1(r io.Reader) (*big.Int, *G1, error) {
+	k, err := randomK(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return k, new(G1).ScalarBaseMult(k), nil
+}
+
+// RandomG2 returns x and g2^x with x a random non-zero scalar read from r
+func RandomG2(r io.Reader) (*big.Int, *G2, error) {
+	k, err := randomK(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return k, new(G2).ScalarBaseMult(k), nil
+}
+
+func randomK(r io.Reader) (k *big.Int, err error) {
+	for {
+		k, err = rand.Int(r, Order)
+		if err != nil || k.Sign() > 0 {
+			return k, err
+		}
+	}
+}
+
+func G1ScalarBaseMult(a *big.Int) *G1 {
+	return new(G1).ScalarBaseMult(a)
+}
+
+func G1ScalarMult(a *G1, b *big.Int) *G1 {
+	return new(G1).ScalarMult(a, b)
+}
+
+func G1Add(a, b *G1) *G1 {
+	return new(G1).Add(a, b)
+}
+
+func G1Neg(a *G1) *G1 {
+	return new(G1).Neg(a)
 }
 
-func G1ScalarMult(a *bn256.G1, b *big.Int) *bn256.G1 {
-	return new(bn256.G1).ScalarMult(a, b)
+func G1Equal(a, b *G1) bool {
+	return bytes.Equal(a.Marshal(), b.Marshal())
 }
 
-func G1Add(a, b *bn256.G1) *bn256.G1 {
-	return new(bn256.G1).Add(a, b)
+func G2ScalarBaseMult(a *big.Int) *G2 {
+	return new(G2).ScalarBaseMult(a)
 }
 
-func G1Neg(a *bn256.G1) *bn256.G1 {
-	return new(bn256.G1).Neg(a)
+func G2ScalarMult(a *G2, b *big.Int) *G2 {
+	return new(G2).ScalarMult(a, b)
 }
 
-func G2ScalarBaseMult(a *big.Int) *bn256.G2 {
-	return new(bn256.G2).ScalarBaseMult(a)
+func G2Add(a, b *G2) *G2 {
+	return new(G2).Add(a, b)
 }
 
-func G2ScalarMult(a *bn256.G2, b *big.Int) *bn256.G2 {
-	return new(bn256.G2).ScalarMult(a, b)
+func G2Equal(a, b *G2) bool {
+	return bytes.Equal(a.Marshal(), b.Marshal())
 }
 
-func G2Add(a, b *bn256.G2) *bn256.G2 {
-	return new(bn256.G2).Add(a, b)
+func GTScalarMult(a *GT, b *big.Int) *GT {
+	return new(GT).ScalarMult(a, b)
 }
 
-func GTScalarMult(a *bn256.GT, b *big.Int) *bn256.GT {
-	return new(bn256.GT).ScalarMult(a, b)
+func GTAdd(a, b *GT) *GT {
+	return new(GT).Add(a, b)
 }
 
-func GTAdd(a, b *bn256.GT) *bn256.GT {
-	return new(bn256.GT).Add(a, b)
+func GTEqual(a, b *GT) bool {
+	return bytes.Equal(a.Marshal(), b.Marshal())
 }
@@ -0,0 +1,149 @@
+package bn256Utils
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+// these tests run against the backend of the build, run them with and
+// without -tags bn256_native
+
+func randomScalar() *big.Int {
+	k, err := randomK(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+func TestG1_ScalarMult(t *testing.T) {
+	a, b := randomScalar(), randomScalar()
+	g := G1ScalarBaseMult(big.NewInt(1))
+	if !G1Equal(G1ScalarBaseMult(big.NewInt(2)), G1Add(g, g)) {
+		t.Error("[2]g1 not match g1 + g1")
+	}
+	ab := new(big.Int).Mul(a, b)
+	if !G1Equal(G1ScalarMult(G1ScalarBaseMult(a), b), G1ScalarBaseMult(ab)) {
+		t.Error("[a][b]g1 not match [ab]g1")
+	}
+	sum := new(big.Int).Add(a, b)
+	if !G1Equal(G1Add(G1ScalarBaseMult(a), G1ScalarBaseMult(b)), G1ScalarBaseMult(sum)) {
+		t.Error("[a]g1 + [b]g1 not match [a+b]g1")
+	}
+	identity := G1ScalarBaseMult(new(big.Int))
+	if !G1Equal(G1Add(g, G1Neg(g)), identity) || !G1Equal(G1ScalarBaseMult(Order), identity) {
+		t.Error("g1 - g1 or [r]g1 not the identity")
+	}
+}
+
+func TestG2_ScalarMult(t *testing.T) {
+	a, b := randomScalar(), randomScalar()
+	g := G2ScalarBaseMult(big.NewInt(1))
+	if !G2Equal(G2ScalarBaseMult(big.NewInt(2)), G2Add(g, g)) {
+		t.Error("[2]g2 not match g2 + g2")
+	}
+	ab := new(big.Int).Mul(a, b)
+	if !G2Equal(G2ScalarMult(G2ScalarBaseMult(a), b), G2ScalarBaseMult(ab)) {
+		t.Error("[a][b]g2 not match [ab]g2")
+	}
+	if !G2Equal(G2Add(g, G2Neg(g)), G2ScalarBaseMult(new(big.Int))) {
+		t.Error("g2 - g2 not the identity")
+	}
+}
+
+func TestPair(t *testing.T) {
+	a, b := randomScalar(), randomScalar()
+	g1 := G1ScalarBaseMult(big.NewInt(1))
+	g2 := G2ScalarBaseMult(big.NewInt(1))
+	base := Pair(g1, g2)
+	// e([a]g1, [b]g2) = e(g1, g2)^{ab}
+	lhs := Pair(G1ScalarBaseMult(a), G2ScalarBaseMult(b))
+	rhs := GTScalarMult(base, new(big.Int).Mul(a, b))
+	if !GTEqual(lhs, rhs) {
+		t.Error("pairing not bilinear")
+	}
+	// e(g1, g2)^a e(g1, g2)^b = e(g1, g2)^{a+b}
+	if !GTEqual(GTAdd(GTScalarMult(base, a), GTScalarMult(base, b)), GTScalarMult(base, new(big.Int).Add(a, b))) {
+		t.Error("GT Add not match the scalars sum")
+	}
+	one := GTScalarMult(base, new(big.Int))
+	if GTEqual(base, one) {
+		t.Error("pairing degenerate")
+	}
+	if !GTEqual(GTAdd(base, new(GT).Neg(base)), one) {
+		t.Error("GT Neg not the inverse")
+	}
+	if !GTEqual(Pair(G1ScalarBaseMult(new(big.Int)), g2), one) {
+		t.Error("pairing of the identity not one")
+	}
+}
+
+func TestG1_Marshal(t *testing.T) {
+	_, p, err := RandomG1(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	m := p.Marshal()
+	if len(m) != 2*numBytes {
+		t.Error("G1 encoding size not match")
+	}
+	q, ok := new(G1).Unmarshal(m)
+	if !ok || !G1Equal(p, q) {
+		t.Error("G1 Unmarshal not match Marshal")
+	}
+	m[len(m)-1] ^= 1
+	if _, ok := new(G1).Unmarshal(m); ok {
+		t.Error("G1 Unmarshal accepts a point off the curve")
+	}
+	if _, ok := new(G1).Unmarshal(m[1:]); ok {
+		t.Error("G1 Unmarshal accepts a short encoding")
+	}
+	identity := G1ScalarBaseMult(new(big.Int)).Marshal()
+	if !bytes.Equal(identity, make([]byte, 2*numBytes)) {
+		t.Error("G1 identity not encoded as zero")
+	}
+	if q, ok := new(G1).Unmarshal(identity); !ok || !G1Equal(q, G1ScalarBaseMult(new(big.Int))) {
+		t.Error("G1 identity not decoded")
+	}
+}
+
+func TestG2_Marshal(t *testing.T) {
+	_, p, err := RandomG2(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	m := p.Marshal()
+	if len(m) != 4*numBytes {
+		t.Error("G2 encoding size not match")
+	}
+	q, ok := new(G2).Unmarshal(m)
+	if !ok || !G2Equal(p, q) {
+		t.Error("G2 Unmarshal not match Marshal")
+	}
+	m[len(m)-1] ^= 1
+	if _, ok := new(G2).Unmarshal(m); ok {
+		t.Error("G2 Unmarshal accepts a point off the twist")
+	}
+}
+
+func TestGT_Marshal(t *testing.T) {
+	_, p, err := RandomG1(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	_, q, err := RandomG2(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	e := Pair(p, q)
+	m := e.Marshal()
+	if len(m) != 12*numBytes {
+		t.Error("GT encoding size not match")
+	}
+	f, ok := new(GT).Unmarshal(m)
+	if !ok || !GTEqual(e, f) {
+		t.Error("GT Unmarshal not match Marshal")
+	}
+}
@@ -0,0 +1,98 @@
+//go:build bn256_native
+// +build bn256_native
+
+package bn256Utils
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	eth "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+// The native backend implements BN254 like go-ethereum, so its points and
+// pairings are compared byte for byte with go-ethereum through Marshal. The
+// default backend is golang.org/x/crypto/bn256 itself and another curve, it
+// cannot be compared value by value with the native one.
+
+// The generator of G2 of go-ethereum is not the one of EIP-197, so the
+// reference computations start from the generators of the native backend
+// decoded by go-ethereum.
+func ethGenerators() (*eth.G1, *eth.G2) {
+	g1, g2 := new(eth.G1), new(eth.G2)
+	if _, err := g1.Unmarshal(G1ScalarBaseMult(big.NewInt(1)).Marshal()); err != nil {
+		panic(err)
+	}
+	if _, err := g2.Unmarshal(G2ScalarBaseMult(big.NewInt(1)).Marshal()); err != nil {
+		panic(err)
+	}
+	return g1, g2
+}
+
+func TestNative_DifferentialG1(t *testing.T) {
+	g1, _ := ethGenerators()
+	for i := 0; i < 16; i++ {
+		a, b := randomScalar(), randomScalar()
+		got := G1Add(G1ScalarBaseMult(a), G1Neg(G1ScalarMult(G1ScalarBaseMult(big.NewInt(3)), b)))
+		ea, eb := new(eth.G1).ScalarMult(g1, a), new(eth.G1).ScalarMult(new(eth.G1).ScalarMult(g1, big.NewInt(3)), b)
+		expected := new(eth.G1).Add(ea, new(eth.G1).Neg(eb))
+		if !bytes.Equal(got.Marshal(), expected.Marshal()) {
+			t.Errorf("[a]g1 - [3b]g1 not match go-ethereum")
+		}
+		m := expected.Marshal()
+		if p, ok := new(G1).Unmarshal(m); !ok || !G1Equal(p, got) {
+			t.Error("go-ethereum G1 encoding not decoded")
+		}
+		m[len(m)-1] ^= 1
+		_, ok := new(G1).Unmarshal(m)
+		if _, err := new(eth.G1).Unmarshal(m); ok != (err == nil) {
+			t.Errorf("G1 Unmarshal of a point off the curve: %v, go-ethereum %v", ok, err)
+		}
+	}
+}
+
+func TestNative_DifferentialG2(t *testing.T) {
+	_, g2 := ethGenerators()
+	for i := 0; i < 8; i++ {
+		a, b := randomScalar(), randomScalar()
+		got := G2Add(G2ScalarBaseMult(a), G2ScalarMult(G2ScalarBaseMult(big.NewInt(5)), b))
+		expected := new(eth.G2).Add(new(eth.G2).ScalarMult(g2, a),
+			new(eth.G2).ScalarMult(new(eth.G2).ScalarMult(g2, big.NewInt(5)), b))
+		if !bytes.Equal(got.Marshal(), expected.Marshal()) {
+			t.Errorf("[a]g2 + [5b]g2 not match go-ethereum")
+		}
+		if !bytes.Equal(G2Neg(got).Marshal(), new(eth.G2).Neg(expected).Marshal()) {
+			t.Errorf("-g2 not match go-ethereum")
+		}
+		m := expected.Marshal()
+		if p, ok := new(G2).Unmarshal(m); !ok || !G2Equal(p, got) {
+			t.Error("go-ethereum G2 encoding not decoded")
+		}
+		m[len(m)-1] ^= 1
+		_, ok := new(G2).Unmarshal(m)
+		if _, err := new(eth.G2).Unmarshal(m); ok != (err == nil) {
+			t.Errorf("G2 Unmarshal of a point off the twist: %v, go-ethereum %v", ok, err)
+		}
+	}
+}
+
+func TestNative_DifferentialPair(t *testing.T) {
+	g1, g2 := ethGenerators()
+	for i := 0; i < 4; i++ {
+		a, b, k := randomScalar(), randomScalar(), randomScalar()
+		got := GTScalarMult(Pair(G1ScalarBaseMult(a), G2ScalarBaseMult(b)), k)
+		e := eth.Pair(new(eth.G1).ScalarMult(g1, a), new(eth.G2).ScalarMult(g2, b))
+		expected := new(eth.GT).ScalarMult(e, k)
+		if !bytes.Equal(got.Marshal(), expected.Marshal()) {
+			t.Errorf("e([a]g1, [b]g2)^k not match go-ethereum")
+		}
+		sum := GTAdd(got, Pair(G1ScalarBaseMult(b), G2ScalarBaseMult(a)))
+		if !bytes.Equal(sum.Marshal(), new(eth.GT).Add(expected, e).Marshal()) {
+			t.Errorf("product in GT not match go-ethereum")
+		}
+		if p, ok := new(GT).Unmarshal(expected.Marshal()); !ok || !GTEqual(p, got) {
+			t.Error("go-ethereum GT encoding not decoded")
+		}
+	}
+}
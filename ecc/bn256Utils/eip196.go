@@ -0,0 +1,183 @@
+package bn256Utils
+
+import (
+	"errors"
+	"math/big"
+
+	"scrypto/ecc/bn256"
+	"scrypto/ecc/bn256/fp"
+	"scrypto/ecc/bn256/fr"
+)
+
+// EIP-196 and EIP-197 define the encodings of the BN254 curve of ecc/bn256 in
+// Ethereum and the precompiles ecAdd (0x06), ecMul (0x07) and ecPairing (0x08).
+// They do not depend on the backend of G1, G2 and GT.
+
+const (
+	// EthG1Size is the size of an encoded G1 point: x || y
+	EthG1Size = 2 * numBytes
+	// EthG2Size is the size of an encoded G2 point: x.imag || x.real || y.imag || y.real
+	EthG2Size = 4 * numBytes
+	// EthPairSize is the size of a (G1, G2) pair in the input of EcPairing
+	EthPairSize = EthG1Size + EthG2Size
+)
+
+var (
+	ErrNotEnoughData            = errors.New("bn256: not enough data")
+	ErrCoordinateExceedsModulus = errors.New("bn256: coordinate exceeds modulus")
+	ErrMalformedPoint           = errors.New("bn256: malformed point")
+	ErrBadPairingInput          = errors.New("bad elliptic curve pairing size")
+)
+
+// curve is the BN254 curve of ecc/bn256, it backs the precompiles and the
+// native G1, G2 and GT
+var curve = bn256.BN256()
+
+// setCoordinate decodes a big-endian coordinate, it rejects values not below
+// the modulus of the base field
+func setCoordinate(z *fp.Element, m []byte) bool {
+	v := new(big.Int).SetBytes(m)
+	if v.Cmp(fp.ElementModulus()) >= 0 {
+		return false
+	}
+	z.SetBigInt(v)
+	return true
+}
+
+// MarshalEthG1 encodes p in EthG1Size bytes, the infinity is all zero
+func MarshalEthG1(p *bn256.G1Jac) []byte {
+	var a bn256.G1Affine
+	p.ToAffineFromJac(&a)
+	res := make([]byte, EthG1Size)
+	copy(res, a.X.Bytes())
+	copy(res[numBytes:], a.Y.Bytes())
+	return res
+}
+
+// UnmarshalEthG1 decodes EthG1Size bytes, it checks the coordinates are below
+// the modulus and the point is on the curve
+func UnmarshalEthG1(m []byte) (*bn256.G1Jac, error) {
+	if len(m) != EthG1Size {
+		return nil, ErrNotEnoughData
+	}
+	var a bn256.G1Affine
+	if !setCoordinate(&a.X, m[:numBytes]) || !setCoordinate(&a.Y, m[numBytes:]) {
+		return nil, ErrCoordinateExceedsModulus
+	}
+	if !a.IsInfinity() && !a.IsOnCurve(curve) {
+		return nil, ErrMalformedPoint
+	}
+	return a.ToJacobian(new(bn256.G1Jac)), nil
+}
+
+// MarshalEthG2 encodes p in EthG2Size bytes, imaginary parts first, the
+// infinity is all zero
+func MarshalEthG2(p *bn256.G2Jac) []byte {
+	var a bn256.G2Affine
+	p.ToAffineFromJac(&a)
+	res := make([]byte, EthG2Size)
+	copy(res, a.X.A1.Bytes())
+	copy(res[numBytes:], a.X.A0.Bytes())
+	copy(res[2*numBytes:], a.Y.A1.Bytes())
+	copy(res[3*numBytes:], a.Y.A0.Bytes())
+	return res
+}
+
+// UnmarshalEthG2 decodes EthG2Size bytes, it checks the coordinates are below
+// the modulus and the point is on the twist and in the subgroup of order r
+func UnmarshalEthG2(m []byte) (*bn256.G2Jac, error) {
+	if len(m) != EthG2Size {
+		return nil, ErrNotEnoughData
+	}
+	var a bn256.G2Affine
+	if !setCoordinate(&a.X.A1, m[:numBytes]) || !setCoordinate(&a.X.A0, m[numBytes:2*numBytes]) ||
+		!setCoordinate(&a.Y.A1, m[2*numBytes:3*numBytes]) || !setCoordinate(&a.Y.A0, m[3*numBytes:]) {
+		return nil, ErrCoordinateExceedsModulus
+	}
+	p := a.ToJacobian(new(bn256.G2Jac))
+	if a.IsInfinity() {
+		return p, nil
+	}
+	if !a.IsOnCurve() || !p.IsInSubGroup(curve) {
+		return nil, ErrMalformedPoint
+	}
+	return p, nil
+}
+
+// rightPad returns input[start:start+size], padded with zeros as the EVM
+// reads the input of a precompile
+func rightPad(input []byte, start, size int) []byte {
+	res := make([]byte, size)
+	if start < len(input) {
+		copy(res, input[start:])
+	}
+	return res
+}
+
+// EcAdd runs the ecAdd precompile: the input holds two G1 points, padded to
+// 128 bytes, and the output is their sum
+func EcAdd(input []byte) ([]byte, error) {
+	p, err := UnmarshalEthG1(rightPad(input, 0, EthG1Size))
+	if err != nil {
+		return nil, err
+	}
+	q, err := UnmarshalEthG1(rightPad(input, EthG1Size, EthG1Size))
+	if err != nil {
+		return nil, err
+	}
+	return MarshalEthG1(p.Add(curve, q)), nil
+}
+
+// EcMul runs the ecMul precompile: the input holds a G1 point and a 32 bytes
+// big-endian scalar, padded to 96 bytes, and the output is their product
+func EcMul(input []byte) ([]byte, error) {
+	p, err := UnmarshalEthG1(rightPad(input, 0, EthG1Size))
+	if err != nil {
+		return nil, err
+	}
+	var k fr.Element
+	k.SetBigInt(new(big.Int).SetBytes(rightPad(input, EthG1Size, numBytes))).FromMont()
+	return MarshalEthG1(new(bn256.G1Jac).ScalarMul(curve, p, k)), nil
+}
+
+// EcPairing runs the ecPairing precompile: the input is a sequence of
+// (G1, G2) pairs and the output is 1 on 32 bytes if the product of their
+// pairings is one, 0 otherwise. An empty input is true.
+func EcPairing(input []byte) ([]byte, error) {
+	if len(input)%EthPairSize != 0 {
+		return nil, ErrBadPairingInput
+	}
+	n := len(input) / EthPairSize
+	ml := make([]bn256.PairingResult, n)
+	for i := 0; i < n; i++ {
+		pair := input[i*EthPairSize : (i+1)*EthPairSize]
+		p, err := UnmarshalEthG1(pair[:EthG1Size])
+		if err != nil {
+			return nil, err
+		}
+		q, err := UnmarshalEthG2(pair[EthG1Size:])
+		if err != nil {
+			return nil, err
+		}
+		var a bn256.G1Affine
+		var b bn256.G2Affine
+		p.ToAffineFromJac(&a)
+		q.ToAffineFromJac(&b)
+		curve.MillerLoop(a, b, &ml[i])
+	}
+	res := make([]byte, 32)
+	if n == 0 {
+		res[31] = 1
+		return res, nil
+	}
+	rest := make([]*bn256.PairingResult, n-1)
+	for i := range rest {
+		rest[i] = &ml[i+1]
+	}
+	var one bn256.PairingResult
+	one.SetOne()
+	if e := curve.FinalExponentiation(&ml[0], rest...); e.Equal(&one) {
+		res[31] = 1
+	}
+	return res, nil
+}
@@ -0,0 +1,286 @@
+package bn256Utils
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"scrypto/ecc/bn256"
+	"scrypto/ecc/bn256/fr"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// ethVector is a test vector of the bn256 precompiles of go-ethereum
+type ethVector struct {
+	name     string
+	input    string
+	expected string
+}
+
+func runVectors(t *testing.T, vectors []ethVector, run func([]byte) ([]byte, error)) {
+	for _, v := range vectors {
+		input, err := hex.DecodeString(v.input)
+		if err != nil {
+			panic(err)
+		}
+		res, err := run(input)
+		if err != nil {
+			t.Errorf("%s: %v", v.name, err)
+			continue
+		}
+		if hex.EncodeToString(res) != v.expected {
+			t.Errorf("%s: got %x, expected %s", v.name, res, v.expected)
+		}
+	}
+}
+
+func TestEcAdd_Vectors(t *testing.T) {
+	runVectors(t, ethAddVectors, EcAdd)
+}
+
+func TestEcMul_Vectors(t *testing.T) {
+	runVectors(t, ethMulVectors, EcMul)
+}
+
+func TestEcPairing_Vectors(t *testing.T) {
+	runVectors(t, ethPairingVectors, EcPairing)
+}
+
+// precompile returns the precompile of go-ethereum at addr
+func precompile(addr byte) vm.PrecompiledContract {
+	return vm.PrecompiledContractsIstanbul[common.BytesToAddress([]byte{addr})]
+}
+
+// compare checks run and the precompile at addr agree on input, both on the
+// output and on rejecting it
+func compare(t *testing.T, name string, addr byte, run func([]byte) ([]byte, error), input []byte) {
+	got, err := run(input)
+	expected, ethErr := precompile(addr).Run(input)
+	if (err != nil) != (ethErr != nil) {
+		t.Errorf("%s: error %v, go-ethereum error %v", name, err, ethErr)
+		return
+	}
+	if !bytes.Equal(got, expected) {
+		t.Errorf("%s: got %x, go-ethereum %x", name, got, expected)
+	}
+}
+
+// scalarOf returns k out of Montgomery form, scalar is not available in
+// every backend
+func scalarOf(k *big.Int) fr.Element {
+	var s fr.Element
+	s.SetBigInt(k).FromMont()
+	return s
+}
+
+func randomEthG1() []byte {
+	k, err := rand.Int(rand.Reader, fr.ElementModulus())
+	if err != nil {
+		panic(err)
+	}
+	var p bn256.G1Jac
+	return MarshalEthG1(p.ScalarMulByGen(curve, scalarOf(k)))
+}
+
+func randomEthG2() []byte {
+	k, err := rand.Int(rand.Reader, fr.ElementModulus())
+	if err != nil {
+		panic(err)
+	}
+	var p bn256.G2Jac
+	return MarshalEthG2(p.ScalarMulByGen(curve, scalarOf(k)))
+}
+
+func concat(parts ...[]byte) []byte {
+	var res []byte
+	for _, p := range parts {
+		res = append(res, p...)
+	}
+	return res
+}
+
+func TestEcAdd_Differential(t *testing.T) {
+	p, q := randomEthG1(), randomEthG1()
+	zero := make([]byte, EthG1Size)
+	offCurve := append([]byte{}, p...)
+	offCurve[EthG1Size-1] ^= 1
+	overModulus := append(bytes.Repeat([]byte{0xff}, numBytes), p[numBytes:]...)
+	neg, err := UnmarshalEthG1(p)
+	if err != nil {
+		panic(err)
+	}
+	inputs := map[string][]byte{
+		"random":        concat(p, q),
+		"double":        concat(p, p),
+		"inverse":       concat(p, MarshalEthG1(neg.Neg(neg))),
+		"infinity":      concat(p, zero),
+		"empty":         nil,
+		"short":         p[:40],
+		"long":          concat(p, q, p),
+		"off curve":     concat(p, offCurve),
+		"over modulus":  concat(overModulus, q),
+		"only infinity": concat(zero, zero),
+	}
+	for name, input := range inputs {
+		compare(t, name, 6, EcAdd, input)
+	}
+}
+
+func TestEcMul_Differential(t *testing.T) {
+	p := randomEthG1()
+	zero := make([]byte, EthG1Size)
+	offCurve := append([]byte{}, p...)
+	offCurve[EthG1Size-1] ^= 1
+	r := make([]byte, numBytes)
+	fr.ElementModulus().FillBytes(r)
+	rPlus := make([]byte, numBytes)
+	new(big.Int).Add(fr.ElementModulus(), big.NewInt(5)).FillBytes(rPlus)
+	inputs := map[string][]byte{
+		"random":      concat(p, randomEthG1()[:numBytes]),
+		"zero":        concat(p, make([]byte, numBytes)),
+		"order":       concat(p, r),
+		"over order":  concat(p, rPlus),
+		"max":         concat(p, bytes.Repeat([]byte{0xff}, numBytes)),
+		"infinity":    concat(zero, rPlus),
+		"short":       concat(p, []byte{1, 2}),
+		"long":        concat(p, r, p),
+		"off curve":   concat(offCurve, rPlus),
+		"empty":       nil,
+		"only points": p,
+	}
+	for name, input := range inputs {
+		compare(t, name, 7, EcMul, input)
+	}
+}
+
+func TestEcPairing_Differential(t *testing.T) {
+	// e([a]g1, g2) * e(-g1, [a]g2) is one
+	k, err := rand.Int(rand.Reader, fr.ElementModulus())
+	if err != nil {
+		panic(err)
+	}
+	s := scalarOf(k)
+	var ag1, g1, nG1 bn256.G1Jac
+	var ag2, g2 bn256.G2Jac
+	ag1.ScalarMulByGen(curve, s)
+	g1.ScalarMulByGen(curve, scalarOf(big.NewInt(1)))
+	nG1.Neg(&g1)
+	ag2.ScalarMulByGen(curve, s)
+	g2.ScalarMulByGen(curve, scalarOf(big.NewInt(1)))
+	one := concat(MarshalEthG1(&ag1), MarshalEthG2(&g2), MarshalEthG1(&nG1), MarshalEthG2(&ag2))
+	notOne := concat(MarshalEthG1(&ag1), MarshalEthG2(&g2), MarshalEthG1(&g1), MarshalEthG2(&ag2))
+	offTwist := append([]byte{}, one...)
+	offTwist[EthPairSize-1] ^= 1
+	inputs := map[string][]byte{
+		"one":            one,
+		"not one":        notOne,
+		"single":         one[:EthPairSize],
+		"infinity":       concat(make([]byte, EthG1Size), MarshalEthG2(&g2)),
+		"random":         concat(randomEthG1(), randomEthG2(), randomEthG1(), randomEthG2()),
+		"off twist":      offTwist,
+		"bad size":       one[:EthPairSize+1],
+		"empty":          nil,
+		"infinity twist": concat(MarshalEthG1(&g1), make([]byte, EthG2Size)),
+	}
+	for name, input := range inputs {
+		compare(t, name, 8, EcPairing, input)
+	}
+}
+
+var ethAddVectors = []ethVector{
+	{
+		name:     "chfast1",
+		input:    "18b18acfb4c2c30276db5411368e7185b311dd124691610c5d3b74034e093dc9063c909c4720840cb5134cb9f59fa749755796819658d32efc0d288198f3726607c2b7f58a84bd6145f00c9c2bc0bb1a187f20ff2c92963a88019e7c6a014eed06614e20c147e940f2d70da3f74c9a17df361706a4485c742bd6788478fa17d7",
+		expected: "2243525c5efd4b9c3d3c45ac0ca3fe4dd85e830a4ce6b65fa1eeaee202839703301d1d33be6da8e509df21cc35964723180eed7532537db9ae5e7d48f195c915",
+	},
+	{
+		name:     "chfast2",
+		input:    "2243525c5efd4b9c3d3c45ac0ca3fe4dd85e830a4ce6b65fa1eeaee202839703301d1d33be6da8e509df21cc35964723180eed7532537db9ae5e7d48f195c91518b18acfb4c2c30276db5411368e7185b311dd124691610c5d3b74034e093dc9063c909c4720840cb5134cb9f59fa749755796819658d32efc0d288198f37266",
+		expected: "2bd3e6d0f3b142924f5ca7b49ce5b9d54c4703d7ae5648e61d02268b1a0a9fb721611ce0a6af85915e2f1d70300909ce2e49dfad4a4619c8390cae66cefdb204",
+	},
+	{
+		name:     "cdetrio1",
+		input:    "0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+		expected: "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+	},
+	{
+		name:     "cdetrio3",
+		input:    "0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+		expected: "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+	},
+	{
+		name:     "cdetrio4",
+		input:    "",
+		expected: "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+	},
+	{
+		name:     "cdetrio5",
+		input:    "000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+		expected: "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+	},
+}
+
+var ethMulVectors = []ethVector{
+	{
+		name:     "chfast1",
+		input:    "2bd3e6d0f3b142924f5ca7b49ce5b9d54c4703d7ae5648e61d02268b1a0a9fb721611ce0a6af85915e2f1d70300909ce2e49dfad4a4619c8390cae66cefdb20400000000000000000000000000000000000000000000000011138ce750fa15c2",
+		expected: "070a8d6a982153cae4be29d434e8faef8a47b274a053f5a4ee2a6c9c13c31e5c031b8ce914eba3a9ffb989f9cdd5b0f01943074bf4f0f315690ec3cec6981afc",
+	},
+	{
+		name:     "chfast2",
+		input:    "070a8d6a982153cae4be29d434e8faef8a47b274a053f5a4ee2a6c9c13c31e5c031b8ce914eba3a9ffb989f9cdd5b0f01943074bf4f0f315690ec3cec6981afc30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd46",
+		expected: "025a6f4181d2b4ea8b724290ffb40156eb0adb514c688556eb79cdea0752c2bb2eff3f31dea215f1eb86023a133a996eb6300b44da664d64251d05381bb8a02e",
+	},
+	{
+		name:     "chfast3",
+		input:    "025a6f4181d2b4ea8b724290ffb40156eb0adb514c688556eb79cdea0752c2bb2eff3f31dea215f1eb86023a133a996eb6300b44da664d64251d05381bb8a02e183227397098d014dc2822db40c0ac2ecbc0b548b438e5469e10460b6c3e7ea3",
+		expected: "14789d0d4a730b354403b5fac948113739e276c23e0258d8596ee72f9cd9d3230af18a63153e0ec25ff9f2951dd3fa90ed0197bfef6e2a1a62b5095b9d2b4a27",
+	},
+	{
+		name:     "cdetrio1",
+		input:    "1a87b0584ce92f4593d161480614f2989035225609f08058ccfa3d0f940febe31a2f3c951f6dadcc7ee9007dff81504b0fcd6d7cf59996efdc33d92bf7f9f8f6ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		expected: "2cde5879ba6f13c0b5aa4ef627f159a3347df9722efce88a9afbb20b763b4c411aa7e43076f6aee272755a7f9b84832e71559ba0d2e0b17d5f9f01755e5b0d11",
+	},
+	{
+		name:     "cdetrio6",
+		input:    "17c139df0efee0f766bc0204762b774362e4ded88953a39ce849a8a7fa163fa901e0559bacb160664764a357af8a9fe70baa9258e0b959273ffc5718c6d4cc7cffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		expected: "29e587aadd7c06722aabba753017c093f70ba7eb1f1c0104ec0564e7e3e21f6022b1143f6a41008e7755c71c3d00b6b915d386de21783ef590486d8afa8453b1",
+	},
+}
+
+var ethPairingVectors = []ethVector{
+	{
+		name:     "jeff1",
+		input:    "1c76476f4def4bb94541d57ebba1193381ffa7aa76ada664dd31c16024c43f593034dd2920f673e204fee2811c678745fc819b55d3e9d294e45c9b03a76aef41209dd15ebff5d46c4bd888e51a93cf99a7329636c63514396b4a452003a35bf704bf11ca01483bfa8b34b43561848d28905960114c8ac04049af4b6315a416782bb8324af6cfc93537a2ad1a445cfd0ca2a71acd7ac41fadbf933c2a51be344d120a2a4cf30c1bf9845f20c6fe39e07ea2cce61f0c9bb048165fe5e4de877550111e129f1cf1097710d41c4ac70fcdfa5ba2023c6ff1cbeac322de49d1b6df7c2032c61a830e3c17286de9462bf242fca2883585b93870a73853face6a6bf411198e9393920d483a7260bfb731fb5d25f1aa493335a9e71297e485b7aef312c21800deef121f1e76426a00665e5c4479674322d4f75edadd46debd5cd992f6ed090689d0585ff075ec9e99ad690c3395bc4b313370b38ef355acdadcd122975b12c85ea5db8c6deb4aab71808dcb408fe3d1e7690c43d37b4ce6cc0166fa7daa",
+		expected: "0000000000000000000000000000000000000000000000000000000000000001",
+	},
+	{
+		name:     "jeff2",
+		input:    "2eca0c7238bf16e83e7a1e6c5d49540685ff51380f309842a98561558019fc0203d3260361bb8451de5ff5ecd17f010ff22f5c31cdf184e9020b06fa5997db841213d2149b006137fcfb23036606f848d638d576a120ca981b5b1a5f9300b3ee2276cf730cf493cd95d64677bbb75fc42db72513a4c1e387b476d056f80aa75f21ee6226d31426322afcda621464d0611d226783262e21bb3bc86b537e986237096df1f82dff337dd5972e32a8ad43e28a78a96a823ef1cd4debe12b6552ea5f06967a1237ebfeca9aaae0d6d0bab8e28c198c5a339ef8a2407e31cdac516db922160fa257a5fd5b280642ff47b65eca77e626cb685c84fa6d3b6882a283ddd1198e9393920d483a7260bfb731fb5d25f1aa493335a9e71297e485b7aef312c21800deef121f1e76426a00665e5c4479674322d4f75edadd46debd5cd992f6ed090689d0585ff075ec9e99ad690c3395bc4b313370b38ef355acdadcd122975b12c85ea5db8c6deb4aab71808dcb408fe3d1e7690c43d37b4ce6cc0166fa7daa",
+		expected: "0000000000000000000000000000000000000000000000000000000000000001",
+	},
+	{
+		name:     "jeff4",
+		input:    "2f2ea0b3da1e8ef11914acf8b2e1b32d99df51f5f4f206fc6b947eae860eddb6068134ddb33dc888ef446b648d72338684d678d2eb2371c61a50734d78da4b7225f83c8b6ab9de74e7da488ef02645c5a16a6652c3c71a15dc37fe3a5dcb7cb122acdedd6308e3bb230d226d16a105295f523a8a02bfc5e8bd2da135ac4c245d065bbad92e7c4e31bf3757f1fe7362a63fbfee50e7dc68da116e67d600d9bf6806d302580dc0661002994e7cd3a7f224e7ddc27802777486bf80f40e4ca3cfdb186bac5188a98c45e6016873d107f5cd131f3a3e339d0375e58bd6219347b008122ae2b09e539e152ec5364e7e2204b03d11d3caa038bfc7cd499f8176aacbee1f39e4e4afc4bc74790a4a028aff2c3d2538731fb755edefd8cb48d6ea589b5e283f150794b6736f670d6a1033f9b46c6f5204f50813eb85c8dc4b59db1c5d39140d97ee4d2b36d99bc49974d18ecca3e7ad51011956051b464d9e27d46cc25e0764bb98575bd466d32db7b15f582b2d5c452b36aa394b789366e5e3ca5aabd415794ab061441e51d01e94640b7e3084a07e02c78cf3103c542bc5b298669f211b88da1679b0b64a63b7e0e7bfe52aae524f73a55be7fe70c7e9bfc94b4cf0da1213d2149b006137fcfb23036606f848d638d576a120ca981b5b1a5f9300b3ee2276cf730cf493cd95d64677bbb75fc42db72513a4c1e387b476d056f80aa75f21ee6226d31426322afcda621464d0611d226783262e21bb3bc86b537e986237096df1f82dff337dd5972e32a8ad43e28a78a96a823ef1cd4debe12b6552ea5f",
+		expected: "0000000000000000000000000000000000000000000000000000000000000001",
+	},
+	{
+		name:     "empty_data",
+		input:    "",
+		expected: "0000000000000000000000000000000000000000000000000000000000000001",
+	},
+	{
+		name:     "one_point",
+		input:    "00000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000002198e9393920d483a7260bfb731fb5d25f1aa493335a9e71297e485b7aef312c21800deef121f1e76426a00665e5c4479674322d4f75edadd46debd5cd992f6ed090689d0585ff075ec9e99ad690c3395bc4b313370b38ef355acdadcd122975b12c85ea5db8c6deb4aab71808dcb408fe3d1e7690c43d37b4ce6cc0166fa7daa",
+		expected: "0000000000000000000000000000000000000000000000000000000000000000",
+	},
+	{
+		name:     "two_point_match_2",
+		input:    "00000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000002198e9393920d483a7260bfb731fb5d25f1aa493335a9e71297e485b7aef312c21800deef121f1e76426a00665e5c4479674322d4f75edadd46debd5cd992f6ed090689d0585ff075ec9e99ad690c3395bc4b313370b38ef355acdadcd122975b12c85ea5db8c6deb4aab71808dcb408fe3d1e7690c43d37b4ce6cc0166fa7daa00000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000002198e9393920d483a7260bfb731fb5d25f1aa493335a9e71297e485b7aef312c21800deef121f1e76426a00665e5c4479674322d4f75edadd46debd5cd992f6ed275dc4a288d1afb3cbb1ac09187524c7db36395df7be3b99e673b13a075a65ec1d9befcd05a5323e6da4d435f3b617cdb3af83285c2df711ef39c01571827f9d",
+		expected: "0000000000000000000000000000000000000000000000000000000000000001",
+	},
+}
@@ -0,0 +1,12 @@
+package ecc
+
+import "scrypto/ecc/internal/pool"
+
+// Execute runs work on [0, n) in parallel on the worker pool of
+// ecc/internal/pool, for the packages outside of ecc which cannot import it
+func Execute(n int, work func(start, end int)) {
+	if n == 0 {
+		return
+	}
+	pool.Execute(0, n, work, false)
+}
@@ -0,0 +1,162 @@
+package group
+
+import (
+	"encoding/binary"
+	"math/big"
+	"scrypto/ecc/bls381"
+	"scrypto/ecc/bls381/fr"
+	"scrypto/ecc/bls381Utils"
+)
+
+var (
+	bls381P, _ = new(big.Int).SetString("4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559787", 10)
+	bls381R    = bls381Utils.Order
+	// bls381H is the cofactor of G1
+	bls381H, _ = new(big.Int).SetString("396c8c005555e1568c00aaab0000aaab", 16)
+	bls381B    = big.NewInt(4)
+)
+
+const bls381FpBytes = 48
+
+type bls381Group struct {
+	curve *bls381.Curve
+}
+
+// BLS381G1 returns the group G1 of BLS12-381, points are *bls381.G1Jac.
+// Points are encoded uncompressed as x || y in 96 bytes, the identity sets
+// the infinity flag 0x40 of the first byte as in the ZCash serialization.
+func BLS381G1() Group {
+	return &bls381Group{curve: bls381.BLS381()}
+}
+
+// rawScalar loads 0 <= k < 2^256 into an fr.Element without Montgomery conversion,
+// which is the form the scalar multiplication of bls381 expects. Scalars modulo r
+// go through bls381Utils.Scalar, this is for r itself and the cofactor.
+func rawScalar(k *big.Int) (e fr.Element) {
+	var buf [32]byte
+	k.FillBytes(buf[:])
+	for i := 0; i < fr.ElementLimbs; i++ {
+		e[i] = binary.BigEndian.Uint64(buf[32-8*(i+1) : 32-8*i])
+	}
+	return e
+}
+
+func (g *bls381Group) Name() string {
+	return "BLS381"
+}
+
+func (g *bls381Group) Order() *big.Int {
+	return bls381R
+}
+
+func (g *bls381Group) Generator() Point {
+	return new(bls381.G1Jac).Set(&g.curve.G1Gen)
+}
+
+func (g *bls381Group) Identity() Point {
+	p := new(bls381.G1Jac)
+	p.X.SetOne()
+	p.Y.SetOne()
+	return p
+}
+
+func (g *bls381Group) ScalarBaseMult(k *big.Int) Point {
+	return bls381Utils.G1MulBase(bls381Utils.NewScalar(k))
+}
+
+func (g *bls381Group) ScalarMult(a Point, k *big.Int) Point {
+	return bls381Utils.G1Mul(a.(*bls381.G1Jac), bls381Utils.NewScalar(k))
+}
+
+func (g *bls381Group) Add(a, b Point) Point {
+	return new(bls381.G1Jac).Set(a.(*bls381.G1Jac)).Add(g.curve, b.(*bls381.G1Jac))
+}
+
+func (g *bls381Group) Neg(a Point) Point {
+	return new(bls381.G1Jac).Neg(a.(*bls381.G1Jac))
+}
+
+func (g *bls381Group) Equal(a, b Point) bool {
+	return a.(*bls381.G1Jac).Equal(b.(*bls381.G1Jac))
+}
+
+func (g *bls381Group) IsIdentity(a Point) bool {
+	return a.(*bls381.G1Jac).Z.IsZero()
+}
+
+func (g *bls381Group) Marshal(a Point) []byte {
+	res := make([]byte, 2*bls381FpBytes)
+	if g.IsIdentity(a) {
+		res[0] = 0x40
+		return res
+	}
+	var aff bls381.G1Affine
+	var x, y big.Int
+	a.(*bls381.G1Jac).ToAffineFromJac(&aff)
+	aff.X.ToBigIntRegular(&x).FillBytes(res[:bls381FpBytes])
+	aff.Y.ToBigIntRegular(&y).FillBytes(res[bls381FpBytes:])
+	return res
+}
+
+func (g *bls381Group) Unmarshal(data []byte) (Point, error) {
+	if len(data) != 2*bls381FpBytes || data[0]&0x80 != 0 {
+		return nil, errInvalidBytes
+	}
+	if data[0]&0x40 != 0 {
+		for _, b := range data[1:] {
+			if b != 0 {
+				return nil, errInvalidBytes
+			}
+		}
+		if data[0] != 0x40 {
+			return nil, errInvalidBytes
+		}
+		return g.Identity(), nil
+	}
+	x := new(big.Int).SetBytes(data[:bls381FpBytes])
+	y := new(big.Int).SetBytes(data[bls381FpBytes:])
+	if x.Cmp(bls381P) >= 0 || y.Cmp(bls381P) >= 0 || !bls381OnCurve(x, y) {
+		return nil, errNotOnCurve
+	}
+	p := bls381FromAffine(x, y)
+	// r * p must be the identity
+	check := new(bls381.G1Jac).ScalarMul(g.curve, p, rawScalar(bls381R))
+	if !check.Z.IsZero() {
+		return nil, errNotInGroup
+	}
+	return p, nil
+}
+
+// HashToPoint uses try-and-increment on the x-coordinate and then clears the cofactor
+func (g *bls381Group) HashToPoint(domain, message []byte) Point {
+	for counter := uint32(0); ; counter++ {
+		x := hashToField(domain, message, counter, bls381P)
+		y2 := new(big.Int).Exp(x, big.NewInt(3), bls381P)
+		y2.Add(y2, bls381B)
+		y, ok := sqrt(y2, bls381P)
+		if !ok {
+			continue
+		}
+		p := new(bls381.G1Jac).ScalarMul(g.curve, bls381FromAffine(x, y), rawScalar(bls381H))
+		if !p.Z.IsZero() {
+			return p
+		}
+	}
+}
+
+func bls381OnCurve(x, y *big.Int) bool {
+	lhs := new(big.Int).Mul(y, y)
+	lhs.Mod(lhs, bls381P)
+	rhs := new(big.Int).Exp(x, big.NewInt(3), bls381P)
+	rhs.Add(rhs, bls381B)
+	rhs.Mod(rhs, bls381P)
+	return lhs.Cmp(rhs) == 0
+}
+
+func bls381FromAffine(x, y *big.Int) *bls381.G1Jac {
+	p := new(bls381.G1Jac)
+	p.X.SetBigInt(x)
+	p.Y.SetBigInt(y)
+	p.Z.SetOne()
+	return p
+}
@@ -0,0 +1,92 @@
+package group
+
+import (
+	"math/big"
+	"scrypto/ecc/bn256Utils"
+)
+
+// bn256B is the constant of the curve y^2 = x^3 + 3, over the field of bn256Utils.P
+var bn256B = big.NewInt(3)
+
+type bn256Group struct{}
+
+// BN256G1 returns the group G1 of the BN256 pairing curve, points are *bn256Utils.G1
+func BN256G1() Group {
+	return &bn256Group{}
+}
+
+func (g *bn256Group) Name() string {
+	return "BN256"
+}
+
+func (g *bn256Group) Order() *big.Int {
+	return bn256Utils.Order
+}
+
+func (g *bn256Group) Generator() Point {
+	return bn256Utils.G1ScalarBaseMult(big.NewInt(1))
+}
+
+func (g *bn256Group) Identity() Point {
+	return bn256Utils.G1ScalarBaseMult(new(big.Int))
+}
+
+func (g *bn256Group) ScalarBaseMult(k *big.Int) Point {
+	return bn256Utils.G1ScalarBaseMult(reduce(k, bn256Utils.Order))
+}
+
+func (g *bn256Group) ScalarMult(a Point, k *big.Int) Point {
+	return bn256Utils.G1ScalarMult(a.(*bn256Utils.G1), reduce(k, bn256Utils.Order))
+}
+
+func (g *bn256Group) Add(a, b Point) Point {
+	return bn256Utils.G1Add(a.(*bn256Utils.G1), b.(*bn256Utils.G1))
+}
+
+func (g *bn256Group) Neg(a Point) Point {
+	return bn256Utils.G1Neg(a.(*bn256Utils.G1))
+}
+
+func (g *bn256Group) Equal(a, b Point) bool {
+	return bn256Utils.G1Equal(a.(*bn256Utils.G1), b.(*bn256Utils.G1))
+}
+
+func (g *bn256Group) IsIdentity(a Point) bool {
+	return g.Equal(a, g.Identity())
+}
+
+// Marshal uses the 64 bytes x || y encoding of bn256Utils.G1, the identity is all zero
+func (g *bn256Group) Marshal(a Point) []byte {
+	return a.(*bn256Utils.G1).Marshal()
+}
+
+func (g *bn256Group) Unmarshal(data []byte) (Point, error) {
+	if len(data) != 64 {
+		return nil, errInvalidBytes
+	}
+	p, ok := new(bn256Utils.G1).Unmarshal(data)
+	if !ok {
+		return nil, errNotOnCurve
+	}
+	return p, nil
+}
+
+// HashToPoint uses try-and-increment on the x-coordinate, G1 has cofactor 1
+func (g *bn256Group) HashToPoint(domain, message []byte) Point {
+	for counter := uint32(0); ; counter++ {
+		x := hashToField(domain, message, counter, bn256Utils.P)
+		// y^2 = x^3 + 3
+		y2 := new(big.Int).Exp(x, big.NewInt(3), bn256Utils.P)
+		y2.Add(y2, bn256B)
+		y, ok := sqrt(y2, bn256Utils.P)
+		if !ok || (x.Sign() == 0 && y.Sign() == 0) {
+			continue
+		}
+		buf := make([]byte, 64)
+		x.FillBytes(buf[:32])
+		y.FillBytes(buf[32:])
+		if p, ok := new(bn256Utils.G1).Unmarshal(buf); ok {
+			return p
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Package group puts the prime-order groups used across the project behind a
+// single interface, so that a protocol such as a Pedersen commitment can be
+// written once and instantiated on P-256 or on G1 of a pairing curve.
+package group
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"scrypto/smath"
+)
+
+// Point is an element of a Group. A Point is only meaningful to the Group that
+// produced it; passing it to another Group panics.
+type Point interface{}
+
+// Group is a cyclic group of prime order together with a fixed generator.
+// Scalars may be any *big.Int, they are reduced modulo Order before use.
+type Group interface {
+	// Name returns a short identifier of the group, e.g. "P256"
+	Name() string
+	// Order returns the prime order of the group
+	Order() *big.Int
+	// Generator returns the fixed generator g
+	Generator() Point
+	// Identity returns the neutral element
+	Identity() Point
+	// ScalarBaseMult returns g^k
+	ScalarBaseMult(k *big.Int) Point
+	// ScalarMult returns a^k
+	ScalarMult(a Point, k *big.Int) Point
+	// Add returns a * b
+	Add(a, b Point) Point
+	// Neg returns a^{-1}
+	Neg(a Point) Point
+	// Equal reports whether a == b
+	Equal(a, b Point) bool
+	// IsIdentity reports whether a is the neutral element
+	IsIdentity(a Point) bool
+	// Marshal encodes a point, the identity included
+	Marshal(a Point) []byte
+	// Unmarshal decodes a point and checks that it belongs to the group
+	Unmarshal(data []byte) (Point, error)
+	// HashToPoint maps (domain, message) to a point whose discrete logarithm
+	// with respect to any other point is unknown
+	HashToPoint(domain, message []byte) Point
+}
+
+var (
+	errNotOnCurve   = errors.New("point is not on the curve")
+	errNotInGroup   = errors.New("point is not in the prime order subgroup")
+	errInvalidBytes = errors.New("invalid point encoding")
+)
+
+// Sub returns a * b^{-1}
+func Sub(g Group, a, b Point) Point {
+	return g.Add(a, g.Neg(b))
+}
+
+// RandomScalar draws a uniform scalar in [1, g.Order())
+func RandomScalar(g Group, random io.Reader) (k *big.Int, err error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	for {
+		k, err = rand.Int(random, g.Order())
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() > 0 {
+			return k, nil
+		}
+	}
+}
+
+// multiScalarMulter is implemented by the groups with a multi-scalar
+// multiplication faster than one exponentiation per point
+type multiScalarMulter interface {
+	multiScalarMult(points []Point, scalars []*big.Int) Point
+}
+
+// MultiScalarMult returns \prod points[i]^{scalars[i]}
+func MultiScalarMult(g Group, points []Point, scalars []*big.Int) (res Point, err error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("points and scalars size not match")
+	}
+	if m, ok := g.(multiScalarMulter); ok {
+		return m.multiScalarMult(points, scalars), nil
+	}
+	res = g.Identity()
+	for i := range points {
+		res = g.Add(res, g.ScalarMult(points[i], scalars[i]))
+	}
+	return res, nil
+}
+
+// reduce returns k mod n as a new integer
+func reduce(k, n *big.Int) *big.Int {
+	return new(big.Int).Mod(k, n)
+}
+
+// sqrt returns a square root of a modulo the prime p,
+// ok is false when a is not a quadratic residue
+func sqrt(a, p *big.Int) (root *big.Int, ok bool) {
+	return (&smath.Field{P: p}).Sqrt(a)
+}
+
+// hashToField returns SHA-512(len(domain) || domain || message || counter) mod p,
+// it is the candidate x-coordinate of the try-and-increment HashToPoint
+func hashToField(domain, message []byte, counter uint32, p *big.Int) *big.Int {
+	h := sha512.New()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(len(domain)))
+	h.Write(buf[:])
+	h.Write(domain)
+	h.Write(message)
+	binary.BigEndian.PutUint32(buf[:4], counter)
+	h.Write(buf[:4])
+	x := new(big.Int).SetBytes(h.Sum(nil))
+	return x.Mod(x, p)
+}
@@ -0,0 +1,106 @@
+package group
+
+import (
+	"math/big"
+	"testing"
+)
+
+var groups = []Group{P256(), Secp256k1(), Ristretto255(), BN256G1(), BLS381G1()}
+
+func TestGroup_ScalarMult(t *testing.T) {
+	for _, g := range groups {
+		a, _ := RandomScalar(g, nil)
+		b, _ := RandomScalar(g, nil)
+		ab := new(big.Int).Mul(a, b)
+		// (g^a)^b == g^{ab}
+		if !g.Equal(g.ScalarMult(g.ScalarBaseMult(a), b), g.ScalarBaseMult(ab)) {
+			t.Error(g.Name(), "ScalarMult not match")
+		}
+		// g^a * g^b == g^{a+b}
+		sum := new(big.Int).Add(a, b)
+		if !g.Equal(g.Add(g.ScalarBaseMult(a), g.ScalarBaseMult(b)), g.ScalarBaseMult(sum)) {
+			t.Error(g.Name(), "Add not match")
+		}
+		// g^q == 1
+		if !g.IsIdentity(g.ScalarBaseMult(g.Order())) || !g.IsIdentity(g.ScalarMult(g.Generator(), g.Order())) {
+			t.Error(g.Name(), "order is not the group order")
+		}
+		// g^a * g^{-a} == 1
+		A := g.ScalarBaseMult(a)
+		if !g.IsIdentity(Sub(g, A, A)) || !g.Equal(g.Add(A, g.Identity()), A) {
+			t.Error(g.Name(), "identity not match")
+		}
+		if !g.Equal(g.Neg(A), g.ScalarBaseMult(new(big.Int).Neg(a))) {
+			t.Error(g.Name(), "Neg not match")
+		}
+	}
+}
+
+func TestGroup_Marshal(t *testing.T) {
+	for _, g := range groups {
+		k, _ := RandomScalar(g, nil)
+		for _, p := range []Point{g.ScalarBaseMult(k), g.Identity(), g.Generator()} {
+			q, err := g.Unmarshal(g.Marshal(p))
+			if err != nil {
+				t.Fatal(g.Name(), err)
+			}
+			if !g.Equal(p, q) {
+				t.Error(g.Name(), "Unmarshal not match")
+			}
+		}
+		bad := g.Marshal(g.Generator())
+		bad[len(bad)-1] ^= 1
+		if _, err := g.Unmarshal(bad); err == nil {
+			t.Error(g.Name(), "invalid point accepted")
+		}
+	}
+}
+
+func TestGroup_HashToPoint(t *testing.T) {
+	for _, g := range groups {
+		p := g.HashToPoint([]byte("domain"), []byte("message"))
+		q := g.HashToPoint([]byte("domain"), []byte("message"))
+		r := g.HashToPoint([]byte("domain"), []byte("message2"))
+		if !g.Equal(p, q) || g.Equal(p, r) {
+			t.Error(g.Name(), "HashToPoint is not deterministic")
+		}
+		if !g.IsIdentity(g.ScalarMult(p, g.Order())) || g.IsIdentity(p) {
+			t.Error(g.Name(), "HashToPoint not in group")
+		}
+		if _, err := g.Unmarshal(g.Marshal(p)); err != nil {
+			t.Error(g.Name(), err)
+		}
+	}
+}
+
+func TestMultiScalarMult(t *testing.T) {
+	for _, g := range groups {
+		var points []Point
+		var scalars []*big.Int
+		expected := g.Identity()
+		for i := 0; i < 40; i++ {
+			k, _ := RandomScalar(g, nil)
+			p := g.ScalarBaseMult(k)
+			if i == 3 {
+				p = g.Identity()
+			}
+			s, _ := RandomScalar(g, nil)
+			if i == 5 {
+				s = big.NewInt(0)
+			}
+			points = append(points, p)
+			scalars = append(scalars, s)
+			expected = g.Add(expected, g.ScalarMult(p, s))
+		}
+		res, err := MultiScalarMult(g, points, scalars)
+		if err != nil {
+			panic(err)
+		}
+		if !g.Equal(res, expected) {
+			t.Error(g.Name(), "MultiScalarMult not match")
+		}
+		if _, err := MultiScalarMult(g, points, scalars[1:]); err == nil {
+			t.Error(g.Name(), "size mismatch accepted")
+		}
+	}
+}
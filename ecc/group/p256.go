@@ -0,0 +1,99 @@
+package group
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"scrypto/ecc/p256Utils"
+)
+
+type p256Group struct {
+	curve elliptic.Curve
+}
+
+// P256 returns the NIST P-256 group, points are *p256Utils.CurvePoint and the
+// identity is encoded as the single byte 0x00 (SEC 1 point at infinity)
+func P256() Group {
+	return &p256Group{curve: elliptic.P256()}
+}
+
+func (g *p256Group) Name() string {
+	return "P256"
+}
+
+func (g *p256Group) Order() *big.Int {
+	return p256Utils.N
+}
+
+func (g *p256Group) Generator() Point {
+	return p256Utils.GetBaseGenerator()
+}
+
+func (g *p256Group) Identity() Point {
+	// (0, 0) is the affine convention of crypto/elliptic for the point at infinity
+	return &p256Utils.CurvePoint{Curve: g.curve, X: new(big.Int), Y: new(big.Int)}
+}
+
+func (g *p256Group) ScalarBaseMult(k *big.Int) Point {
+	return p256Utils.ScalarBaseMult(reduce(k, p256Utils.N))
+}
+
+func (g *p256Group) ScalarMult(a Point, k *big.Int) Point {
+	return p256Utils.ScalarMult(a.(*p256Utils.CurvePoint), reduce(k, p256Utils.N))
+}
+
+func (g *p256Group) Add(a, b Point) Point {
+	return p256Utils.ScalarAdd(a.(*p256Utils.CurvePoint), b.(*p256Utils.CurvePoint))
+}
+
+func (g *p256Group) Neg(a Point) Point {
+	p := a.(*p256Utils.CurvePoint)
+	if g.IsIdentity(p) {
+		return g.Identity()
+	}
+	y := new(big.Int).Sub(g.curve.Params().P, p.Y)
+	return &p256Utils.CurvePoint{Curve: g.curve, X: new(big.Int).Set(p.X), Y: y}
+}
+
+func (g *p256Group) Equal(a, b Point) bool {
+	p, q := a.(*p256Utils.CurvePoint), b.(*p256Utils.CurvePoint)
+	return p.X.Cmp(q.X) == 0 && p.Y.Cmp(q.Y) == 0
+}
+
+func (g *p256Group) IsIdentity(a Point) bool {
+	p := a.(*p256Utils.CurvePoint)
+	return p.X.Sign() == 0 && p.Y.Sign() == 0
+}
+
+func (g *p256Group) Marshal(a Point) []byte {
+	if g.IsIdentity(a) {
+		return []byte{0}
+	}
+	return p256Utils.Marshal(a.(*p256Utils.CurvePoint))
+}
+
+func (g *p256Group) Unmarshal(data []byte) (Point, error) {
+	if len(data) == 1 && data[0] == 0 {
+		return g.Identity(), nil
+	}
+	x, y := elliptic.Unmarshal(g.curve, data)
+	if x == nil {
+		return nil, errNotOnCurve
+	}
+	return &p256Utils.CurvePoint{Curve: g.curve, X: x, Y: y}, nil
+}
+
+// HashToPoint uses try-and-increment on the x-coordinate, P-256 has cofactor 1
+func (g *p256Group) HashToPoint(domain, message []byte) Point {
+	params := g.curve.Params()
+	size := (params.BitSize + 7) / 8
+	for counter := uint32(0); ; counter++ {
+		x := hashToField(domain, message, counter, params.P)
+		compressed := make([]byte, 1+size)
+		compressed[0] = 2
+		x.FillBytes(compressed[1:])
+		px, py := elliptic.UnmarshalCompressed(g.curve, compressed)
+		if px != nil {
+			return &p256Utils.CurvePoint{Curve: g.curve, X: px, Y: py}
+		}
+	}
+}
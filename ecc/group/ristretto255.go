@@ -0,0 +1,103 @@
+package group
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/gtank/ristretto255"
+)
+
+// ristretto255L is the prime order of ristretto255, 2^252 + 27742317777372353535851937790883648493
+var ristretto255L, _ = new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+
+type ristretto255Group struct{}
+
+// Ristretto255 returns the prime order group ristretto255 of RFC 9496 on
+// Curve25519, points are *ristretto255.Element encoded in 32 bytes, the
+// identity as 32 zero bytes
+func Ristretto255() Group {
+	return &ristretto255Group{}
+}
+
+// ristretto255Scalar converts k mod l to a scalar, scalars are little-endian
+func ristretto255Scalar(k *big.Int) *ristretto255.Scalar {
+	be := reduce(k, ristretto255L).FillBytes(make([]byte, 32))
+	le := make([]byte, 32)
+	for i := range be {
+		le[i] = be[31-i]
+	}
+	s := ristretto255.NewScalar()
+	if err := s.Decode(le); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (g *ristretto255Group) Name() string {
+	return "ristretto255"
+}
+
+func (g *ristretto255Group) Order() *big.Int {
+	return ristretto255L
+}
+
+func (g *ristretto255Group) Generator() Point {
+	return ristretto255.NewElement().Base()
+}
+
+func (g *ristretto255Group) Identity() Point {
+	return ristretto255.NewElement().Zero()
+}
+
+func (g *ristretto255Group) ScalarBaseMult(k *big.Int) Point {
+	return ristretto255.NewElement().ScalarBaseMult(ristretto255Scalar(k))
+}
+
+func (g *ristretto255Group) ScalarMult(a Point, k *big.Int) Point {
+	return ristretto255.NewElement().ScalarMult(ristretto255Scalar(k), a.(*ristretto255.Element))
+}
+
+func (g *ristretto255Group) Add(a, b Point) Point {
+	return ristretto255.NewElement().Add(a.(*ristretto255.Element), b.(*ristretto255.Element))
+}
+
+func (g *ristretto255Group) Neg(a Point) Point {
+	return ristretto255.NewElement().Negate(a.(*ristretto255.Element))
+}
+
+func (g *ristretto255Group) Equal(a, b Point) bool {
+	return a.(*ristretto255.Element).Equal(b.(*ristretto255.Element)) == 1
+}
+
+func (g *ristretto255Group) IsIdentity(a Point) bool {
+	return g.Equal(a, g.Identity())
+}
+
+func (g *ristretto255Group) Marshal(a Point) []byte {
+	return a.(*ristretto255.Element).Encode(nil)
+}
+
+// Unmarshal accepts the canonical encodings only, the identity included
+func (g *ristretto255Group) Unmarshal(data []byte) (Point, error) {
+	if len(data) != 32 {
+		return nil, errInvalidBytes
+	}
+	e := ristretto255.NewElement()
+	if err := e.Decode(data); err != nil {
+		return nil, errInvalidBytes
+	}
+	return e, nil
+}
+
+// HashToPoint maps SHA-512(len(domain) || domain || message) with the
+// one-way map of ristretto255
+func (g *ristretto255Group) HashToPoint(domain, message []byte) Point {
+	h := sha512.New()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(len(domain)))
+	h.Write(buf[:])
+	h.Write(domain)
+	h.Write(message)
+	return ristretto255.NewElement().FromUniformBytes(h.Sum(nil))
+}
@@ -0,0 +1,108 @@
+package group
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"scrypto/ecc/secp256k1Utils"
+)
+
+type secp256k1Group struct {
+	curve elliptic.Curve
+}
+
+// Secp256k1 returns the secp256k1 group, points are *secp256k1Utils.CurvePoint
+// and the identity is encoded as the single byte 0x00 (SEC 1 point at infinity)
+func Secp256k1() Group {
+	return &secp256k1Group{curve: secp256k1Utils.S256()}
+}
+
+func (g *secp256k1Group) Name() string {
+	return "secp256k1"
+}
+
+func (g *secp256k1Group) Order() *big.Int {
+	return secp256k1Utils.N
+}
+
+func (g *secp256k1Group) Generator() Point {
+	return secp256k1Utils.GetBaseGenerator()
+}
+
+func (g *secp256k1Group) Identity() Point {
+	// (0, 0) is the affine convention of crypto/elliptic for the point at infinity
+	return &secp256k1Utils.CurvePoint{Curve: g.curve, X: new(big.Int), Y: new(big.Int)}
+}
+
+func (g *secp256k1Group) ScalarBaseMult(k *big.Int) Point {
+	return secp256k1Utils.ScalarBaseMult(reduce(k, secp256k1Utils.N))
+}
+
+func (g *secp256k1Group) ScalarMult(a Point, k *big.Int) Point {
+	return secp256k1Utils.ScalarMult(a.(*secp256k1Utils.CurvePoint), reduce(k, secp256k1Utils.N))
+}
+
+func (g *secp256k1Group) Add(a, b Point) Point {
+	return secp256k1Utils.ScalarAdd(a.(*secp256k1Utils.CurvePoint), b.(*secp256k1Utils.CurvePoint))
+}
+
+func (g *secp256k1Group) Neg(a Point) Point {
+	p := a.(*secp256k1Utils.CurvePoint)
+	if g.IsIdentity(p) {
+		return g.Identity()
+	}
+	y := new(big.Int).Sub(g.curve.Params().P, p.Y)
+	return &secp256k1Utils.CurvePoint{Curve: g.curve, X: new(big.Int).Set(p.X), Y: y}
+}
+
+func (g *secp256k1Group) Equal(a, b Point) bool {
+	p, q := a.(*secp256k1Utils.CurvePoint), b.(*secp256k1Utils.CurvePoint)
+	return p.X.Cmp(q.X) == 0 && p.Y.Cmp(q.Y) == 0
+}
+
+func (g *secp256k1Group) IsIdentity(a Point) bool {
+	p := a.(*secp256k1Utils.CurvePoint)
+	return p.X.Sign() == 0 && p.Y.Sign() == 0
+}
+
+func (g *secp256k1Group) Marshal(a Point) []byte {
+	if g.IsIdentity(a) {
+		return []byte{0}
+	}
+	return secp256k1Utils.Marshal(a.(*secp256k1Utils.CurvePoint))
+}
+
+func (g *secp256k1Group) Unmarshal(data []byte) (Point, error) {
+	if len(data) == 1 && data[0] == 0 {
+		return g.Identity(), nil
+	}
+	x, y := elliptic.Unmarshal(g.curve, data)
+	if x == nil {
+		return nil, errNotOnCurve
+	}
+	return &secp256k1Utils.CurvePoint{Curve: g.curve, X: x, Y: y}, nil
+}
+
+// multiScalarMult runs the bucket method of secp256k1Utils
+func (g *secp256k1Group) multiScalarMult(points []Point, scalars []*big.Int) Point {
+	ps := make([]*secp256k1Utils.CurvePoint, len(points))
+	for i, p := range points {
+		ps[i] = p.(*secp256k1Utils.CurvePoint)
+	}
+	return secp256k1Utils.MultiScalarMult(ps, scalars)
+}
+
+// HashToPoint uses try-and-increment on the x-coordinate, secp256k1 has cofactor 1
+func (g *secp256k1Group) HashToPoint(domain, message []byte) Point {
+	params := g.curve.Params()
+	size := (params.BitSize + 7) / 8
+	for counter := uint32(0); ; counter++ {
+		x := hashToField(domain, message, counter, params.P)
+		compressed := make([]byte, 1+size)
+		compressed[0] = 2
+		x.FillBytes(compressed[1:])
+		px, py := elliptic.UnmarshalCompressed(g.curve, compressed)
+		if px != nil {
+			return &secp256k1Utils.CurvePoint{Curve: g.curve, X: px, Y: py}
+		}
+	}
+}
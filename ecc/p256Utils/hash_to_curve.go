@@ -0,0 +1,78 @@
+package p256Utils
+
+import (
+	"math/big"
+
+	"scrypto/sutils/xmd"
+)
+
+// Hashing to P-256 with the suite P256_XMD:SHA-256_SSWU_RO_ of RFC 9380:
+// hash_to_field with expand_message_xmd, the simplified SWU map and the sum of
+// the two images, P-256 has cofactor 1. Unlike HashToCurve, whose output is a
+// scalar, the discrete logarithm of the result is unknown.
+
+// hashToFieldL is the number of bytes hashed into one element of the field
+const hashToFieldL = 48
+
+var (
+	curveA = big.NewInt(-3)
+	// sswuZ is the constant Z = -10 of the suite
+	sswuZ = big.NewInt(-10)
+)
+
+// HashToPoint maps msg to a point with the domain separation tag dst
+func HashToPoint(msg, dst []byte) (*CurvePoint, error) {
+	p := p256.Params().P
+	uniform, err := xmd.Expand(msg, dst, 2*hashToFieldL)
+	if err != nil {
+		return nil, err
+	}
+	u0 := new(big.Int).SetBytes(uniform[:hashToFieldL])
+	u1 := new(big.Int).SetBytes(uniform[hashToFieldL:])
+	q0 := mapToCurveSSWU(u0.Mod(u0, p))
+	q1 := mapToCurveSSWU(u1.Mod(u1, p))
+	return ScalarAdd(q0, q1), nil
+}
+
+// mapToCurveSSWU is the simplified Shallue-van de Woestijne-Ulas map of
+// RFC 9380 section 6.6.2 for a curve with A, B != 0
+func mapToCurveSSWU(u *big.Int) *CurvePoint {
+	params := p256.Params()
+	p := params.P
+	mod := func(a *big.Int) *big.Int {
+		return a.Mod(a, p)
+	}
+	g := func(x *big.Int) *big.Int {
+		// x^3 + A x + B
+		res := new(big.Int).Mul(x, x)
+		res.Mul(res, x)
+		res.Add(res, new(big.Int).Mul(curveA, x))
+		return mod(res.Add(res, params.B))
+	}
+	uu := mod(new(big.Int).Mul(u, u))
+	zuu := mod(new(big.Int).Mul(sswuZ, uu))
+	// tv1 = 1 / (Z^2 u^4 + Z u^2), 0 when the denominator is 0
+	tv1 := mod(new(big.Int).Add(new(big.Int).Mul(zuu, zuu), zuu))
+	aInv := new(big.Int).ModInverse(mod(new(big.Int).Set(curveA)), p)
+	var x1 *big.Int
+	if tv1.Sign() == 0 {
+		// x1 = B / (Z A)
+		x1 = mod(new(big.Int).Mul(params.B, new(big.Int).ModInverse(mod(new(big.Int).Mul(sswuZ, curveA)), p)))
+	} else {
+		tv1.ModInverse(tv1, p)
+		// x1 = (-B / A) (1 + tv1)
+		x1 = new(big.Int).Neg(params.B)
+		x1.Mul(x1, aInv)
+		x1 = mod(x1.Mul(x1, tv1.Add(tv1, big.NewInt(1))))
+	}
+	x, y := x1, new(big.Int).ModSqrt(g(x1), p)
+	if y == nil {
+		x = mod(new(big.Int).Mul(zuu, x1))
+		y = new(big.Int).ModSqrt(g(x), p)
+	}
+	// sgn0 is the parity for a prime field
+	if u.Bit(0) != y.Bit(0) {
+		y = mod(y.Neg(y))
+	}
+	return &CurvePoint{Curve: p256, X: x, Y: y}
+}
@@ -0,0 +1,31 @@
+package p256Utils
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestHashToPoint(t *testing.T) {
+	// RFC 9380 J.1.1, P256_XMD:SHA-256_SSWU_RO_
+	dst := []byte("QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_RO_")
+	vectors := []struct {
+		msg, x, y string
+	}{
+		{"", "2c15230b26dbc6fc9a37051158c95b79656e17a1a920b11394ca91c44247d3e4", "8a7a74985cc5c776cdfe4b1f19884970453912e9d31528c060be9ab5c43e8415"},
+		{"abc", "0bb8b87485551aa43ed54f009230450b492fead5f1cc91658775dac4a3388a0f", "5c41b3d0731a27a7b14bc0bf0ccded2d8751f83493404c84a88e71ffd424212e"},
+	}
+	for _, v := range vectors {
+		p, err := HashToPoint([]byte(v.msg), dst)
+		if err != nil {
+			panic(err)
+		}
+		x, _ := new(big.Int).SetString(v.x, 16)
+		y, _ := new(big.Int).SetString(v.y, 16)
+		if p.X.Cmp(x) != 0 || p.Y.Cmp(y) != 0 {
+			t.Errorf("hash of %q not match", v.msg)
+		}
+		if !IsOnCurve(p) {
+			t.Errorf("hash of %q not on the curve", v.msg)
+		}
+	}
+}
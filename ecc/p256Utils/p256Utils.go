@@ -6,9 +6,9 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
-	"golang.org/x/crypto/bn256"
 	"math/big"
 	"regexp"
+	"scrypto/ecc/bn256Utils"
 )
 
 type CurvePoint = ecdsa.PublicKey
@@ -182,7 +182,7 @@ func ComputeGAndHVec(n int) (gs []*CurvePoint, hs []*CurvePoint) {
 	return gs, hs
 }
 
-func ConvertG1ToP256(a *bn256.G1) (point *CurvePoint) {
+func ConvertG1ToP256(a *bn256Utils.G1) (point *CurvePoint) {
 	aInt := new(big.Int).SetBytes(a.Marshal())
 	point = ScalarBaseMult(aInt)
 	return point
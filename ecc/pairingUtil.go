@@ -1,5 +0,0 @@
-package ecc
-
-type PairingUtil struct {
-	Curve
-}
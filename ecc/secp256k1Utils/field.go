@@ -0,0 +1,144 @@
+package secp256k1Utils
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+// fieldElement is an element of GF(p) in four little-endian 64 bits limbs,
+// always reduced below p. Its arithmetic has no branch nor memory access
+// that depends on the values, it backs the constant time scalar
+// multiplications.
+type fieldElement [4]uint64
+
+// fieldC is 2^256 - p = 2^32 + 977, so 2^256 = fieldC mod p
+const fieldC = 0x1000003D1
+
+var fieldP = fieldElement{0xFFFFFFFEFFFFFC2F, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF}
+
+// feFromBig returns v mod p
+func feFromBig(v *big.Int) fieldElement {
+	var b [32]byte
+	new(big.Int).Mod(v, secp256k1.params.P).FillBytes(b[:])
+	var res fieldElement
+	for i := range res {
+		res[i] = binary.BigEndian.Uint64(b[24-8*i:])
+	}
+	return res
+}
+
+func (a *fieldElement) toBig() *big.Int {
+	var b [32]byte
+	for i, l := range a {
+		binary.BigEndian.PutUint64(b[24-8*i:], l)
+	}
+	return new(big.Int).SetBytes(b[:])
+}
+
+// reduce returns carry 2^256 + a mod p for a value below 2p
+func (a *fieldElement) reduce(carry uint64) fieldElement {
+	var t fieldElement
+	var borrow uint64
+	t[0], borrow = bits.Sub64(a[0], fieldP[0], 0)
+	t[1], borrow = bits.Sub64(a[1], fieldP[1], borrow)
+	t[2], borrow = bits.Sub64(a[2], fieldP[2], borrow)
+	t[3], borrow = bits.Sub64(a[3], fieldP[3], borrow)
+	// a - p when a >= p, that is when the sum carried or nothing was borrowed
+	mask := -(carry | (borrow ^ 1))
+	var res fieldElement
+	for i := range res {
+		res[i] = t[i]&mask | a[i]&^mask
+	}
+	return res
+}
+
+func feAdd(a, b *fieldElement) fieldElement {
+	var s fieldElement
+	var carry uint64
+	s[0], carry = bits.Add64(a[0], b[0], 0)
+	s[1], carry = bits.Add64(a[1], b[1], carry)
+	s[2], carry = bits.Add64(a[2], b[2], carry)
+	s[3], carry = bits.Add64(a[3], b[3], carry)
+	return s.reduce(carry)
+}
+
+func feSub(a, b *fieldElement) fieldElement {
+	var d fieldElement
+	var borrow uint64
+	d[0], borrow = bits.Sub64(a[0], b[0], 0)
+	d[1], borrow = bits.Sub64(a[1], b[1], borrow)
+	d[2], borrow = bits.Sub64(a[2], b[2], borrow)
+	d[3], borrow = bits.Sub64(a[3], b[3], borrow)
+	// add p back when a < b
+	mask := -borrow
+	var carry uint64
+	d[0], carry = bits.Add64(d[0], fieldP[0]&mask, 0)
+	d[1], carry = bits.Add64(d[1], fieldP[1]&mask, carry)
+	d[2], carry = bits.Add64(d[2], fieldP[2]&mask, carry)
+	d[3], _ = bits.Add64(d[3], fieldP[3]&mask, carry)
+	return d
+}
+
+func feMul(a, b *fieldElement) fieldElement {
+	// schoolbook product in 8 limbs
+	var r [8]uint64
+	for i := 0; i < 4; i++ {
+		var carry uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(a[i], b[j])
+			var c uint64
+			lo, c = bits.Add64(lo, r[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			r[i+j], carry = lo, hi
+		}
+		r[i+4] = carry
+	}
+	// hi 2^256 + lo = hi fieldC + lo, in 5 limbs
+	var t [5]uint64
+	var carry uint64
+	for i := 0; i < 4; i++ {
+		hi, lo := bits.Mul64(r[4+i], fieldC)
+		var c uint64
+		lo, c = bits.Add64(lo, r[i], 0)
+		hi += c
+		lo, c = bits.Add64(lo, carry, 0)
+		hi += c
+		t[i], carry = lo, hi
+	}
+	t[4] = carry
+	// fold the fifth limb once more, the result is below 2^256 + 2^67
+	hi, lo := bits.Mul64(t[4], fieldC)
+	var s fieldElement
+	s[0], carry = bits.Add64(t[0], lo, 0)
+	s[1], carry = bits.Add64(t[1], hi, carry)
+	s[2], carry = bits.Add64(t[2], 0, carry)
+	s[3], carry = bits.Add64(t[3], 0, carry)
+	return s.reduce(carry)
+}
+
+// feInverse returns a^{p-2}, the inverse of a != 0, the exponent is public
+func feInverse(a *fieldElement) fieldElement {
+	e := new(big.Int).Sub(secp256k1.params.P, big.NewInt(2))
+	res := fieldElement{1}
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res = feMul(&res, &res)
+		if e.Bit(i) == 1 {
+			res = feMul(&res, a)
+		}
+	}
+	return res
+}
+
+// feSelect sets a to b when mask is all ones and leaves it when mask is zero
+func feSelect(a, b *fieldElement, mask uint64) {
+	for i := range a {
+		a[i] = b[i]&mask | a[i]&^mask
+	}
+}
+
+func (a *fieldElement) isZero() bool {
+	return a[0]|a[1]|a[2]|a[3] == 0
+}
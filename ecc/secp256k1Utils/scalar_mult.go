@@ -0,0 +1,114 @@
+package secp256k1Utils
+
+import "math/big"
+
+// projective is (X : Y : Z) for the affine point (X/Z, Y/Z), the identity is
+// (0 : 1 : 0). It only serves the constant time scalar multiplications,
+// with the complete formulas of Renes, Costello and Batina
+// (https://eprint.iacr.org/2015/1060) which have no exceptional case.
+type projective struct {
+	x, y, z fieldElement
+}
+
+// feB3 is 3 b = 21
+var feB3 = fieldElement{21}
+
+func projectiveIdentity() projective {
+	return projective{y: fieldElement{1}}
+}
+
+// toProjective maps the affine (0, 0) of crypto/elliptic to the identity
+func toProjective(x, y *big.Int) projective {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return projectiveIdentity()
+	}
+	return projective{x: feFromBig(x), y: feFromBig(y), z: fieldElement{1}}
+}
+
+func (a *projective) toAffine() (x, y *big.Int) {
+	if a.z.isZero() {
+		return new(big.Int), new(big.Int)
+	}
+	zInv := feInverse(&a.z)
+	ax, ay := feMul(&a.x, &zInv), feMul(&a.y, &zInv)
+	return ax.toBig(), ay.toBig()
+}
+
+// projectiveAdd returns a + b, algorithm 7 of Renes, Costello and Batina for
+// a = 0, which also doubles
+func projectiveAdd(a, b *projective) projective {
+	t0 := feMul(&a.x, &b.x)
+	t1 := feMul(&a.y, &b.y)
+	t2 := feMul(&a.z, &b.z)
+	t3 := feAdd(&a.x, &a.y)
+	t4 := feAdd(&b.x, &b.y)
+	t3 = feMul(&t3, &t4)
+	t4 = feAdd(&t0, &t1)
+	t3 = feSub(&t3, &t4)
+	t4 = feAdd(&a.y, &a.z)
+	x3 := feAdd(&b.y, &b.z)
+	t4 = feMul(&t4, &x3)
+	x3 = feAdd(&t1, &t2)
+	t4 = feSub(&t4, &x3)
+	x3 = feAdd(&a.x, &a.z)
+	y3 := feAdd(&b.x, &b.z)
+	x3 = feMul(&x3, &y3)
+	y3 = feAdd(&t0, &t2)
+	y3 = feSub(&x3, &y3)
+	x3 = feAdd(&t0, &t0)
+	t0 = feAdd(&x3, &t0)
+	t2 = feMul(&feB3, &t2)
+	z3 := feAdd(&t1, &t2)
+	t1 = feSub(&t1, &t2)
+	y3 = feMul(&feB3, &y3)
+	x3 = feMul(&t4, &y3)
+	t2 = feMul(&t3, &t1)
+	x3 = feSub(&t2, &x3)
+	y3 = feMul(&y3, &t0)
+	t1 = feMul(&t1, &z3)
+	y3 = feAdd(&t1, &y3)
+	t0 = feMul(&t0, &t3)
+	z3 = feMul(&z3, &t4)
+	z3 = feAdd(&z3, &t0)
+	return projective{x3, y3, z3}
+}
+
+// projectiveSelect sets a to b when mask is all ones
+func projectiveSelect(a, b *projective, mask uint64) {
+	feSelect(&a.x, &b.x, mask)
+	feSelect(&a.y, &b.y, mask)
+	feSelect(&a.z, &b.z, mask)
+}
+
+// scalarMultConstantTime returns k a for the big-endian k with a fixed window
+// of 4 bits: the same sequence of operations runs for every k of 32 bytes
+// and the digits select the multiples of a by masks, not by indices
+func scalarMultConstantTime(a *projective, k []byte) projective {
+	if len(k) > 32 {
+		k = new(big.Int).Mod(new(big.Int).SetBytes(k), N).Bytes()
+	}
+	var scalar [32]byte
+	copy(scalar[32-len(k):], k)
+	var table [16]projective
+	table[0] = projectiveIdentity()
+	for i := 1; i < len(table); i++ {
+		table[i] = projectiveAdd(&table[i-1], a)
+	}
+	res := projectiveIdentity()
+	for _, b := range scalar {
+		for _, digit := range [2]uint64{uint64(b >> 4), uint64(b & 15)} {
+			for i := 0; i < 4; i++ {
+				res = projectiveAdd(&res, &res)
+			}
+			multiple := projectiveIdentity()
+			for i := range table {
+				// all ones when i = digit
+				x := uint64(i) ^ digit
+				mask := -(1 ^ (x|-x)>>63)
+				projectiveSelect(&multiple, &table[i], mask)
+			}
+			res = projectiveAdd(&res, &multiple)
+		}
+	}
+	return res
+}
@@ -0,0 +1,321 @@
+// Package secp256k1Utils implements secp256k1 (SEC 2), y^2 = x^3 + 7, as an
+// elliptic.Curve, so that crypto/ecdsa and the signers of this project run on
+// it. ScalarMult and ScalarBaseMult, which see secret keys and nonces, run in
+// constant time on fixed-size limbs. Add, Double and MultiScalarMult are in
+// Jacobian coordinates on math/big and are not constant time, they are meant
+// for public values such as the verification of signatures.
+package secp256k1Utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"math/big"
+	"math/bits"
+)
+
+type CurvePoint = ecdsa.PublicKey
+
+// Curve is secp256k1, a = 0 and the generic methods of elliptic.CurveParams,
+// which assume a = -3, do not apply
+type Curve struct {
+	params *elliptic.CurveParams
+}
+
+var (
+	secp256k1 = newCurve()
+	N         = secp256k1.params.N
+)
+
+func newCurve() *Curve {
+	hexInt := func(s string) *big.Int {
+		v, _ := new(big.Int).SetString(s, 16)
+		return v
+	}
+	return &Curve{params: &elliptic.CurveParams{
+		Name:    "secp256k1",
+		BitSize: 256,
+		P:       hexInt("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F"),
+		N:       hexInt("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141"),
+		B:       big.NewInt(7),
+		Gx:      hexInt("79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798"),
+		Gy:      hexInt("483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8"),
+	}}
+}
+
+// S256 returns secp256k1
+func S256() elliptic.Curve {
+	return secp256k1
+}
+
+func (c *Curve) Params() *elliptic.CurveParams {
+	return c.params
+}
+
+// polynomial returns x^3 + 7 mod p
+func (c *Curve) polynomial(x *big.Int) *big.Int {
+	y2 := new(big.Int).Mul(x, x)
+	y2.Mul(y2, x)
+	y2.Add(y2, c.params.B)
+	return y2.Mod(y2, c.params.P)
+}
+
+func (c *Curve) IsOnCurve(x, y *big.Int) bool {
+	p := c.params.P
+	if x.Sign() < 0 || x.Cmp(p) >= 0 || y.Sign() < 0 || y.Cmp(p) >= 0 {
+		return false
+	}
+	y2 := new(big.Int).Mul(y, y)
+	return y2.Mod(y2, p).Cmp(c.polynomial(x)) == 0
+}
+
+// jacobian is (X, Y, Z) for the affine point (X/Z^2, Y/Z^3), Z = 0 is infinity
+type jacobian struct {
+	x, y, z *big.Int
+}
+
+// toJacobian maps the affine (0, 0) of crypto/elliptic to infinity
+func toJacobian(x, y *big.Int) *jacobian {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return &jacobian{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	return &jacobian{new(big.Int).Set(x), new(big.Int).Set(y), big.NewInt(1)}
+}
+
+func (c *Curve) toAffine(a *jacobian) (x, y *big.Int) {
+	if a.z.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+	p := c.params.P
+	zInv := new(big.Int).ModInverse(a.z, p)
+	zInv2 := new(big.Int).Mul(zInv, zInv)
+	x = new(big.Int).Mul(a.x, zInv2)
+	x.Mod(x, p)
+	y = new(big.Int).Mul(a.y, zInv2.Mul(zInv2, zInv))
+	y.Mod(y, p)
+	return x, y
+}
+
+// double returns 2a, dbl-2009-l of the Explicit-Formulas Database
+func (c *Curve) double(a *jacobian) *jacobian {
+	p := c.params.P
+	if a.z.Sign() == 0 || a.y.Sign() == 0 {
+		return &jacobian{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	A := new(big.Int).Mul(a.x, a.x)
+	B := new(big.Int).Mul(a.y, a.y)
+	B.Mod(B, p)
+	C := new(big.Int).Mul(B, B)
+	// D = 2((X + B)^2 - A - C)
+	D := new(big.Int).Add(a.x, B)
+	D.Mul(D, D)
+	D.Sub(D, A)
+	D.Sub(D, C)
+	D.Lsh(D, 1)
+	D.Mod(D, p)
+	E := A.Mul(A, big.NewInt(3))
+	E.Mod(E, p)
+	F := new(big.Int).Mul(E, E)
+	x3 := F.Sub(F, new(big.Int).Lsh(D, 1))
+	x3.Mod(x3, p)
+	y3 := new(big.Int).Sub(D, x3)
+	y3.Mul(y3, E)
+	y3.Sub(y3, C.Lsh(C, 3))
+	y3.Mod(y3, p)
+	z3 := new(big.Int).Mul(a.y, a.z)
+	z3.Lsh(z3, 1)
+	z3.Mod(z3, p)
+	return &jacobian{x3, y3, z3}
+}
+
+// add returns a + b, add-2007-bl of the Explicit-Formulas Database
+func (c *Curve) add(a, b *jacobian) *jacobian {
+	if a.z.Sign() == 0 {
+		return b
+	}
+	if b.z.Sign() == 0 {
+		return a
+	}
+	p := c.params.P
+	z1z1 := new(big.Int).Mul(a.z, a.z)
+	z1z1.Mod(z1z1, p)
+	z2z2 := new(big.Int).Mul(b.z, b.z)
+	z2z2.Mod(z2z2, p)
+	u1 := new(big.Int).Mul(a.x, z2z2)
+	u1.Mod(u1, p)
+	u2 := new(big.Int).Mul(b.x, z1z1)
+	u2.Mod(u2, p)
+	s1 := new(big.Int).Mul(a.y, b.z)
+	s1.Mul(s1, z2z2)
+	s1.Mod(s1, p)
+	s2 := new(big.Int).Mul(b.y, a.z)
+	s2.Mul(s2, z1z1)
+	s2.Mod(s2, p)
+	h := new(big.Int).Sub(u2, u1)
+	h.Mod(h, p)
+	r := new(big.Int).Sub(s2, s1)
+	r.Lsh(r, 1)
+	r.Mod(r, p)
+	if h.Sign() == 0 {
+		if r.Sign() == 0 {
+			return c.double(a)
+		}
+		return &jacobian{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	i := new(big.Int).Lsh(h, 1)
+	i.Mul(i, i)
+	i.Mod(i, p)
+	j := new(big.Int).Mul(h, i)
+	v := new(big.Int).Mul(u1, i)
+	// X3 = r^2 - J - 2V
+	x3 := new(big.Int).Mul(r, r)
+	x3.Sub(x3, j)
+	x3.Sub(x3, new(big.Int).Lsh(v, 1))
+	x3.Mod(x3, p)
+	// Y3 = r(V - X3) - 2 S1 J
+	y3 := v.Sub(v, x3)
+	y3.Mul(y3, r)
+	y3.Sub(y3, s1.Mul(s1, j).Lsh(s1, 1))
+	y3.Mod(y3, p)
+	// Z3 = ((Z1 + Z2)^2 - Z1Z1 - Z2Z2) H
+	z3 := new(big.Int).Add(a.z, b.z)
+	z3.Mul(z3, z3)
+	z3.Sub(z3, z1z1)
+	z3.Sub(z3, z2z2)
+	z3.Mul(z3, h)
+	z3.Mod(z3, p)
+	return &jacobian{x3, y3, z3}
+}
+
+func (c *Curve) Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int) {
+	return c.toAffine(c.add(toJacobian(x1, y1), toJacobian(x2, y2)))
+}
+
+func (c *Curve) Double(x1, y1 *big.Int) (x, y *big.Int) {
+	return c.toAffine(c.double(toJacobian(x1, y1)))
+}
+
+// ScalarMult returns k(x1, y1) for the big-endian k in constant time
+func (c *Curve) ScalarMult(x1, y1 *big.Int, k []byte) (x, y *big.Int) {
+	a := toProjective(x1, y1)
+	res := scalarMultConstantTime(&a, k)
+	return res.toAffine()
+}
+
+// ScalarBaseMult returns kG for the big-endian k in constant time
+func (c *Curve) ScalarBaseMult(k []byte) (x, y *big.Int) {
+	return c.ScalarMult(c.params.Gx, c.params.Gy, k)
+}
+
+// Unmarshal decodes an uncompressed point, crypto/elliptic calls it instead
+// of its generic code for a = -3
+func (c *Curve) Unmarshal(data []byte) (x, y *big.Int) {
+	byteLen := (c.params.BitSize + 7) / 8
+	if len(data) != 1+2*byteLen || data[0] != 4 {
+		return nil, nil
+	}
+	x = new(big.Int).SetBytes(data[1 : 1+byteLen])
+	y = new(big.Int).SetBytes(data[1+byteLen:])
+	if !c.IsOnCurve(x, y) {
+		return nil, nil
+	}
+	return x, y
+}
+
+// UnmarshalCompressed decodes a compressed point, see Unmarshal
+func (c *Curve) UnmarshalCompressed(data []byte) (x, y *big.Int) {
+	byteLen := (c.params.BitSize + 7) / 8
+	if len(data) != 1+byteLen || (data[0] != 2 && data[0] != 3) {
+		return nil, nil
+	}
+	p := c.params.P
+	x = new(big.Int).SetBytes(data[1:])
+	if x.Cmp(p) >= 0 {
+		return nil, nil
+	}
+	// p = 3 mod 4, y = (x^3 + 7)^{(p+1)/4}
+	y2 := c.polynomial(x)
+	e := new(big.Int).Add(p, big.NewInt(1))
+	y = new(big.Int).Exp(y2, e.Rsh(e, 2), p)
+	if new(big.Int).Mod(new(big.Int).Mul(y, y), p).Cmp(y2) != 0 {
+		return nil, nil
+	}
+	if byte(y.Bit(0)) != data[0]&1 {
+		y.Sub(p, y)
+	}
+	return x, y
+}
+
+func ScalarBaseMult(a *big.Int) *CurvePoint {
+	x, y := secp256k1.ScalarBaseMult(a.Bytes())
+	return &CurvePoint{Curve: secp256k1, X: x, Y: y}
+}
+
+func ScalarMult(a *CurvePoint, b *big.Int) *CurvePoint {
+	x, y := secp256k1.ScalarMult(a.X, a.Y, b.Bytes())
+	return &CurvePoint{Curve: secp256k1, X: x, Y: y}
+}
+
+func ScalarAdd(a, b *CurvePoint) *CurvePoint {
+	x, y := secp256k1.Add(a.X, a.Y, b.X, b.Y)
+	return &CurvePoint{Curve: secp256k1, X: x, Y: y}
+}
+
+func Marshal(a *CurvePoint) []byte {
+	return elliptic.Marshal(secp256k1, a.X, a.Y)
+}
+
+func GetBaseGenerator() *CurvePoint {
+	params := secp256k1.params
+	return &CurvePoint{Curve: secp256k1, X: new(big.Int).Set(params.Gx), Y: new(big.Int).Set(params.Gy)}
+}
+
+// windowSize returns the bits of the digits of MultiScalarMult for n points
+func windowSize(n int) int {
+	if n < 32 {
+		return 4
+	}
+	return bits.Len(uint(n)) - 2
+}
+
+// MultiScalarMult returns sum scalars[i] points[i] with the bucket method of
+// Pippenger: per window of c bits, the points are added to the bucket of their
+// digit and the buckets summed as sum_j j B_j with running sums
+func MultiScalarMult(points []*CurvePoint, scalars []*big.Int) *CurvePoint {
+	c := windowSize(len(points))
+	jac := make([]*jacobian, len(points))
+	ks := make([]*big.Int, len(points))
+	for i := range points {
+		jac[i] = toJacobian(points[i].X, points[i].Y)
+		ks[i] = new(big.Int).Mod(scalars[i], N)
+	}
+	infinity := func() *jacobian {
+		return &jacobian{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	res := infinity()
+	buckets := make([]*jacobian, 1<<uint(c)-1)
+	for w := (N.BitLen()+c-1)/c - 1; w >= 0; w-- {
+		for i := 0; i < c; i++ {
+			res = secp256k1.double(res)
+		}
+		for j := range buckets {
+			buckets[j] = infinity()
+		}
+		for i, k := range ks {
+			d := 0
+			for b := c - 1; b >= 0; b-- {
+				d = d<<1 | int(k.Bit(w*c+b))
+			}
+			if d > 0 {
+				buckets[d-1] = secp256k1.add(buckets[d-1], jac[i])
+			}
+		}
+		running, sum := infinity(), infinity()
+		for j := len(buckets) - 1; j >= 0; j-- {
+			running = secp256k1.add(running, buckets[j])
+			sum = secp256k1.add(sum, running)
+		}
+		res = secp256k1.add(res, sum)
+	}
+	x, y := secp256k1.toAffine(res)
+	return &CurvePoint{Curve: secp256k1, X: x, Y: y}
+}
@@ -0,0 +1,232 @@
+package secp256k1Utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+)
+
+func hexInt(s string) *big.Int {
+	v, _ := new(big.Int).SetString(s, 16)
+	return v
+}
+
+func TestCurve_ScalarBaseMult(t *testing.T) {
+	vectors := []struct {
+		k    int64
+		x, y string
+	}{
+		{
+			2,
+			"C6047F9441ED7D6D3045406E95C07CD85C778E4B8CEF3CA7ABAC09B95C709EE5",
+			"1AE168FEA63DC339A3C58419466CEAEEF7F632653266D0E1236431A950CFE52A",
+		},
+		{
+			3,
+			"F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+			"388F7B0F632DE8140FE337E62A37F3566500A99934C2231B6CB9FD7584B8E672",
+		},
+	}
+	c := S256()
+	for _, v := range vectors {
+		p := ScalarBaseMult(big.NewInt(v.k))
+		if p.X.Cmp(hexInt(v.x)) != 0 || p.Y.Cmp(hexInt(v.y)) != 0 {
+			t.Errorf("%dG not match", v.k)
+		}
+		if !c.IsOnCurve(p.X, p.Y) {
+			t.Errorf("%dG not on curve", v.k)
+		}
+	}
+	// 2G + G == G + G + G
+	g := ScalarBaseMult(big.NewInt(1))
+	x, y := c.Double(g.X, g.Y)
+	x, y = c.Add(x, y, g.X, g.Y)
+	if x.Cmp(hexInt(vectors[1].x)) != 0 || y.Cmp(hexInt(vectors[1].y)) != 0 {
+		t.Error("2G + G not match")
+	}
+	// nG is infinity and (n-1)G = -G
+	if x, y := c.ScalarBaseMult(N.Bytes()); x.Sign() != 0 || y.Sign() != 0 {
+		t.Error("nG is not infinity")
+	}
+	minusG := ScalarBaseMult(new(big.Int).Sub(N, big.NewInt(1)))
+	if minusG.X.Cmp(g.X) != 0 || new(big.Int).Add(minusG.Y, g.Y).Cmp(c.Params().P) != 0 {
+		t.Error("(n-1)G is not -G")
+	}
+	if x, y := c.Add(g.X, g.Y, minusG.X, minusG.Y); x.Sign() != 0 || y.Sign() != 0 {
+		t.Error("G - G is not infinity")
+	}
+}
+
+// doubleAndAdd is the variable time k a on the Jacobian arithmetic, the
+// reference of the constant time ScalarMult
+func doubleAndAdd(a *CurvePoint, k *big.Int) (x, y *big.Int) {
+	res := &jacobian{new(big.Int), new(big.Int), new(big.Int)}
+	p := toJacobian(a.X, a.Y)
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		res = secp256k1.double(res)
+		if k.Bit(i) == 1 {
+			res = secp256k1.add(res, p)
+		}
+	}
+	return secp256k1.toAffine(res)
+}
+
+func TestCurve_ScalarMult(t *testing.T) {
+	c := S256()
+	a := ScalarBaseMult(big.NewInt(12345))
+	ks := []*big.Int{
+		big.NewInt(0), big.NewInt(1), big.NewInt(15), big.NewInt(16),
+		new(big.Int).Sub(N, big.NewInt(1)), N, new(big.Int).Add(N, big.NewInt(5)),
+		// above 2^256, reduced modulo n
+		new(big.Int).Lsh(big.NewInt(3), 260),
+	}
+	for i := 0; i < 16; i++ {
+		k, err := rand.Int(rand.Reader, N)
+		if err != nil {
+			panic(err)
+		}
+		ks = append(ks, k)
+	}
+	for _, k := range ks {
+		x, y := c.ScalarMult(a.X, a.Y, k.Bytes())
+		ex, ey := doubleAndAdd(a, k)
+		if x.Cmp(ex) != 0 || y.Cmp(ey) != 0 {
+			t.Errorf("[%x]a not match double and add", k)
+		}
+	}
+	// the identity stays the identity
+	if x, y := c.ScalarMult(new(big.Int), new(big.Int), big.NewInt(7).Bytes()); x.Sign() != 0 || y.Sign() != 0 {
+		t.Error("7 infinity is not infinity")
+	}
+}
+
+func TestFieldElement(t *testing.T) {
+	p := secp256k1.params.P
+	values := []*big.Int{big.NewInt(0), big.NewInt(1), new(big.Int).Sub(p, big.NewInt(1)), new(big.Int).Sub(p, big.NewInt(fieldC))}
+	for i := 0; i < 16; i++ {
+		v, err := rand.Int(rand.Reader, p)
+		if err != nil {
+			panic(err)
+		}
+		values = append(values, v)
+	}
+	for _, a := range values {
+		for _, b := range values {
+			fa, fb := feFromBig(a), feFromBig(b)
+			sum, diff, prod := feAdd(&fa, &fb), feSub(&fa, &fb), feMul(&fa, &fb)
+			if sum.toBig().Cmp(new(big.Int).Mod(new(big.Int).Add(a, b), p)) != 0 {
+				t.Errorf("%x + %x not match", a, b)
+			}
+			if diff.toBig().Cmp(new(big.Int).Mod(new(big.Int).Sub(a, b), p)) != 0 {
+				t.Errorf("%x - %x not match", a, b)
+			}
+			if prod.toBig().Cmp(new(big.Int).Mod(new(big.Int).Mul(a, b), p)) != 0 {
+				t.Errorf("%x * %x not match", a, b)
+			}
+		}
+		if fa := feFromBig(a); a.Sign() != 0 {
+			inv := feInverse(&fa)
+			if inv.toBig().Cmp(new(big.Int).ModInverse(a, p)) != 0 {
+				t.Errorf("inverse of %x not match", a)
+			}
+		}
+	}
+}
+
+func TestCurve_Unmarshal(t *testing.T) {
+	c := S256()
+	k, _ := rand.Int(rand.Reader, N)
+	p := ScalarBaseMult(k)
+	x, y := elliptic.Unmarshal(c, Marshal(p))
+	if x == nil || x.Cmp(p.X) != 0 || y.Cmp(p.Y) != 0 {
+		t.Error("Unmarshal not match")
+	}
+	x, y = elliptic.UnmarshalCompressed(c, elliptic.MarshalCompressed(c, p.X, p.Y))
+	if x == nil || x.Cmp(p.X) != 0 || y.Cmp(p.Y) != 0 {
+		t.Error("UnmarshalCompressed not match")
+	}
+	bad := Marshal(p)
+	bad[len(bad)-1] ^= 1
+	if x, _ := elliptic.Unmarshal(c, bad); x != nil {
+		t.Error("point off the curve accepted")
+	}
+}
+
+func TestCurve_ECDSA(t *testing.T) {
+	sk, err := ecdsa.GenerateKey(S256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	digest := sha256.Sum256([]byte("message"))
+	sig, err := ecdsa.SignASN1(rand.Reader, sk, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	if !ecdsa.VerifyASN1(&sk.PublicKey, digest[:], sig) {
+		t.Error("signature not verified")
+	}
+	other := sha256.Sum256([]byte("another message"))
+	if ecdsa.VerifyASN1(&sk.PublicKey, other[:], sig) {
+		t.Error("signature of another message verified")
+	}
+}
+
+func TestMultiScalarMult(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 31, 70} {
+		points := make([]*CurvePoint, n)
+		scalars := make([]*big.Int, n)
+		expected := &CurvePoint{Curve: S256(), X: new(big.Int), Y: new(big.Int)}
+		for i := range points {
+			k, _ := rand.Int(rand.Reader, N)
+			points[i] = ScalarBaseMult(k)
+			scalars[i], _ = rand.Int(rand.Reader, N)
+			if i == 1 {
+				// the same point twice exercises the doubling of the buckets
+				points[i] = points[0]
+				scalars[i] = scalars[0]
+			}
+			expected = ScalarAdd(expected, ScalarMult(points[i], scalars[i]))
+		}
+		res := MultiScalarMult(points, scalars)
+		if res.X.Cmp(expected.X) != 0 || res.Y.Cmp(expected.Y) != 0 {
+			t.Errorf("%d points: MultiScalarMult not match", n)
+		}
+	}
+}
+
+func BenchmarkMultiScalarMult(b *testing.B) {
+	const n = 128
+	points := make([]*CurvePoint, n)
+	scalars := make([]*big.Int, n)
+	for i := range points {
+		k, _ := rand.Int(rand.Reader, N)
+		points[i] = ScalarBaseMult(k)
+		scalars[i], _ = rand.Int(rand.Reader, N)
+	}
+	b.Run("Pippenger", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			MultiScalarMult(points, scalars)
+		}
+	})
+	b.Run("Naive", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res := ScalarMult(points[0], scalars[0])
+			for j := 1; j < n; j++ {
+				res = ScalarAdd(res, ScalarMult(points[j], scalars[j]))
+			}
+		}
+	})
+}
+
+func BenchmarkCurve_ScalarBaseMult(b *testing.B) {
+	k, err := rand.Int(rand.Reader, N)
+	if err != nil {
+		panic(err)
+	}
+	for i := 0; i < b.N; i++ {
+		ScalarBaseMult(k)
+	}
+}
@@ -0,0 +1,140 @@
+// Package paillier implements the Paillier cryptosystem with generator N + 1:
+// ciphertexts are additively homomorphic, Enc(a) * Enc(b) = Enc(a + b) and
+// Enc(a)^k = Enc(k a), modulo N.
+package paillier
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// PublicKey is a Paillier public key
+type PublicKey struct {
+	N       *big.Int
+	NSquare *big.Int
+}
+
+// PrivateKey is a Paillier private key, phi = (p-1)(q-1) and mu = phi^{-1}
+// modulo N
+type PrivateKey struct {
+	PublicKey
+	Phi *big.Int
+	Mu  *big.Int
+}
+
+var (
+	one = big.NewInt(1)
+
+	errKeySize    = errors.New("paillier modulus is too small")
+	errPlaintext  = errors.New("plaintext out of range")
+	errCiphertext = errors.New("invalid paillier ciphertext")
+	errNonce      = errors.New("paillier nonce is not a unit")
+)
+
+// NewPublicKey returns the public key of modulus n
+func NewPublicKey(n *big.Int) *PublicKey {
+	return &PublicKey{N: new(big.Int).Set(n), NSquare: new(big.Int).Mul(n, n)}
+}
+
+// GenerateKey returns a key whose modulus of bits bits is the product of two
+// primes of half size drawn from random, crypto/rand if random is nil
+func GenerateKey(random io.Reader, bits int) (*PrivateKey, error) {
+	if bits < 64 {
+		return nil, errKeySize
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+	for {
+		p, err := rand.Prime(random, bits/2)
+		if err != nil {
+			return nil, err
+		}
+		q, err := rand.Prime(random, bits-bits/2)
+		if err != nil {
+			return nil, err
+		}
+		if p.Cmp(q) == 0 {
+			continue
+		}
+		n := new(big.Int).Mul(p, q)
+		// primes of the same size give gcd(n, phi) = 1
+		phi := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+		mu := new(big.Int).ModInverse(phi, n)
+		if n.BitLen() != bits || mu == nil {
+			continue
+		}
+		return &PrivateKey{PublicKey: *NewPublicKey(n), Phi: phi, Mu: mu}, nil
+	}
+}
+
+// RandomNonce returns a random element of Z*_N drawn from random,
+// crypto/rand if random is nil
+func (pk *PublicKey) RandomNonce(random io.Reader) (*big.Int, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	for {
+		r, err := rand.Int(random, pk.N)
+		if err != nil {
+			return nil, err
+		}
+		if r.Sign() > 0 && new(big.Int).GCD(nil, nil, r, pk.N).Cmp(one) == 0 {
+			return r, nil
+		}
+	}
+}
+
+// Encrypt returns (1 + m N) r^N mod N^2 for a random r, 0 <= m < N
+func (pk *PublicKey) Encrypt(m *big.Int) (*big.Int, error) {
+	r, err := pk.RandomNonce(nil)
+	if err != nil {
+		return nil, err
+	}
+	return pk.EncryptWithNonce(m, r)
+}
+
+// EncryptWithNonce returns (1 + m N) r^N mod N^2, 0 <= m < N and r in Z*_N
+func (pk *PublicKey) EncryptWithNonce(m, r *big.Int) (*big.Int, error) {
+	if m.Sign() < 0 || m.Cmp(pk.N) >= 0 {
+		return nil, errPlaintext
+	}
+	if r.Sign() <= 0 || r.Cmp(pk.N) >= 0 || new(big.Int).GCD(nil, nil, r, pk.N).Cmp(one) != 0 {
+		return nil, errNonce
+	}
+	c := new(big.Int).Mul(m, pk.N)
+	c.Add(c, one)
+	c.Mul(c, new(big.Int).Exp(r, pk.N, pk.NSquare))
+	return c.Mod(c, pk.NSquare), nil
+}
+
+// IsCiphertext reports whether c is in Z*_{N^2}
+func (pk *PublicKey) IsCiphertext(c *big.Int) bool {
+	return c != nil && c.Sign() > 0 && c.Cmp(pk.NSquare) < 0 &&
+		new(big.Int).GCD(nil, nil, c, pk.N).Cmp(one) == 0
+}
+
+// Add returns a ciphertext of the sum of the plaintexts of a and b
+func (pk *PublicKey) Add(a, b *big.Int) *big.Int {
+	c := new(big.Int).Mul(a, b)
+	return c.Mod(c, pk.NSquare)
+}
+
+// MulConst returns a ciphertext of k times the plaintext of c, k >= 0
+func (pk *PublicKey) MulConst(c, k *big.Int) *big.Int {
+	return new(big.Int).Exp(c, k, pk.NSquare)
+}
+
+// Decrypt returns L(c^phi mod N^2) mu mod N with L(u) = (u - 1) / N
+func (sk *PrivateKey) Decrypt(c *big.Int) (*big.Int, error) {
+	if !sk.IsCiphertext(c) {
+		return nil, errCiphertext
+	}
+	u := new(big.Int).Exp(c, sk.Phi, sk.NSquare)
+	u.Sub(u, one)
+	u.Div(u, sk.N)
+	u.Mul(u, sk.Mu)
+	return u.Mod(u, sk.N), nil
+}
@@ -0,0 +1,143 @@
+package paillier
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestPrivateKey_Decrypt(t *testing.T) {
+	sk, err := GenerateKey(nil, 1024)
+	if err != nil {
+		panic(err)
+	}
+	if sk.N.BitLen() != 1024 {
+		t.Errorf("modulus of %d bits", sk.N.BitLen())
+	}
+	pk := NewPublicKey(sk.N)
+	for _, m := range []*big.Int{big.NewInt(0), big.NewInt(42), new(big.Int).Sub(sk.N, one)} {
+		c, err := pk.Encrypt(m)
+		if err != nil {
+			panic(err)
+		}
+		res, err := sk.Decrypt(c)
+		if err != nil {
+			panic(err)
+		}
+		if res.Cmp(m) != 0 {
+			t.Errorf("decryption of %v not match", m)
+		}
+	}
+	if _, err := pk.Encrypt(sk.N); err == nil {
+		t.Error("plaintext N encrypted")
+	}
+	if _, err := sk.Decrypt(sk.NSquare); err == nil {
+		t.Error("ciphertext N^2 decrypted")
+	}
+	// the encryption is randomized
+	a, _ := pk.Encrypt(big.NewInt(1))
+	b, _ := pk.Encrypt(big.NewInt(1))
+	if a.Cmp(b) == 0 {
+		t.Error("encryptions are equal")
+	}
+}
+
+func TestPublicKey_Homomorphic(t *testing.T) {
+	sk, err := GenerateKey(nil, 512)
+	if err != nil {
+		panic(err)
+	}
+	pk := &sk.PublicKey
+	a, b, k := big.NewInt(1234), big.NewInt(5678), big.NewInt(91)
+	ca, err := pk.Encrypt(a)
+	if err != nil {
+		panic(err)
+	}
+	cb, err := pk.Encrypt(b)
+	if err != nil {
+		panic(err)
+	}
+	// a k + b
+	c := pk.Add(pk.MulConst(ca, k), cb)
+	res, err := sk.Decrypt(c)
+	if err != nil {
+		panic(err)
+	}
+	expected := new(big.Int).Mul(a, k)
+	expected.Add(expected, b)
+	if res.Cmp(expected) != 0 {
+		t.Errorf("homomorphic result %v not match %v", res, expected)
+	}
+	// the sum wraps modulo N
+	cn, err := pk.Encrypt(new(big.Int).Sub(pk.N, one))
+	if err != nil {
+		panic(err)
+	}
+	res, err = sk.Decrypt(pk.Add(cn, ca))
+	if err != nil {
+		panic(err)
+	}
+	if res.Cmp(new(big.Int).Sub(a, one)) != 0 {
+		t.Error("sum not reduced modulo N")
+	}
+}
+
+func TestPrivateKey_ProveKey(t *testing.T) {
+	sk, err := GenerateKey(nil, 1024)
+	if err != nil {
+		panic(err)
+	}
+	context := []byte("test")
+	proof, err := sk.ProveKey(context)
+	if err != nil {
+		panic(err)
+	}
+	pk := NewPublicKey(sk.N)
+	if !pk.VerifyKey(context, proof) {
+		t.Error("proof of a valid key not verified")
+	}
+	if pk.VerifyKey([]byte("another"), proof) {
+		t.Error("proof verified in another context")
+	}
+	other, err := GenerateKey(nil, 1024)
+	if err != nil {
+		panic(err)
+	}
+	if NewPublicKey(other.N).VerifyKey(context, proof) {
+		t.Error("proof verified for another modulus")
+	}
+	// 3 p q is coprime to 2 (p - 1) (q - 1) when 3 divides neither p - 1 nor
+	// q - 1, its roots exist but the small factor is rejected
+	for {
+		k, err := GenerateKey(nil, 512)
+		if err != nil {
+			panic(err)
+		}
+		small := &PrivateKey{PublicKey: *NewPublicKey(new(big.Int).Mul(k.N, big.NewInt(3))), Phi: new(big.Int).Lsh(k.Phi, 1)}
+		smallProof, err := small.ProveKey(context)
+		if err != nil {
+			continue
+		}
+		if small.VerifyKey(context, smallProof) {
+			t.Error("modulus with a small factor verified")
+		}
+		break
+	}
+	// N = p^2 q is not coprime to phi(N), the N-th roots do not exist
+	p, err := GenerateKey(nil, 512)
+	if err != nil {
+		panic(err)
+	}
+	bad := &PrivateKey{PublicKey: *NewPublicKey(new(big.Int).Mul(p.N, p.N)), Phi: new(big.Int).Mul(p.N, p.Phi)}
+	if _, err := bad.ProveKey(context); err == nil {
+		t.Error("proof of a modulus not coprime to phi")
+	}
+	forged := make([]*big.Int, len(proof))
+	for i := range forged {
+		if forged[i], err = bad.RandomNonce(nil); err != nil {
+			panic(err)
+		}
+	}
+	if bad.VerifyKey(context, forged) || pk.VerifyKey(context, proof[1:]) {
+		t.Error("forged proof verified")
+	}
+}
@@ -0,0 +1,91 @@
+package paillier
+
+import (
+	"errors"
+	"math/big"
+
+	"scrypto/proof/transcript"
+)
+
+// Proof that N is a Paillier modulus, gcd(N, phi(N)) = 1, of Goldberg, Reyzin,
+// Sagga and Baldimtsi (https://eprint.iacr.org/2018/057): the prover gives the
+// N-th roots of keyProofRounds values derived from N, which all exist when
+// x -> x^N permutes Z*_N. Otherwise a prime p >= keyProofAlpha divides N and
+// phi(N), since N has no smaller factor, and at most 1/p of the values have a
+// root, so a cheating prover succeeds with probability below 2^{-128}. Then
+// Enc is a bijection from Z_N x Z*_N onto Z*_{N^2} and every ciphertext has a
+// single plaintext.
+
+const (
+	keyProofAlpha  = 6370
+	keyProofRounds = 11
+)
+
+var errKeyProof = errors.New("paillier modulus is not coprime to phi")
+
+// smallPrimes is the product of the primes below keyProofAlpha
+var smallPrimes = primorial(keyProofAlpha)
+
+func primorial(bound int) *big.Int {
+	res := big.NewInt(1)
+	composite := make([]bool, bound)
+	for i := 2; i < bound; i++ {
+		if composite[i] {
+			continue
+		}
+		res.Mul(res, big.NewInt(int64(i)))
+		for j := i * i; j < bound; j += i {
+			composite[j] = true
+		}
+	}
+	return res
+}
+
+// keyProofChallenges returns the values of Z_N whose N-th roots prove N
+func keyProofChallenges(n *big.Int, context []byte) []*big.Int {
+	t := transcript.New("paillier-key-proof")
+	t.AppendMessage("context", context)
+	t.AppendScalar("N", n)
+	res := make([]*big.Int, keyProofRounds)
+	for i := range res {
+		res[i] = t.ChallengeScalar("rho", n)
+	}
+	return res
+}
+
+// ProveKey returns the proof that the modulus of sk is coprime to phi(N),
+// context binds the proof to the protocol that uses the key
+func (sk *PrivateKey) ProveKey(context []byte) ([]*big.Int, error) {
+	// the N-th root of rho is rho^{N^{-1} mod phi}
+	d := new(big.Int).ModInverse(sk.N, sk.Phi)
+	if d == nil {
+		return nil, errKeyProof
+	}
+	res := keyProofChallenges(sk.N, context)
+	for i, rho := range res {
+		res[i] = rho.Exp(rho, d, sk.N)
+	}
+	return res, nil
+}
+
+// VerifyKey reports whether proof proves that N is coprime to phi(N) and has
+// no prime factor below keyProofAlpha
+func (pk *PublicKey) VerifyKey(context []byte, proof []*big.Int) bool {
+	n := pk.N
+	if n == nil || n.Sign() <= 0 || len(proof) != keyProofRounds {
+		return false
+	}
+	if new(big.Int).GCD(nil, nil, n, smallPrimes).Cmp(one) != 0 {
+		return false
+	}
+	for i, rho := range keyProofChallenges(n, context) {
+		sigma := proof[i]
+		if sigma == nil || sigma.Sign() <= 0 || sigma.Cmp(n) >= 0 {
+			return false
+		}
+		if new(big.Int).Exp(sigma, n, n).Cmp(rho) != 0 {
+			return false
+		}
+	}
+	return true
+}
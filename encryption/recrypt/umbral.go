@@ -1,18 +1,20 @@
 package recrypt
 
 import (
-	"bytes"
 	"crypto/ecdsa"
 	"crypto/elliptic"
-	"encoding/gob"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
+	"scrypto/dsa/ecdsa"
+	"scrypto/ecc/group"
 	"scrypto/ecc/p256Utils"
 	"scrypto/encryption/sherAes"
-	"scrypto/dsa/ecdsa"
+	"scrypto/proof/transcript"
 	sherMath "scrypto/smath"
 	"scrypto/sutils"
+	"scrypto/sutils/kdf"
 )
 
 type CurvePoint = ecdsa.PublicKey
@@ -20,22 +22,66 @@ type CurvePoint = ecdsa.PublicKey
 type Umbral struct {
 	curve elliptic.Curve
 	N     *big.Int
+	Hash  sutils.Hash // hash deriving the aes key of new capsules
 }
 
 type Capsule struct {
-	E *CurvePoint
-	V *CurvePoint
-	S *big.Int
+	E    *CurvePoint
+	V    *CurvePoint
+	S    *big.Int
+	Hash sutils.Hash // hash deriving the aes key, 0 means SHA-256
 }
 
+// size of an encoded capsule: hash id, E, V and S
+const capsuleSize = 1 + 65 + 65 + 32
+
 func NewRecryptCipher(curve elliptic.Curve) (recryptCipher *Umbral) {
 	recryptCipher = &Umbral{
 		curve: curve,
 		N:     curve.Params().N,
+		Hash:  sutils.SHA256,
 	}
 	return recryptCipher
 }
 
+// NewRecryptCipherWithHash creates a cipher deriving aes keys with h, the
+// capsules record h so that every party derives the same key
+func NewRecryptCipherWithHash(curve elliptic.Curve, h sutils.Hash) (recryptCipher *Umbral, err error) {
+	if !h.Available() {
+		return nil, errors.New("hash function is unavailable")
+	}
+	recryptCipher = NewRecryptCipher(curve)
+	recryptCipher.Hash = h
+	return recryptCipher, nil
+}
+
+// key schedule of the data encapsulation
+const (
+	kdfSalt = "scrypto/recrypt/umbral"
+	kdfInfo = "aes-256-gcm"
+	keySize = 32
+	ivSize  = 12
+)
+
+// deriveKey turns the shared point into key material with HKDF on the hash of
+// the capsule:
+//
+//	key, iv = kdf.DeriveKeysWithHash(h, Marshal(point), kdfSalt, kdfInfo, 32, 12)
+//
+// and returns key || iv. The point is (pk_A)^{e+v} for the sender and Alice,
+// and (E' * V')^d for Bob, so the three of them get the same key and iv.
+func (this *Umbral) deriveKey(capsule *Capsule, point *CurvePoint) (keyBytes []byte, err error) {
+	h := capsule.Hash
+	if h == 0 {
+		h = sutils.SHA256
+	}
+	keys, err := kdf.DeriveKeysWithHash(h, p256Utils.Marshal(point), []byte(kdfSalt), []byte(kdfInfo), keySize, ivSize)
+	if err != nil {
+		return nil, err
+	}
+	return append(keys[0], keys[1]...), nil
+}
+
 func (this *Umbral) encryptKeyGen(pubKey *ecdsa.PublicKey) (capsule *Capsule, keyBytes []byte, err error) {
 	// generate E,V key-pairs
 	ecdsaSigner := ecdsaUtils.NewECDSA(this.curve)
@@ -45,34 +91,50 @@ func (this *Umbral) encryptKeyGen(pubKey *ecdsa.PublicKey) (capsule *Capsule, ke
 		return nil, nil, err
 	}
 	// get H2(E || V)
-	EV := sutils.ContactBytes(p256Utils.Marshal(E), p256Utils.Marshal(V))
-	h := p256Utils.HashToCurve(EV)
-	if err != nil {
-		return nil, nil, err
-	}
+	h := this.h2(E, V)
 	// get s = v + e * H2(E || V)
 	s := sherMath.Add(v.D, sherMath.Mul(e.D, h, this.N), this.N)
 	// get (pk_A)^{e+v}
 	point := p256Utils.ScalarMult(pubKey, sherMath.Add(e.D, v.D, this.N))
+	capsule = &Capsule{
+		E:    E,
+		V:    V,
+		S:    s,
+		Hash: this.Hash,
+	}
 	// generate aes key
-	keyBytes, err = sutils.GetSha3HashBytes(p256Utils.Marshal(point))
+	keyBytes, err = this.deriveKey(capsule, point)
 	if err != nil {
 		return nil, nil, err
 	}
-	capsule = &Capsule{
-		E: E,
-		V: V,
-		S: s,
-	}
 	return capsule, keyBytes, nil
 }
 
-// Recreate aes key
+// H2(E || V), the challenge binding the capsule
+func (this *Umbral) h2(E, V *CurvePoint) *big.Int {
+	g := group.P256()
+	tr := transcript.New("scrypto/recrypt/H2")
+	tr.AppendPoint("E", g, E)
+	tr.AppendPoint("V", g, V)
+	return tr.ChallengeScalar("h", this.N)
+}
+
+// H3(X_A || pk_B || S), the scalar shared between delegator and delegatee
+func (this *Umbral) h3(X, pkB, S *CurvePoint) *big.Int {
+	g := group.P256()
+	tr := transcript.New("scrypto/recrypt/H3")
+	tr.AppendPoint("X", g, X)
+	tr.AppendPoint("pkB", g, pkB)
+	tr.AppendPoint("S", g, S)
+	return tr.ChallengeScalar("d", this.N)
+}
+
+// Recreate aes key and iv, as key || iv
 func (this *Umbral) RecreateAESKeyByMyPriKey(capsule *Capsule, aPriKey *ecdsa.PrivateKey) (keyBytes []byte, err error) {
 	point := p256Utils.ScalarAdd(capsule.E, capsule.V)
 	point = p256Utils.ScalarMult(point, aPriKey.D)
 	// generate aes key
-	keyBytes, err = sutils.GetSha3HashBytes(p256Utils.Marshal(point))
+	keyBytes, err = this.deriveKey(capsule, point)
 	if err != nil {
 		return nil, err
 	}
@@ -88,11 +150,13 @@ func (this *Umbral) RecreateAESKeyByMyPriKeyStr(capsule *Capsule, aPriKeyStr str
 }
 
 func (this *Umbral) EncryptMessageByAESKey(message []byte, keyBytes []byte, pubKey *ecdsa.PublicKey) (cipherText []byte, err error) {
-	// use aes gcm algorithm to encrypt
-	// mark keyBytes[:12] as nonce
+	if len(keyBytes) != keySize+ivSize {
+		return nil, errors.New("key size not match")
+	}
+	// use aes gcm algorithm to encrypt, pubKey as additional data
 	pubKeyBytes := p256Utils.Marshal(pubKey)
 	aesGCMCipher := sherAes.NewDefaultAesGCM()
-	cipherText, err = aesGCMCipher.Encrypt(message, keyBytes, pubKeyBytes, nil)
+	cipherText, err = aesGCMCipher.Encrypt(message, keyBytes[:keySize], keyBytes[keySize:], pubKeyBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -106,11 +170,9 @@ func (this *Umbral) Encrypt(message []byte, pubKey *ecdsa.PublicKey) (cipherText
 	if err != nil {
 		return nil, nil, err
 	}
-	fmt.Println("Origin key:", hex.EncodeToString(keyBytes))
-	// use aes gcm algorithm to encrypt
-	// mark keyBytes[:12] as nonce
+	// use aes gcm algorithm to encrypt with the derived key and iv
 	aesGCMCipher := sherAes.NewDefaultAesGCM()
-	cipherText, err = aesGCMCipher.Encrypt(message, keyBytes, keyBytes, nil)
+	cipherText, err = aesGCMCipher.Encrypt(message, keyBytes[:keySize], keyBytes[keySize:], nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -137,8 +199,7 @@ func (this *Umbral) ReKeyGen(aPriKey *ecdsa.PrivateKey, bPubKey *ecdsa.PublicKey
 	}
 	// get d = H3(X_A || pk_B || pk_B^{x_A})
 	Bx := p256Utils.ScalarMult(bPubKey, priX.D)
-	dPre := sutils.ContactBytes(p256Utils.Marshal(pubX), p256Utils.Marshal(bPubKey), p256Utils.Marshal(Bx))
-	d := p256Utils.HashToCurve(dPre)
+	d := this.h3(pubX, bPubKey, Bx)
 	// rk = sk_A * d^{-1}
 	rk = sherMath.Mul(aPriKey.D, sherMath.ModInverse(d, this.N), this.N)
 	return rk, pubX, nil
@@ -160,10 +221,7 @@ func (this *Umbral) ReKeyGenByStr(aPriKeyStr, bPubKeyStr string) (rk *big.Int, p
 func (this *Umbral) ReEncryption(rk *big.Int, capsule *Capsule) (newCapsule *Capsule, err error) {
 	// check g^s == V * E^{H2(E || V)}
 	S := p256Utils.ScalarBaseMult(capsule.S)
-	h2 := p256Utils.HashToCurve(
-		sutils.ContactBytes(
-			p256Utils.Marshal(capsule.E),
-			p256Utils.Marshal(capsule.V)))
+	h2 := this.h2(capsule.E, capsule.V)
 	Eh2 := p256Utils.ScalarMult(capsule.E, h2)
 	VEh2 := p256Utils.ScalarAdd(capsule.V, Eh2)
 	// if check failed return error
@@ -172,9 +230,10 @@ func (this *Umbral) ReEncryption(rk *big.Int, capsule *Capsule) (newCapsule *Cap
 	}
 	// E' = E^{rk}, V' = V^{rk}
 	newCapsule = &Capsule{
-		E: p256Utils.ScalarMult(capsule.E, rk),
-		V: p256Utils.ScalarMult(capsule.V, rk),
-		S: capsule.S,
+		E:    p256Utils.ScalarMult(capsule.E, rk),
+		V:    p256Utils.ScalarMult(capsule.V, rk),
+		S:    capsule.S,
+		Hash: capsule.Hash,
 	}
 	return newCapsule, nil
 }
@@ -182,13 +241,10 @@ func (this *Umbral) ReEncryption(rk *big.Int, capsule *Capsule) (newCapsule *Cap
 func (this *Umbral) decryptKeyGen(bPriKey *ecdsa.PrivateKey, capsule *Capsule, pubX *ecdsa.PublicKey) (keyBytes []byte, err error) {
 	// S = X_A^{sk_B}
 	S := p256Utils.ScalarMult(pubX, bPriKey.D)
-	SBytes := p256Utils.Marshal(S)
-	pubBBytes := p256Utils.Marshal(&bPriKey.PublicKey)
-	pubXBytes := p256Utils.Marshal(pubX)
 	// recreate d = H3(X_A || pk_B || S)
-	d := p256Utils.HashToCurve(sutils.ContactBytes(pubXBytes, pubBBytes, SBytes))
+	d := this.h3(pubX, &bPriKey.PublicKey, S)
 	point := p256Utils.ScalarMult(p256Utils.ScalarAdd(capsule.E, capsule.V), d)
-	keyBytes, err = sutils.GetSha3HashBytes(p256Utils.Marshal(point))
+	keyBytes, err = this.deriveKey(capsule, point)
 	if err != nil {
 		return nil, err
 	}
@@ -201,11 +257,10 @@ func (this *Umbral) Decrypt(bPriKey *ecdsa.PrivateKey, capsule *Capsule, pubX *e
 	if err != nil {
 		return nil, err
 	}
-	// recreate aes key = G((E' * V')^d)
+	// recreate aes key and iv = KDF((E' * V')^d)
 	// use aes gcm to decrypt
-	// mark keyBytes[:12] as nonce
 	aesGCMCipher := sherAes.NewDefaultAesGCM()
-	plainText, err = aesGCMCipher.Decrypt(cipherText, keyBytes, keyBytes, nil)
+	plainText, err = aesGCMCipher.Decrypt(cipherText, keyBytes[:keySize], keyBytes[keySize:], nil)
 	if err != nil {
 		return nil, err
 	}
@@ -230,10 +285,9 @@ func (this *Umbral) DecryptOnMyPriKey(aPriKey *ecdsa.PrivateKey, capsule *Capsul
 	if err != nil {
 		return nil, err
 	}
-	// use aes gcm algorithm to encrypt
-	// mark keyBytes[:12] as nonce
+	// use aes gcm algorithm to decrypt with the derived key and iv
 	aesGCMCipher := sherAes.NewDefaultAesGCM()
-	plainText, err = aesGCMCipher.Decrypt(cipherText, keyBytes, keyBytes, nil)
+	plainText, err = aesGCMCipher.Decrypt(cipherText, keyBytes[:keySize], keyBytes[keySize:], nil)
 	return plainText, err
 }
 
@@ -245,23 +299,43 @@ func (this *Umbral) DecryptOnMyOwnStrKey(aPriKeyStr string, capsule *Capsule, ci
 	return this.DecryptOnMyPriKey(aPriKey, capsule, cipherText)
 }
 
+// EncodeCapsule encodes capsule as hash id || E || V || S, with the points
+// uncompressed and S in 32 big-endian bytes
 func (this *Umbral) EncodeCapsule(capsule Capsule) (capsuleAsBytes []byte, err error) {
-	gob.Register(elliptic.P256())
-	buf := new(bytes.Buffer)
-	enc := gob.NewEncoder(buf)
-	if err = enc.Encode(capsule); err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
+	h := capsule.Hash
+	if h == 0 {
+		h = sutils.SHA256
+	}
+	if !h.Available() {
+		return nil, errors.New("hash function is unavailable")
+	}
+	if capsule.S.Sign() < 0 || capsule.S.BitLen() > 256 {
+		return nil, errors.New("capsule s out of range")
+	}
+	capsuleAsBytes = make([]byte, 0, capsuleSize)
+	capsuleAsBytes = append(capsuleAsBytes, byte(h))
+	capsuleAsBytes = append(capsuleAsBytes, p256Utils.Marshal(capsule.E)...)
+	capsuleAsBytes = append(capsuleAsBytes, p256Utils.Marshal(capsule.V)...)
+	var s [32]byte
+	capsuleAsBytes = append(capsuleAsBytes, capsule.S.FillBytes(s[:])...)
+	return capsuleAsBytes, nil
 }
 
 func (this *Umbral) DecodeCapsule(capsuleAsBytes []byte) (capsule Capsule, err error) {
 	capsule = Capsule{}
-	gob.Register(this.curve)
-	dec := gob.NewDecoder(bytes.NewBuffer(capsuleAsBytes))
-	if err = dec.Decode(&capsule); err != nil {
-		return capsule, err
+	if len(capsuleAsBytes) != capsuleSize {
+		return capsule, errors.New("capsule size error")
+	}
+	capsule.Hash = sutils.Hash(capsuleAsBytes[0])
+	if !capsule.Hash.Available() {
+		return capsule, errors.New("hash function is unavailable")
+	}
+	capsule.E = p256Utils.Unmarshal(capsuleAsBytes[1:66])
+	capsule.V = p256Utils.Unmarshal(capsuleAsBytes[66:131])
+	if capsule.E.X == nil || capsule.V.X == nil {
+		return capsule, errors.New("capsule point is not on the curve")
 	}
+	capsule.S = new(big.Int).SetBytes(capsuleAsBytes[131:])
 	return capsule, nil
 }
 
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"scrypto/dsa/ecdsa"
+	"scrypto/sutils"
 	"testing"
 )
 
@@ -181,3 +182,54 @@ func TestUmbral_Decrypt(t *testing.T) {
 	// get plainText
 	fmt.Println("plainText:", string(plainText))
 }
+
+func TestUmbral_EncodeCapsuleWithHash(t *testing.T) {
+	p256 := elliptic.P256()
+	recryptCipher, err := NewRecryptCipherWithHash(p256, sutils.BLAKE2b_256)
+	if err != nil {
+		panic(err)
+	}
+	ecdsaSigner := ecdsaUtils.NewECDSA(p256)
+	aPriKey, aPubKey, _ := ecdsaSigner.GenerateKeys()
+	bPriKey, bPubKey, _ := ecdsaSigner.GenerateKeys()
+	m := []byte("Hello, Proxy Re-Encryption")
+	cipherText, capsule, err := recryptCipher.Encrypt(m, aPubKey)
+	if err != nil {
+		panic(err)
+	}
+	rk, pubX, err := recryptCipher.ReKeyGen(aPriKey, bPubKey)
+	if err != nil {
+		panic(err)
+	}
+	newCapsule, err := recryptCipher.ReEncryption(rk, capsule)
+	if err != nil {
+		panic(err)
+	}
+	capsuleAsBytes, err := recryptCipher.EncodeCapsule(*newCapsule)
+	if err != nil {
+		panic(err)
+	}
+	// a cipher with the default hash follows the hash of the capsule
+	defaultCipher := NewRecryptCipher(p256)
+	decoded, err := defaultCipher.DecodeCapsule(capsuleAsBytes)
+	if err != nil {
+		panic(err)
+	}
+	if decoded.Hash != sutils.BLAKE2b_256 {
+		t.Error("hash not match:", decoded.Hash)
+	}
+	plainText, err := defaultCipher.Decrypt(bPriKey, &decoded, pubX, cipherText)
+	if err != nil {
+		panic(err)
+	}
+	if string(plainText) != string(m) {
+		t.Error("plainText not match")
+	}
+	// a capsule claiming another hash does not decrypt
+	capsuleAsBytes[0] = byte(sutils.SHA256)
+	decoded, _ = defaultCipher.DecodeCapsule(capsuleAsBytes)
+	_, err = defaultCipher.Decrypt(bPriKey, &decoded, pubX, cipherText)
+	if err == nil {
+		t.Error("capsule decrypted with another hash")
+	}
+}
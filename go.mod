@@ -1,10 +1,30 @@
 module scrypto
 
-go 1.15
+go 1.20
 
 require (
-        golang.org/x/crypto v0.0.0-20200604202706-70a84ac30bf9
-        github.com/consensys/gurvy v0.1.2-0.20200512111154-1662e289e29b
+	github.com/consensys/gurvy v0.1.2-0.20200512111154-1662e289e29b
+	github.com/gtank/ristretto255 v0.1.2
+	golang.org/x/crypto v0.0.0-20200604202706-70a84ac30bf9
+	golang.org/x/text v0.3.2
 )
 
-require github.com/ethereum/go-ethereum v1.9.14
+require (
+	github.com/ethereum/go-ethereum v1.9.14
+	golang.org/x/sys v0.0.0-20200406155108-e3b113bbe6a4
+)
+
+require (
+	github.com/StackExchange/wmi v0.0.0-20180116203802-5d049714c4a6 // indirect
+	github.com/VictoriaMetrics/fastcache v1.5.7 // indirect
+	github.com/aristanetworks/goarista v0.0.0-20170210015632-ea17b1a17847 // indirect
+	github.com/btcsuite/btcd v0.0.0-20171128150713-2e60448ffcc6 // indirect
+	github.com/cespare/xxhash/v2 v2.1.1 // indirect
+	github.com/elastic/gosigar v0.8.1-0.20180330100440-37f05ff46ffa // indirect
+	github.com/go-ole/go-ole v1.2.1 // indirect
+	github.com/go-stack/stack v1.8.0 // indirect
+	github.com/golang/snappy v0.0.1 // indirect
+	github.com/pkg/errors v0.8.1 // indirect
+	github.com/steakknife/bloomfilter v0.0.0-20180922174646-6819c0d2a570 // indirect
+	github.com/steakknife/hamming v0.0.0-20180906055917-c99c65617cd3 // indirect
+)
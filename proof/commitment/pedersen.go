@@ -0,0 +1,233 @@
+// Package commitment implements Pedersen commitments that can be opened,
+// verified and combined homomorphically, over any group of ecc/group.
+package commitment
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"scrypto/ecc/group"
+	"scrypto/smath"
+	"strings"
+)
+
+const (
+	pedersenDomain = "scrypto/commitment/pedersen"
+
+	Opening_Values   = "Values"
+	Opening_Blinding = "Blinding"
+)
+
+// Params are the public parameters of Pedersen commitments over a group:
+// the value generators Gs (Gs[0] is the group generator g) and the blinding
+// generator H. H and Gs[1:] come from HashToPoint, so nobody knows the
+// discrete logarithm of one generator with respect to another.
+type Params struct {
+	Group group.Group
+	H     group.Point
+	Gs    []group.Point
+}
+
+// Commitment is a Pedersen commitment c = h^r \prod g_i^{m_i}
+type Commitment struct {
+	Point group.Point
+}
+
+// Opening holds the committed values m_i and the blinding factor r
+type Opening struct {
+	Values   []*big.Int
+	Blinding *big.Int
+}
+
+// NewParams derives parameters able to commit to vectors of at most n values
+func NewParams(g group.Group, n int) (pp *Params, err error) {
+	if n <= 0 {
+		return nil, errors.New("n should larger than 0")
+	}
+	pp = &Params{
+		Group: g,
+		H:     g.HashToPoint([]byte(pedersenDomain), []byte("H")),
+		Gs:    []group.Point{g.Generator()},
+	}
+	for i := 1; i < n; i++ {
+		var index [4]byte
+		binary.BigEndian.PutUint32(index[:], uint32(i))
+		gi := g.HashToPoint([]byte(pedersenDomain), append([]byte("G"), index[:]...))
+		pp.Gs = append(pp.Gs, gi)
+	}
+	return pp, nil
+}
+
+// Commit commits to values with a fresh random blinding factor.
+// A single value gives the classic c = g^m h^r, several values give the
+// vector commitment c = h^r \prod g_i^{m_i} sharing one blinding factor.
+func (pp *Params) Commit(values ...*big.Int) (c *Commitment, opening *Opening, err error) {
+	r, err := rand.Int(rand.Reader, pp.Group.Order())
+	if err != nil {
+		return nil, nil, err
+	}
+	return pp.CommitWithBlinding(r, values...)
+}
+
+// CommitWithBlinding commits to values with the given blinding factor r
+func (pp *Params) CommitWithBlinding(r *big.Int, values ...*big.Int) (c *Commitment, opening *Opening, err error) {
+	if len(values) == 0 || len(values) > len(pp.Gs) {
+		return nil, nil, errors.New("values size error")
+	}
+	N := pp.Group.Order()
+	opening = &Opening{
+		Blinding: new(big.Int).Mod(r, N),
+	}
+	for _, m := range values {
+		opening.Values = append(opening.Values, new(big.Int).Mod(m, N))
+	}
+	c = &Commitment{Point: pp.compute(opening)}
+	return c, opening, nil
+}
+
+// compute returns h^r \prod g_i^{m_i}
+func (pp *Params) compute(opening *Opening) group.Point {
+	g := pp.Group
+	point := g.ScalarMult(pp.H, opening.Blinding)
+	for i, m := range opening.Values {
+		point = g.Add(point, g.ScalarMult(pp.Gs[i], m))
+	}
+	return point
+}
+
+// Verify checks that opening opens c
+func (pp *Params) Verify(c *Commitment, opening *Opening) bool {
+	if c == nil || opening == nil || opening.Blinding == nil {
+		return false
+	}
+	if len(opening.Values) == 0 || len(opening.Values) > len(pp.Gs) {
+		return false
+	}
+	for _, m := range opening.Values {
+		if m == nil {
+			return false
+		}
+	}
+	return pp.Group.Equal(c.Point, pp.compute(opening))
+}
+
+// Add returns a * b, which commits to the sum of the committed values
+func (pp *Params) Add(a, b *Commitment) *Commitment {
+	return &Commitment{Point: pp.Group.Add(a.Point, b.Point)}
+}
+
+// ScalarMul returns a^k, which commits to k times the committed values
+func (pp *Params) ScalarMul(a *Commitment, k *big.Int) *Commitment {
+	return &Commitment{Point: pp.Group.ScalarMult(a.Point, k)}
+}
+
+// AddOpening returns the opening of Add(a, b), missing values count as zero
+func (pp *Params) AddOpening(a, b *Opening) *Opening {
+	N := pp.Group.Order()
+	n := len(a.Values)
+	if len(b.Values) > n {
+		n = len(b.Values)
+	}
+	res := &Opening{
+		Blinding: smath.Add(a.Blinding, b.Blinding, N),
+	}
+	for i := 0; i < n; i++ {
+		sum := new(big.Int)
+		if i < len(a.Values) {
+			sum.Add(sum, a.Values[i])
+		}
+		if i < len(b.Values) {
+			sum.Add(sum, b.Values[i])
+		}
+		res.Values = append(res.Values, sum.Mod(sum, N))
+	}
+	return res
+}
+
+// ScalarMulOpening returns the opening of ScalarMul(a, k)
+func (pp *Params) ScalarMulOpening(a *Opening, k *big.Int) *Opening {
+	N := pp.Group.Order()
+	res := &Opening{
+		Blinding: smath.Mul(a.Blinding, k, N),
+	}
+	for _, m := range a.Values {
+		res.Values = append(res.Values, smath.Mul(m, k, N))
+	}
+	return res
+}
+
+// Marshal encodes c with the point encoding of the group
+func (pp *Params) Marshal(c *Commitment) []byte {
+	return pp.Group.Marshal(c.Point)
+}
+
+// Unmarshal decodes a commitment and checks that it is a group element
+func (pp *Params) Unmarshal(data []byte) (c *Commitment, err error) {
+	point, err := pp.Group.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Commitment{Point: point}, nil
+}
+
+var errOpening = errors.New("opening without blinding or values")
+
+// scalarHex encodes k in hex, 0 as "00" so that it is never empty
+func scalarHex(k *big.Int) string {
+	if k.Sign() == 0 {
+		return "00"
+	}
+	return hex.EncodeToString(k.Bytes())
+}
+
+// serialize opening
+func (opening *Opening) MarshalJSON() ([]byte, error) {
+	if opening.Blinding == nil || len(opening.Values) == 0 {
+		return nil, errOpening
+	}
+	kv := make(map[string]string)
+	var valuesSlice []string
+	for _, v := range opening.Values {
+		if v == nil {
+			return nil, errOpening
+		}
+		valuesSlice = append(valuesSlice, scalarHex(v))
+	}
+	kv[Opening_Values] = strings.Join(valuesSlice, ",")
+	kv[Opening_Blinding] = scalarHex(opening.Blinding)
+	return json.Marshal(kv)
+}
+
+// deserialize opening
+func (opening *Opening) UnmarshalJSON(data []byte) error {
+	kv := make(map[string]string)
+	err := json.Unmarshal(data, &kv)
+	if err != nil {
+		return err
+	}
+	blinding, ok := kv[Opening_Blinding]
+	if !ok || blinding == "" || kv[Opening_Values] == "" {
+		return errOpening
+	}
+	blindingBytes, err := hex.DecodeString(blinding)
+	if err != nil {
+		return err
+	}
+	var values []*big.Int
+	for _, v := range strings.Split(kv[Opening_Values], ",") {
+		if v == "" {
+			return errOpening
+		}
+		valueBytes, err := hex.DecodeString(v)
+		if err != nil {
+			return err
+		}
+		values = append(values, new(big.Int).SetBytes(valueBytes))
+	}
+	opening.Values = values
+	opening.Blinding = new(big.Int).SetBytes(blindingBytes)
+	return nil
+}
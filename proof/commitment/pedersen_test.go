@@ -0,0 +1,125 @@
+package commitment
+
+import (
+	"encoding/json"
+	"math/big"
+	"scrypto/ecc/group"
+	"testing"
+)
+
+var groups = []group.Group{group.P256(), group.BN256G1(), group.BLS381G1()}
+
+func TestParams_Commit(t *testing.T) {
+	for _, g := range groups {
+		pp, err := NewParams(g, 3)
+		if err != nil {
+			panic(err)
+		}
+		c, opening, err := pp.Commit(big.NewInt(100))
+		if err != nil {
+			panic(err)
+		}
+		if !pp.Verify(c, opening) {
+			t.Error(g.Name(), "valid opening rejected")
+		}
+		opening.Values[0] = big.NewInt(101)
+		if pp.Verify(c, opening) {
+			t.Error(g.Name(), "wrong value accepted")
+		}
+		// single value commitment is the vector commitment of length 1
+		c1, _, _ := pp.CommitWithBlinding(big.NewInt(7), big.NewInt(5))
+		c2, _, _ := pp.CommitWithBlinding(big.NewInt(7), big.NewInt(5), big.NewInt(0))
+		if !g.Equal(c1.Point, c2.Point) {
+			t.Error(g.Name(), "padding with zero changed the commitment")
+		}
+	}
+}
+
+func TestParams_CommitVector(t *testing.T) {
+	for _, g := range groups {
+		pp, _ := NewParams(g, 3)
+		values := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}
+		c, opening, err := pp.Commit(values...)
+		if err != nil {
+			panic(err)
+		}
+		if !pp.Verify(c, opening) {
+			t.Error(g.Name(), "valid vector opening rejected")
+		}
+		// swapping two values must not open the commitment
+		opening.Values[0], opening.Values[1] = opening.Values[1], opening.Values[0]
+		if pp.Verify(c, opening) {
+			t.Error(g.Name(), "permuted values accepted")
+		}
+		if _, _, err := pp.Commit(append(values, big.NewInt(4))...); err == nil {
+			t.Error(g.Name(), "too many values accepted")
+		}
+	}
+}
+
+func TestParams_Homomorphism(t *testing.T) {
+	for _, g := range groups {
+		pp, _ := NewParams(g, 2)
+		a, aOpening, _ := pp.Commit(big.NewInt(3), big.NewInt(4))
+		b, bOpening, _ := pp.Commit(big.NewInt(10))
+		sum := pp.Add(a, b)
+		sumOpening := pp.AddOpening(aOpening, bOpening)
+		if !pp.Verify(sum, sumOpening) || sumOpening.Values[0].Int64() != 13 || sumOpening.Values[1].Int64() != 4 {
+			t.Error(g.Name(), "Add not homomorphic")
+		}
+		k := big.NewInt(-2)
+		mul := pp.ScalarMul(a, k)
+		mulOpening := pp.ScalarMulOpening(aOpening, k)
+		if !pp.Verify(mul, mulOpening) {
+			t.Error(g.Name(), "ScalarMul not homomorphic")
+		}
+	}
+}
+
+func TestParams_Marshal(t *testing.T) {
+	for _, g := range groups {
+		pp, _ := NewParams(g, 2)
+		c, opening, _ := pp.Commit(big.NewInt(42), big.NewInt(0))
+		c2, err := pp.Unmarshal(pp.Marshal(c))
+		if err != nil {
+			panic(err)
+		}
+		openingBytes, err := json.Marshal(opening)
+		if err != nil {
+			panic(err)
+		}
+		opening2 := new(Opening)
+		if err = json.Unmarshal(openingBytes, opening2); err != nil {
+			panic(err)
+		}
+		if !pp.Verify(c2, opening2) {
+			t.Error(g.Name(), "decoded commitment does not verify")
+		}
+	}
+}
+
+func TestOpening_MarshalJSON(t *testing.T) {
+	if _, err := json.Marshal(new(Opening)); err == nil {
+		t.Error("opening without blinding encoded")
+	}
+	if _, err := json.Marshal(&Opening{Blinding: big.NewInt(1)}); err == nil {
+		t.Error("opening without values encoded")
+	}
+	for _, data := range []string{`{}`, `{"Blinding":"01"}`, `{"Values":"01"}`, `{"Values":"","Blinding":"01"}`, `{"Values":"01,","Blinding":"01"}`} {
+		if err := json.Unmarshal([]byte(data), new(Opening)); err == nil {
+			t.Errorf("%s decoded", data)
+		}
+	}
+	opening := &Opening{Values: []*big.Int{big.NewInt(0)}, Blinding: big.NewInt(0)}
+	data, err := json.Marshal(opening)
+	if err != nil {
+		panic(err)
+	}
+	decoded := new(Opening)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		panic(err)
+	}
+	if len(decoded.Values) != 1 || decoded.Values[0].Sign() != 0 || decoded.Blinding.Sign() != 0 {
+		t.Error("zero opening not match")
+	}
+}
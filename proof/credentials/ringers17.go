@@ -4,11 +4,12 @@ import (
 	"crypto/rand"
 	"errors"
 	"fmt"
-	"golang.org/x/crypto/bn256"
 	"math/big"
+	"scrypto/dsa/ringers17"
 	"scrypto/ecc/bn256Utils"
+	"scrypto/ecc/group"
 	"scrypto/proof/sigmaProtocol"
-	"scrypto/dsa/ringers17"
+	"scrypto/proof/transcript"
 	sherMath "scrypto/smath"
 	"scrypto/sutils"
 )
@@ -38,13 +39,13 @@ func (credential *Credential) AddClaimInfo(key string, value *big.Int) {
 
 func NewRingersCredential() (credentialScheme *ringersCredential) {
 	credentialScheme = &ringersCredential{
-		P: bn256.Order,
+		P: bn256Utils.Order,
 	}
 	return credentialScheme
 }
 
-func (ringers *ringersCredential) ProverKeyGen() (sk *big.Int, pk *bn256.G1, err error) {
-	sk, pk, err = bn256.RandomG1(rand.Reader)
+func (ringers *ringersCredential) ProverKeyGen() (sk *big.Int, pk *bn256Utils.G1, err error) {
+	sk, pk, err = bn256Utils.RandomG1(rand.Reader)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -91,8 +92,30 @@ func hideAttributes(claim map[string]*big.Int, C map[string]bool) (hidedPairs ma
 	return hidedPairs, nil
 }
 
+// credentialTranscript binds the presented claim and the re-randomized signature
+// to the proof of a selective credential
+func credentialTranscript(credential *Credential, optionData []byte) *transcript.Transcript {
+	g := group.BN256G1()
+	tr := transcript.New("scrypto/credentials")
+	tr.AppendUint64("claim-size", uint64(len(credential.Keys)))
+	for _, key := range credential.Keys {
+		tr.AppendMessage("claim-key", []byte(key))
+		tr.AppendScalar("claim-value", credential.Claim[key])
+	}
+	sigma := credential.Sigma
+	tr.AppendPoint("K", g, sigma.K)
+	tr.AppendPoint("S", g, sigma.S)
+	for _, Si := range sigma.Ss {
+		tr.AppendPoint("Si", g, Si)
+	}
+	tr.AppendPoint("C", g, sigma.C)
+	tr.AppendPoint("T", g, sigma.T)
+	tr.AppendMessage("option-data", optionData)
+	return tr
+}
+
 // 展示凭证，选择性披露
-func (ringers *ringersCredential) ShowCredential(credential *Credential, pk *bn256.G1, C map[string]bool) (selectiveCredential *Credential, err error) {
+func (ringers *ringersCredential) ShowCredential(credential *Credential, pk *bn256Utils.G1, C map[string]bool) (selectiveCredential *Credential, err error) {
 	// create a new credential
 	selectiveCredential = new(Credential)
 	// get dsa
@@ -139,7 +162,8 @@ func (ringers *ringersCredential) ShowCredential(credential *Credential, pk *bn2
 	//if err != nil {
 	//	return nil, err
 	//}
-	prove, err := nizk.Prove(newSigma.C, pk, nil)
+	tr := credentialTranscript(selectiveCredential, nil)
+	prove, err := nizk.ProveWithTranscript(tr, newSigma.C, pk)
 	if err != nil {
 		return nil, err
 	}
@@ -163,7 +187,8 @@ func (ringers *ringersCredential) Verify(credential *Credential, optionData []by
 	if credential.IsSelective && credential.Proof != nil {
 		// verify zk proof
 		nizk := sigmaProtocol.NewSigmaNIZK(ringers.P)
-		zkRes, err := nizk.Verify(credential.Proof, optionData)
+		tr := credentialTranscript(credential, optionData)
+		zkRes, err := nizk.VerifyWithTranscript(tr, credential.Proof)
 		if err != nil {
 			return false, err
 		}
@@ -6,9 +6,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"golang.org/x/crypto/bn256"
 	"math/big"
 	"scrypto/ecc/bn256Utils"
+	"scrypto/ecc/group"
+	"scrypto/proof/transcript"
 	sherMath "scrypto/smath"
 	"scrypto/sutils"
 	"strings"
@@ -17,6 +18,7 @@ import (
 type sigmaNIZK struct {
 	Pairs []*Pair
 	P     *big.Int
+	Hash  sutils.Hash // transcript hash, 0 for STROBE-128
 }
 
 func NewSigmaNIZK(p *big.Int) (nizk *sigmaNIZK) {
@@ -26,18 +28,32 @@ func NewSigmaNIZK(p *big.Int) (nizk *sigmaNIZK) {
 	return nizk
 }
 
+// NewSigmaNIZKWithHash creates a sigma NIZK whose challenges come from a
+// transcript on the hash h, the proofs record h so verifiers use it too
+func NewSigmaNIZKWithHash(p *big.Int, h sutils.Hash) (nizk *sigmaNIZK, err error) {
+	if !h.Available() {
+		return nil, errors.New("hash function is unavailable")
+	}
+	nizk = &sigmaNIZK{
+		P:    p,
+		Hash: h,
+	}
+	return nizk, nil
+}
+
 type Pair struct {
 	Secret *big.Int
-	Public *bn256.G1
+	Public *bn256Utils.G1
 }
 
 type ProveScheme struct {
-	Commitment *bn256.G1   `json:"Commitment"`
-	Challenge  *big.Int    `json:"Challenge"`
-	Proofs     []*big.Int  `json:"Proofs"`
-	PubValues  []*bn256.G1 `json:"PubValues"`
-	Relation   *bn256.G1   `json:"Relation"`
-	Owner      *bn256.G1   `json:"Owner"`
+	Commitment *bn256Utils.G1   `json:"Commitment"`
+	Challenge  *big.Int         `json:"Challenge"`
+	Proofs     []*big.Int       `json:"Proofs"`
+	PubValues  []*bn256Utils.G1 `json:"PubValues"`
+	Relation   *bn256Utils.G1   `json:"Relation"`
+	Owner      *bn256Utils.G1   `json:"Owner"`
+	Hash       sutils.Hash      `json:"Hash"`
 }
 
 const (
@@ -47,6 +63,7 @@ const (
 	ProveScheme_PubValues  = "PubValues"
 	ProveScheme_Relation   = "Relation"
 	ProveScheme_Owner      = "Owner"
+	ProveScheme_Hash       = "Hash"
 )
 
 // serialize scheme
@@ -76,6 +93,10 @@ func (scheme *ProveScheme) MarshalJSON() ([]byte, error) {
 	kv[ProveScheme_Relation] = hex.EncodeToString(scheme.Relation.Marshal())
 	// Owner
 	kv[ProveScheme_Owner] = hex.EncodeToString(scheme.Owner.Marshal())
+	// Hash, omitted for STROBE-128
+	if scheme.Hash != 0 {
+		kv[ProveScheme_Hash] = scheme.Hash.String()
+	}
 	return json.Marshal(kv)
 }
 
@@ -95,7 +116,7 @@ func (scheme *ProveScheme) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	Commitment, res := new(bn256.G1).Unmarshal(CommitmentBytes)
+	Commitment, res := new(bn256Utils.G1).Unmarshal(CommitmentBytes)
 	if !res {
 		return errors.New("error when unmarshal G1 of CommitmentBytes")
 	}
@@ -119,7 +140,7 @@ func (scheme *ProveScheme) UnmarshalJSON(data []byte) error {
 		if err != nil {
 			return err
 		}
-		pubValue, res := new(bn256.G1).Unmarshal(pubValueBytes)
+		pubValue, res := new(bn256Utils.G1).Unmarshal(pubValueBytes)
 		if !res {
 			return errors.New("error when unmarshal G1 of PubValueBytes")
 		}
@@ -129,20 +150,27 @@ func (scheme *ProveScheme) UnmarshalJSON(data []byte) error {
 	RelationBytes, err := hex.DecodeString(kv[ProveScheme_Relation])
 	// Owner
 	OwnerBytes, err := hex.DecodeString(kv[ProveScheme_Owner])
-	Relation, res := new(bn256.G1).Unmarshal(RelationBytes)
+	Relation, res := new(bn256Utils.G1).Unmarshal(RelationBytes)
 	if !res {
 		return errors.New("error when unmarshal G1 of RelationBytes")
 	}
-	Owner, res := new(bn256.G1).Unmarshal(OwnerBytes)
+	Owner, res := new(bn256Utils.G1).Unmarshal(OwnerBytes)
 	if !res {
 		return errors.New("error when unmarshal G1 of OwnerBytes")
 	}
 	scheme.Relation = Relation
 	scheme.Owner = Owner
+	// Hash
+	if name, ok := kv[ProveScheme_Hash]; ok {
+		scheme.Hash, err = sutils.ParseHash(name)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (this *sigmaNIZK) AddPair(secret *big.Int, public *bn256.G1) {
+func (this *sigmaNIZK) AddPair(secret *big.Int, public *bn256Utils.G1) {
 	pair := &Pair{
 		Secret: secret,
 		Public: public,
@@ -150,7 +178,46 @@ func (this *sigmaNIZK) AddPair(secret *big.Int, public *bn256.G1) {
 	this.Pairs = append(this.Pairs, pair)
 }
 
-func (this *sigmaNIZK) Prove(R *bn256.G1, pk *bn256.G1, optionData []byte) (prove *ProveScheme, err error) {
+const transcriptLabel = "scrypto/sigmaProtocol"
+
+// newTranscript returns the transcript Prove and Verify use for optionData,
+// on the hash h or on STROBE-128 if h is 0
+func newTranscript(h sutils.Hash, optionData []byte) *transcript.Transcript {
+	var tr *transcript.Transcript
+	if h == 0 {
+		tr = transcript.New(transcriptLabel)
+	} else {
+		tr = transcript.NewWithHash(transcriptLabel, h)
+	}
+	tr.AppendMessage("option-data", optionData)
+	return tr
+}
+
+// challenge binds the statement and the commitment t to tr and derives c
+func (this *sigmaNIZK) challenge(tr *transcript.Transcript, pubValues []*bn256Utils.G1, R, pk, t *bn256Utils.G1) *big.Int {
+	g := group.BN256G1()
+	tr.AppendUint64("count", uint64(len(pubValues)))
+	for _, pubValue := range pubValues {
+		tr.AppendPoint("public", g, pubValue)
+	}
+	tr.AppendPoint("relation", g, R)
+	tr.AppendPoint("owner", g, pk)
+	tr.AppendPoint("commitment", g, t)
+	return tr.ChallengeScalar("challenge", this.P)
+}
+
+func (this *sigmaNIZK) Prove(R *bn256Utils.G1, pk *bn256Utils.G1, optionData []byte) (prove *ProveScheme, err error) {
+	prove, err = this.ProveWithTranscript(newTranscript(this.Hash, optionData), R, pk)
+	if err != nil {
+		return nil, err
+	}
+	prove.Hash = this.Hash
+	return prove, nil
+}
+
+// ProveWithTranscript proves the relation with the challenge drawn from tr,
+// callers use it to bind the proof to the context of a larger protocol
+func (this *sigmaNIZK) ProveWithTranscript(tr *transcript.Transcript, R *bn256Utils.G1, pk *bn256Utils.G1) (prove *ProveScheme, err error) {
 	pairs := this.Pairs
 	if len(pairs) <= 0 {
 		return nil, errors.New("claim count should larger than 0")
@@ -160,29 +227,31 @@ func (this *sigmaNIZK) Prove(R *bn256.G1, pk *bn256.G1, optionData []byte) (prov
 	secretCount := len(pairs)
 	// generate random numbers
 	rs := make([]*big.Int, secretCount)
-	rs[0], _ = rand.Int(rand.Reader, this.P)
+	rs[0], err = rand.Int(rand.Reader, this.P)
+	if err != nil {
+		return nil, err
+	}
 	t := bn256Utils.G1ScalarMult(pairs[0].Public, rs[0])
 	for i := 1; i < secretCount; i++ {
-		rs[i], _ = rand.Int(rand.Reader, this.P)
+		rs[i], err = rand.Int(rand.Reader, this.P)
+		if err != nil {
+			return nil, err
+		}
 		// t_i = (pairs[i].Public)^{randNums[i]}
 		// t = \prod t_i
 		ti := bn256Utils.G1ScalarMult(pairs[i].Public, rs[i])
 		t = bn256Utils.G1Add(t, ti)
 	}
-	// c = H(g || t || A || optionData)
-	base := bn256Utils.G1ScalarBaseMult(new(big.Int).SetInt64(1))
-	cPre := sutils.ContactBytes(base.Marshal(), t.Marshal(), pk.Marshal(), optionData)
-	c, err := sutils.GetSha3HashBytes(cPre)
-	if err != nil {
-		return nil, err
+	for i := 0; i < secretCount; i++ {
+		prove.PubValues = append(prove.PubValues, pairs[i].Public)
 	}
-	cInt := new(big.Int).SetBytes(c)
+	// c = H(PubValues || R || A || t) on the transcript
+	cInt := this.challenge(tr, prove.PubValues, R, pk, t)
 	for i := 0; i < secretCount; i++ {
 		// si = ri - c secret
 		c_secret := sherMath.Mul(cInt, pairs[i].Secret, this.P)
 		si := sherMath.Sub(rs[i], c_secret, this.P)
 		prove.Proofs = append(prove.Proofs, si)
-		prove.PubValues = append(prove.PubValues, pairs[i].Public)
 	}
 	prove.Challenge = cInt
 	prove.Commitment = t
@@ -192,9 +261,23 @@ func (this *sigmaNIZK) Prove(R *bn256.G1, pk *bn256.G1, optionData []byte) (prov
 }
 
 func (this *sigmaNIZK) Verify(prove *ProveScheme, optionData []byte) (res bool, err error) {
+	if prove.Hash != 0 && !prove.Hash.Available() {
+		return false, errors.New("hash function is unavailable")
+	}
+	return this.VerifyWithTranscript(newTranscript(prove.Hash, optionData), prove)
+}
+
+// VerifyWithTranscript verifies a proof made by ProveWithTranscript, tr must be
+// in the state the prover's transcript had
+func (this *sigmaNIZK) VerifyWithTranscript(tr *transcript.Transcript, prove *ProveScheme) (res bool, err error) {
 	if len(prove.Proofs) != len(prove.PubValues) {
 		return false, nil
 	}
+	// recompute the challenge
+	c := this.challenge(tr, prove.PubValues, prove.Relation, prove.Owner, prove.Commitment)
+	if c.Cmp(prove.Challenge) != 0 {
+		return false, nil
+	}
 	// check t == (pubValue)^{s[i]} * R^c
 	tVer := bn256Utils.G1ScalarMult(prove.Relation, prove.Challenge)
 	keyCount := len(prove.PubValues)
@@ -212,12 +295,12 @@ func TryOnce() {
 	fmt.Println("-----------Sigma NIZK start-------------")
 	//optionData := []byte("Hello NIZK")
 
-	a, A, err := bn256.RandomG1(rand.Reader)
+	a, A, err := bn256Utils.RandomG1(rand.Reader)
 	if err != nil {
 		panic(err)
 	}
 	base := bn256Utils.G1ScalarBaseMult(new(big.Int).SetInt64(1))
-	nizk := NewSigmaNIZK(bn256.Order)
+	nizk := NewSigmaNIZK(bn256Utils.Order)
 	nizk.AddPair(a, base)
 	prove, err := nizk.Prove(A, A, nil)
 	if err != nil {
@@ -2,18 +2,19 @@ package sigmaProtocol
 
 import (
 	"crypto/rand"
+	"encoding/json"
 	"fmt"
-	"golang.org/x/crypto/bn256"
 	"math/big"
 	"scrypto/ecc/bn256Utils"
+	"scrypto/sutils"
 	"testing"
 )
 
 func TestSchnorrNIZK_Prove(t *testing.T) {
-	nizk := NewSigmaNIZK(bn256.Order)
+	nizk := NewSigmaNIZK(bn256Utils.Order)
 	// we generate prove: A = g^{a}
 	base := bn256Utils.G1ScalarBaseMult(new(big.Int).SetInt64(1))
-	a, A, err := bn256.RandomG1(rand.Reader)
+	a, A, err := bn256Utils.RandomG1(rand.Reader)
 	fmt.Println("Alice public key and proof relation: ", A.String())
 	if err != nil {
 		panic(err)
@@ -29,10 +30,10 @@ func TestSchnorrNIZK_Prove(t *testing.T) {
 }
 
 func TestSchnorrNIZK_Verify(t *testing.T) {
-	nizk := NewSigmaNIZK(bn256.Order)
+	nizk := NewSigmaNIZK(bn256Utils.Order)
 	// we generate prove: A = g^{a}
 	base := bn256Utils.G1ScalarBaseMult(new(big.Int).SetInt64(1))
-	a, A, err := bn256.RandomG1(rand.Reader)
+	a, A, err := bn256Utils.RandomG1(rand.Reader)
 	fmt.Println("Alice public key and proof relation: ", A.String())
 	if err != nil {
 		panic(err)
@@ -53,3 +54,66 @@ func TestSchnorrNIZK_Verify(t *testing.T) {
 	fmt.Println("Verify result:", res)
 
 }
+
+func TestSchnorrNIZK_VerifyOptionData(t *testing.T) {
+	nizk := NewSigmaNIZK(bn256Utils.Order)
+	base := bn256Utils.G1ScalarBaseMult(new(big.Int).SetInt64(1))
+	a, A, err := bn256Utils.RandomG1(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	nizk.AddPair(a, base)
+	prove, err := nizk.Prove(A, A, []byte("Hello NIZK"))
+	if err != nil {
+		panic(err)
+	}
+	res, err := nizk.Verify(prove, []byte("Hello NIZK"))
+	if err != nil || !res {
+		t.Error("valid proof rejected")
+	}
+	// the challenge is bound to the option data
+	res, err = nizk.Verify(prove, []byte("Hello"))
+	if err != nil || res {
+		t.Error("proof accepted with other option data")
+	}
+}
+
+func TestSchnorrNIZK_ProveWithHash(t *testing.T) {
+	nizk, err := NewSigmaNIZKWithHash(bn256Utils.Order, sutils.SHA3_256)
+	if err != nil {
+		panic(err)
+	}
+	base := bn256Utils.G1ScalarBaseMult(new(big.Int).SetInt64(1))
+	a, A, err := bn256Utils.RandomG1(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	nizk.AddPair(a, base)
+	prove, err := nizk.Prove(A, A, []byte("Hello NIZK"))
+	if err != nil {
+		panic(err)
+	}
+	// the hash travels with the proof
+	proveBytes, err := json.Marshal(prove)
+	if err != nil {
+		panic(err)
+	}
+	decoded := new(ProveScheme)
+	err = json.Unmarshal(proveBytes, decoded)
+	if err != nil {
+		panic(err)
+	}
+	if decoded.Hash != sutils.SHA3_256 {
+		t.Error("hash not match:", decoded.Hash)
+	}
+	res, err := NewSigmaNIZK(bn256Utils.Order).Verify(decoded, []byte("Hello NIZK"))
+	if err != nil || !res {
+		t.Error("valid proof rejected")
+	}
+	// a proof on another hash does not verify
+	decoded.Hash = sutils.SHA256
+	res, err = nizk.Verify(decoded, []byte("Hello NIZK"))
+	if err != nil || res {
+		t.Error("proof accepted with another hash")
+	}
+}
@@ -0,0 +1,76 @@
+package transcript
+
+import (
+	"encoding/binary"
+	"scrypto/sutils"
+)
+
+const hashProtocolLabel = "scrypto hash transcript v1"
+
+// operation types of the frames, so that a message never collides with the
+// frame of a challenge or of a ratchet
+const (
+	opAppend byte = iota + 1
+	opChallenge
+	opRatchet
+)
+
+// hashBackend chains the transcript through a hash function: each message
+// replaces the state by H(state || frame), and challenges are squeezed in
+// counter mode from the state before it is ratcheted.
+type hashBackend struct {
+	h     sutils.Hash
+	state []byte
+}
+
+func newHashBackend(h sutils.Hash) *hashBackend {
+	if !h.Available() {
+		panic("transcript: hash function is unavailable")
+	}
+	b := &hashBackend{h: h}
+	b.absorb(opAppend, []byte("protocol"), []byte(hashProtocolLabel+" "+h.String()))
+	return b
+}
+
+func (b *hashBackend) clone() backend {
+	return &hashBackend{
+		h:     b.h,
+		state: append([]byte{}, b.state...),
+	}
+}
+
+// absorb sets state = H(state || op || len(label) || label || len(message) || message)
+func (b *hashBackend) absorb(op byte, label, message []byte) {
+	var size [4]byte
+	d := b.h.New()
+	d.Write(b.state)
+	d.Write([]byte{op})
+	binary.LittleEndian.PutUint32(size[:], uint32(len(label)))
+	d.Write(size[:])
+	d.Write(label)
+	binary.LittleEndian.PutUint32(size[:], uint32(len(message)))
+	d.Write(size[:])
+	d.Write(message)
+	b.state = d.Sum(nil)
+}
+
+func (b *hashBackend) appendMessage(label, message []byte) {
+	b.absorb(opAppend, label, message)
+}
+
+func (b *hashBackend) challengeBytes(label []byte, dest []byte) {
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(dest)))
+	b.absorb(opChallenge, label, size[:])
+	// dest = H(state || 0) || H(state || 1) || ...
+	var counter [4]byte
+	for i := 0; len(dest) > 0; i++ {
+		binary.LittleEndian.PutUint32(counter[:], uint32(i))
+		d := b.h.New()
+		d.Write(b.state)
+		d.Write(counter[:])
+		dest = dest[copy(dest, d.Sum(nil)):]
+	}
+	// ratchet, so the state after a challenge differs from the one it came from
+	b.absorb(opRatchet, nil, nil)
+}
@@ -0,0 +1,122 @@
+package transcript
+
+import "encoding/binary"
+
+// strobe128 is the subset of STROBE-128 (https://strobe.sourceforge.io) used by
+// Merlin: meta-AD, AD and PRF operations over Keccak-f[1600]
+type strobe128 struct {
+	state    [200]byte
+	pos      int
+	posBegin int
+	curFlags byte
+}
+
+const (
+	strobeR = 166
+
+	flagI byte = 1
+	flagA byte = 1 << 1
+	flagC byte = 1 << 2
+	flagT byte = 1 << 3
+	flagM byte = 1 << 4
+	flagK byte = 1 << 5
+)
+
+func newStrobe128(protocolLabel []byte) *strobe128 {
+	s := new(strobe128)
+	copy(s.state[:6], []byte{1, strobeR + 2, 1, 0, 1, 96})
+	copy(s.state[6:18], "STROBEv1.0.2")
+	keccakF1600(&s.state)
+	s.metaAD(protocolLabel, false)
+	return s
+}
+
+func (s *strobe128) clone() backend {
+	c := *s
+	return &c
+}
+
+// appendMessage frames message as Merlin does: meta-AD of the label and the
+// little-endian length, then AD of the message
+func (s *strobe128) appendMessage(label, message []byte) {
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(message)))
+	s.metaAD(label, false)
+	s.metaAD(size[:], true)
+	s.ad(message, false)
+}
+
+func (s *strobe128) challengeBytes(label []byte, dest []byte) {
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(dest)))
+	s.metaAD(label, false)
+	s.metaAD(size[:], true)
+	s.prf(dest, false)
+}
+
+func (s *strobe128) metaAD(data []byte, more bool) {
+	s.beginOp(flagM|flagA, more)
+	s.absorb(data)
+}
+
+func (s *strobe128) ad(data []byte, more bool) {
+	s.beginOp(flagA, more)
+	s.absorb(data)
+}
+
+func (s *strobe128) prf(data []byte, more bool) {
+	s.beginOp(flagI|flagA|flagC, more)
+	s.squeeze(data)
+}
+
+func (s *strobe128) runF() {
+	s.state[s.pos] ^= byte(s.posBegin)
+	s.state[s.pos+1] ^= 0x04
+	s.state[strobeR+1] ^= 0x80
+	keccakF1600(&s.state)
+	s.pos = 0
+	s.posBegin = 0
+}
+
+func (s *strobe128) absorb(data []byte) {
+	for _, b := range data {
+		s.state[s.pos] ^= b
+		s.pos++
+		if s.pos == strobeR {
+			s.runF()
+		}
+	}
+}
+
+func (s *strobe128) squeeze(data []byte) {
+	for i := range data {
+		data[i] = s.state[s.pos]
+		s.state[s.pos] = 0
+		s.pos++
+		if s.pos == strobeR {
+			s.runF()
+		}
+	}
+}
+
+func (s *strobe128) beginOp(flags byte, more bool) {
+	// continuing an operation only makes sense with the same flags
+	if more {
+		if s.curFlags != flags {
+			panic("strobe: continued operation with different flags")
+		}
+		return
+	}
+	// transport operations are not supported
+	if flags&flagT != 0 {
+		panic("strobe: transport operations are not supported")
+	}
+	oldBegin := s.posBegin
+	s.posBegin = s.pos + 1
+	s.curFlags = flags
+	s.absorb([]byte{byte(oldBegin), flags})
+	// force a permutation for cipher and key operations
+	if flags&(flagC|flagK) != 0 && s.pos != 0 {
+		s.runF()
+	}
+}
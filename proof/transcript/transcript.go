@@ -0,0 +1,96 @@
+// Package transcript implements Merlin transcripts (https://merlin.cool) for
+// Fiat-Shamir challenges. Every message is framed by a label and its length,
+// so two different sequences of messages never produce the same challenge.
+//
+// Besides STROBE-128, a transcript can run on any hash of sutils, see NewWithHash.
+package transcript
+
+import (
+	"encoding/binary"
+	"math/big"
+	"scrypto/ecc/group"
+	"scrypto/sutils"
+)
+
+const merlinProtocolLabel = "Merlin v1.0"
+
+// backend is the duplex construction under a transcript
+type backend interface {
+	appendMessage(label, message []byte)
+	challengeBytes(label []byte, dest []byte)
+	clone() backend
+}
+
+// Transcript is a Merlin transcript
+type Transcript struct {
+	backend backend
+}
+
+// New creates a transcript for the protocol named label, on STROBE-128
+func New(label string) *Transcript {
+	t := &Transcript{
+		backend: newStrobe128([]byte(merlinProtocolLabel)),
+	}
+	t.AppendMessage("dom-sep", []byte(label))
+	return t
+}
+
+// NewWithHash creates a transcript for the protocol named label, on the hash
+// function h instead of STROBE-128. Its challenges differ from those of New.
+func NewWithHash(label string, h sutils.Hash) *Transcript {
+	t := &Transcript{
+		backend: newHashBackend(h),
+	}
+	t.AppendMessage("dom-sep", []byte(label))
+	return t
+}
+
+// AppendMessage appends a labelled message to the transcript
+func (t *Transcript) AppendMessage(label string, message []byte) {
+	t.backend.appendMessage([]byte(label), message)
+}
+
+// AppendUint64 appends a labelled integer, encoded in 8 little-endian bytes
+func (t *Transcript) AppendUint64(label string, x uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], x)
+	t.AppendMessage(label, buf[:])
+}
+
+// AppendPoint appends a labelled group element with the encoding of its group
+func (t *Transcript) AppendPoint(label string, g group.Group, p group.Point) {
+	t.AppendMessage(label, g.Marshal(p))
+}
+
+// AppendScalar appends a labelled non-negative integer in big-endian bytes
+func (t *Transcript) AppendScalar(label string, k *big.Int) {
+	t.AppendMessage(label, k.Bytes())
+}
+
+// ChallengeBytes squeezes n challenge bytes out of the transcript
+func (t *Transcript) ChallengeBytes(label string, n int) []byte {
+	res := make([]byte, n)
+	t.backend.challengeBytes([]byte(label), res)
+	return res
+}
+
+// ChallengeScalar returns a challenge uniform modulo order: it squeezes 128 bits
+// more than the size of order and reduces, so the bias is at most 2^{-128}
+func (t *Transcript) ChallengeScalar(label string, order *big.Int) *big.Int {
+	size := (order.BitLen()+7)/8 + 16
+	c := new(big.Int).SetBytes(t.ChallengeBytes(label, size))
+	return c.Mod(c, order)
+}
+
+// Clone returns an independent copy of the transcript
+func (t *Transcript) Clone() *Transcript {
+	return &Transcript{backend: t.backend.clone()}
+}
+
+// Fork returns a copy of the transcript bound to label, so that the branches
+// of a protocol which share a prefix derive unrelated challenges
+func (t *Transcript) Fork(label string) *Transcript {
+	fork := t.Clone()
+	fork.AppendMessage("fork", []byte(label))
+	return fork
+}
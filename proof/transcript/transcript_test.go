@@ -0,0 +1,135 @@
+package transcript
+
+import (
+	"bytes"
+	"encoding/hex"
+	"golang.org/x/crypto/sha3"
+	"math/big"
+	"scrypto/ecc/group"
+	"scrypto/sutils"
+	"testing"
+)
+
+// sha3Sum256 is SHA3-256 as a sponge on keccakF1600, to test the permutation
+func sha3Sum256(m []byte) []byte {
+	const rate = 136
+	var state [200]byte
+	padded := append(append([]byte{}, m...), 0x06)
+	for len(padded)%rate != 0 {
+		padded = append(padded, 0)
+	}
+	padded[len(padded)-1] |= 0x80
+	for i := 0; i < len(padded); i += rate {
+		for j := 0; j < rate; j++ {
+			state[j] ^= padded[i+j]
+		}
+		keccakF1600(&state)
+	}
+	return state[:32]
+}
+
+func TestKeccakF1600(t *testing.T) {
+	for _, m := range [][]byte{nil, []byte("abc"), bytes.Repeat([]byte{0xa3}, 200)} {
+		want := sha3.Sum256(m)
+		if !bytes.Equal(sha3Sum256(m), want[:]) {
+			t.Error("keccak-f[1600] not match for message of size", len(m))
+		}
+	}
+}
+
+func TestTranscript_Simple(t *testing.T) {
+	tr := New("test protocol")
+	tr.AppendMessage("some label", []byte("some data"))
+	c := hex.EncodeToString(tr.ChallengeBytes("challenge", 32))
+	if c != "d5a21972d0d5fe320c0d263fac7fffb8145aa640af6e9bca177c03c7efcf0615" {
+		t.Error("challenge not match:", c)
+	}
+}
+
+func TestTranscript_Complex(t *testing.T) {
+	tr := New("test protocol")
+	tr.AppendMessage("step1", []byte("some data"))
+	data := bytes.Repeat([]byte{99}, 1024)
+	var c []byte
+	for i := 0; i < 32; i++ {
+		c = tr.ChallengeBytes("challenge", 32)
+		tr.AppendMessage("bigdata", data)
+		tr.AppendMessage("challengedata", c)
+	}
+	if hex.EncodeToString(c) != "a8c933f54fae76e3f9bea93648c1308e7dfa2152dd51674ff3ca438351cf003c" {
+		t.Error("challenge not match:", hex.EncodeToString(c))
+	}
+}
+
+func TestTranscript_Framing(t *testing.T) {
+	// the same bytes split differently must give different challenges
+	a := New("framing")
+	a.AppendMessage("m", []byte("ab"))
+	a.AppendMessage("m", []byte("c"))
+	b := New("framing")
+	b.AppendMessage("m", []byte("a"))
+	b.AppendMessage("m", []byte("bc"))
+	if bytes.Equal(a.ChallengeBytes("c", 32), b.ChallengeBytes("c", 32)) {
+		t.Error("different framings give the same challenge")
+	}
+}
+
+func TestTranscript_CloneAndFork(t *testing.T) {
+	g := group.P256()
+	tr := New("fork")
+	tr.AppendPoint("point", g, g.Generator())
+	tr.AppendScalar("scalar", big.NewInt(42))
+	clone := tr.Clone()
+	if !bytes.Equal(tr.ChallengeBytes("c", 32), clone.ChallengeBytes("c", 32)) {
+		t.Error("clone diverged from the transcript")
+	}
+	left, right := tr.Fork("left"), tr.Fork("right")
+	if bytes.Equal(left.ChallengeBytes("c", 32), right.ChallengeBytes("c", 32)) {
+		t.Error("forks give the same challenge")
+	}
+}
+
+func TestTranscript_ChallengeScalar(t *testing.T) {
+	order := group.BLS381G1().Order()
+	tr := New("scalar")
+	for i := 0; i < 16; i++ {
+		c := tr.ChallengeScalar("c", order)
+		if c.Sign() < 0 || c.Cmp(order) >= 0 {
+			t.Error("challenge out of range:", c)
+		}
+	}
+}
+
+func TestTranscript_NewWithHash(t *testing.T) {
+	run := func(h sutils.Hash, message string) []byte {
+		tr := NewWithHash("hash", h)
+		tr.AppendMessage("m", []byte(message))
+		return tr.ChallengeBytes("c", 100)
+	}
+	for _, h := range []sutils.Hash{sutils.SHA256, sutils.SHA512, sutils.SHA3_256, sutils.Keccak256, sutils.BLAKE2b_256, sutils.BLAKE2s_256} {
+		c := run(h, "data")
+		if len(c) != 100 || !bytes.Equal(c, run(h, "data")) {
+			t.Error("challenge not deterministic for", h)
+		}
+		if bytes.Equal(c, run(h, "other")) {
+			t.Error("challenge does not depend on the message for", h)
+		}
+	}
+	if bytes.Equal(run(sutils.SHA256, "data"), run(sutils.SHA3_256, "data")) {
+		t.Error("different hashes give the same challenge")
+	}
+	// a message never takes the frame of a challenge or of a ratchet
+	challenged := NewWithHash("hash", sutils.SHA256)
+	challenged.ChallengeBytes("c", 32)
+	appended := NewWithHash("hash", sutils.SHA256)
+	appended.AppendMessage("c", []byte{32, 0, 0, 0})
+	appended.AppendMessage("", nil)
+	if bytes.Equal(challenged.ChallengeBytes("c", 32), appended.ChallengeBytes("c", 32)) {
+		t.Error("messages collide with a challenge")
+	}
+	// successive challenges differ
+	tr := NewWithHash("hash", sutils.SHA256)
+	if bytes.Equal(tr.ChallengeBytes("c", 32), tr.ChallengeBytes("c", 32)) {
+		t.Error("successive challenges are equal")
+	}
+}
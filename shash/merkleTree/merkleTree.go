@@ -2,20 +2,37 @@ package merkleTree
 
 import (
 	"bytes"
-	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"hash"
 	"scrypto/sutils"
+	"strconv"
+	"strings"
 )
 
 type MerkleTree struct {
 	Root         *Node            // Root node of Merkle Tree
 	Leaves       []*Node          // Leaves
 	hashStrategy func() hash.Hash // hash function
+	hashID       sutils.Hash      // id of the hash function, 0 if not registered
 	rootHash     []byte           // root node hash value
 }
 
+// Proof is a Merkle path of a value, with the hash the tree is built on
+type Proof struct {
+	Hash  sutils.Hash // hash function
+	Path  [][]byte    // sibling hashes from the leaf to the root
+	Index []int64     // 1 if the sibling is a right node, 0 if it is a left node
+}
+
+const (
+	Proof_Hash  = "Hash"
+	Proof_Path  = "Path"
+	Proof_Index = "Index"
+)
+
 type Node struct {
 	Parent  *Node  // parent node
 	Left    *Node  // left node
@@ -27,17 +44,19 @@ type Node struct {
 
 //NewTree creates a new Merkle Tree using the bytes vector.
 func NewTree(values [][]byte) (t *MerkleTree, err error) {
-	var defaultHashStrategy = sha256.New
-	t = &MerkleTree{
-		hashStrategy: defaultHashStrategy,
+	return NewTreeWithHash(values, sutils.SHA256)
+}
+
+//NewTreeWithHash creates a new Merkle Tree using the hash function h.
+func NewTreeWithHash(values [][]byte, h sutils.Hash) (t *MerkleTree, err error) {
+	if !h.Available() {
+		return nil, errors.New("error: hash function is unavailable")
 	}
-	root, leaves, err := buildWithValues(values, t)
+	t, err = NewTreeWithHashStrategy(values, h.New)
 	if err != nil {
 		return nil, err
 	}
-	t.Root = root
-	t.Leaves = leaves
-	t.rootHash = root.HashVal
+	t.hashID = h
 	return t, nil
 }
 
@@ -178,6 +197,99 @@ func (mt *MerkleTree) VerifyValue(value []byte) (bool, error) {
 	return false, nil
 }
 
+// GetProof returns the Merkle proof of value, nil if value is not in the tree
+func (mt *MerkleTree) GetProof(value []byte) (proof *Proof, err error) {
+	if mt.hashID == 0 {
+		return nil, errors.New("error: hash strategy is not a registered hash")
+	}
+	merklePath, index, err := mt.GetMerklePath(value)
+	if err != nil || merklePath == nil {
+		return nil, err
+	}
+	proof = &Proof{
+		Hash:  mt.hashID,
+		Path:  merklePath,
+		Index: index,
+	}
+	return proof, nil
+}
+
+// VerifyProof checks that proof links value to the Merkle root
+func VerifyProof(root []byte, value []byte, proof *Proof) (bool, error) {
+	if !proof.Hash.Available() {
+		return false, errors.New("error: hash function is unavailable")
+	}
+	if len(proof.Path) != len(proof.Index) {
+		return false, nil
+	}
+	hashVal, err := sutils.HashBytes(proof.Hash, value)
+	if err != nil {
+		return false, err
+	}
+	for i, sibling := range proof.Path {
+		var cBytes []byte
+		if proof.Index[i] == 1 {
+			cBytes = append(append(cBytes, hashVal...), sibling...)
+		} else {
+			cBytes = append(append(cBytes, sibling...), hashVal...)
+		}
+		hashVal, err = sutils.HashBytes(proof.Hash, cBytes)
+		if err != nil {
+			return false, err
+		}
+	}
+	return bytes.Equal(hashVal, root), nil
+}
+
+// serialize proof
+func (proof *Proof) MarshalJSON() ([]byte, error) {
+	kv := make(map[string]string)
+	kv[Proof_Hash] = proof.Hash.String()
+	var pathSlice, indexSlice []string
+	for i, v := range proof.Path {
+		pathSlice = append(pathSlice, hex.EncodeToString(v))
+		indexSlice = append(indexSlice, strconv.FormatInt(proof.Index[i], 10))
+	}
+	kv[Proof_Path] = strings.Join(pathSlice, ",")
+	kv[Proof_Index] = strings.Join(indexSlice, ",")
+	return json.Marshal(kv)
+}
+
+// deserialize proof
+func (proof *Proof) UnmarshalJSON(data []byte) error {
+	kv := make(map[string]string)
+	err := json.Unmarshal(data, &kv)
+	if err != nil {
+		return err
+	}
+	h, err := sutils.ParseHash(kv[Proof_Hash])
+	if err != nil {
+		return err
+	}
+	var path [][]byte
+	var index []int64
+	if kv[Proof_Path] != "" {
+		for _, v := range strings.Split(kv[Proof_Path], ",") {
+			node, err := hex.DecodeString(v)
+			if err != nil {
+				return err
+			}
+			path = append(path, node)
+		}
+		for _, v := range strings.Split(kv[Proof_Index], ",") {
+			i, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return err
+			}
+			index = append(index, i)
+		}
+	}
+	proof.Hash = h
+	proof.Path = path
+	proof.Index = index
+	return nil
+}
+
 //String returns a string representation of the node.
 func (n *Node) String() string {
 	return fmt.Sprintf("%t %t %v", n.leaf, n.dup, n.HashVal)
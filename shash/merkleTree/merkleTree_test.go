@@ -2,8 +2,9 @@ package merkleTree
 
 import (
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
-	"shercrypto/xutils"
+	"scrypto/sutils"
 	"testing"
 )
 
@@ -20,7 +21,7 @@ var (
 //1	        2	    3	        4
 
 func TestNewTree(t *testing.T) {
-	ageBytes, _ := xutils.GetHashValue(age, sha256.New)
+	ageBytes, _ := sutils.GetHashValue(age, sha256.New)
 	fmt.Println("ageBytes:", ageBytes)
 	tree, err := NewTree(values)
 	if err != nil {
@@ -54,3 +55,39 @@ func TestMerkleTree_VerifyValue(t *testing.T) {
 	}
 	fmt.Println("is valid value:", isValid)
 }
+
+func TestMerkleTree_VerifyProof(t *testing.T) {
+	tree, err := NewTreeWithHash(values, sutils.BLAKE2s_256)
+	if err != nil {
+		panic(err)
+	}
+	proof, err := tree.GetProof(age)
+	if err != nil {
+		panic(err)
+	}
+	proofBytes, err := json.Marshal(proof)
+	if err != nil {
+		panic(err)
+	}
+	decoded := new(Proof)
+	err = json.Unmarshal(proofBytes, decoded)
+	if err != nil {
+		panic(err)
+	}
+	isValid, err := VerifyProof(tree.MerkleRoot(), age, decoded)
+	if err != nil {
+		panic(err)
+	}
+	if !isValid {
+		t.Error("valid proof rejected")
+	}
+	isValid, _ = VerifyProof(tree.MerkleRoot(), name, decoded)
+	if isValid {
+		t.Error("proof accepted for another value")
+	}
+	decoded.Hash = sutils.SHA256
+	isValid, _ = VerifyProof(tree.MerkleRoot(), age, decoded)
+	if isValid {
+		t.Error("proof accepted with another hash")
+	}
+}
@@ -0,0 +1,213 @@
+package smath
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+)
+
+var (
+	zero = big.NewInt(0)
+	one  = big.NewInt(1)
+	two  = big.NewInt(2)
+)
+
+// Field is the prime field Z/PZ. Its methods take any integer and always
+// return a new integer reduced in [0, P).
+type Field struct {
+	P *big.Int
+}
+
+// NewField returns the field modulo the odd prime p, primality is checked
+// with 20 Miller-Rabin rounds
+func NewField(p *big.Int) (f *Field, err error) {
+	if p.Cmp(two) <= 0 || p.Bit(0) == 0 || !p.ProbablyPrime(20) {
+		return nil, errors.New("modulus should be an odd prime")
+	}
+	return &Field{P: new(big.Int).Set(p)}, nil
+}
+
+// Reduce returns a mod P
+func (f *Field) Reduce(a *big.Int) *big.Int {
+	return new(big.Int).Mod(a, f.P)
+}
+
+func (f *Field) Add(a, b *big.Int) *big.Int {
+	return Add(a, b, f.P)
+}
+
+func (f *Field) Sub(a, b *big.Int) *big.Int {
+	return Sub(a, b, f.P)
+}
+
+func (f *Field) Mul(a, b *big.Int) *big.Int {
+	return Mul(a, b, f.P)
+}
+
+// Neg returns -a mod P
+func (f *Field) Neg(a *big.Int) *big.Int {
+	return Sub(zero, a, f.P)
+}
+
+// Inv returns a^{-1} mod P, or an error if a = 0 mod P
+func (f *Field) Inv(a *big.Int) (*big.Int, error) {
+	res := new(big.Int).ModInverse(f.Reduce(a), f.P)
+	if res == nil {
+		return nil, errors.New("zero has no inverse")
+	}
+	return res, nil
+}
+
+// Exp returns a^e mod P, a negative e inverts a first
+func (f *Field) Exp(a, e *big.Int) (*big.Int, error) {
+	base := f.Reduce(a)
+	if e.Sign() < 0 {
+		inv, err := f.Inv(base)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).Exp(inv, new(big.Int).Neg(e), f.P), nil
+	}
+	return new(big.Int).Exp(base, e, f.P), nil
+}
+
+// Legendre returns the Legendre symbol (a/P): 0, 1 or -1
+func (f *Field) Legendre(a *big.Int) int {
+	return big.Jacobi(f.Reduce(a), f.P)
+}
+
+// Sqrt returns a square root of a with Tonelli-Shanks, ok is false when a is
+// not a quadratic residue
+func (f *Field) Sqrt(a *big.Int) (root *big.Int, ok bool) {
+	a = f.Reduce(a)
+	switch f.Legendre(a) {
+	case 0:
+		return new(big.Int), true
+	case -1:
+		return nil, false
+	}
+	p := f.P
+	// P = 3 mod 4: a^{(P+1)/4}
+	if p.Bit(1) == 1 {
+		e := new(big.Int).Add(p, one)
+		return new(big.Int).Exp(a, e.Rsh(e, 2), p), true
+	}
+	// P - 1 = q 2^s with q odd
+	q := new(big.Int).Sub(p, one)
+	s := 0
+	for q.Bit(0) == 0 {
+		q.Rsh(q, 1)
+		s++
+	}
+	// z is a quadratic non-residue
+	z := big.NewInt(2)
+	for f.Legendre(z) != -1 {
+		z.Add(z, one)
+	}
+	m := s
+	c := new(big.Int).Exp(z, q, p)
+	t := new(big.Int).Exp(a, q, p)
+	e := new(big.Int).Add(q, one)
+	root = new(big.Int).Exp(a, e.Rsh(e, 1), p)
+	for t.Cmp(one) != 0 {
+		// least i such that t^{2^i} = 1
+		i := 0
+		for t2 := new(big.Int).Set(t); t2.Cmp(one) != 0; i++ {
+			t2.Mul(t2, t2).Mod(t2, p)
+		}
+		// b = c^{2^{m-i-1}}
+		b := new(big.Int).Set(c)
+		for j := 0; j < m-i-1; j++ {
+			b.Mul(b, b).Mod(b, p)
+		}
+		m = i
+		c.Mul(b, b).Mod(c, p)
+		t.Mul(t, c).Mod(t, p)
+		root.Mul(root, b).Mod(root, p)
+	}
+	return root, true
+}
+
+// RandomElement draws a uniform element of [0, P) from random, crypto/rand
+// if random is nil
+func (f *Field) RandomElement(random io.Reader) (*big.Int, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	return rand.Int(random, f.P)
+}
+
+// RandomNonZero draws a uniform element of [1, P)
+func (f *Field) RandomNonZero(random io.Reader) (*big.Int, error) {
+	for {
+		a, err := f.RandomElement(random)
+		if err != nil {
+			return nil, err
+		}
+		if a.Sign() != 0 {
+			return a, nil
+		}
+	}
+}
+
+// BatchInverse inverts all the elements with a single inversion
+// (Montgomery's trick), it fails if one of them is zero
+func (f *Field) BatchInverse(elements []*big.Int) ([]*big.Int, error) {
+	n := len(elements)
+	res := make([]*big.Int, n)
+	if n == 0 {
+		return res, nil
+	}
+	// prefix[i] = elements[0] * ... * elements[i-1]
+	prefix := make([]*big.Int, n)
+	acc := big.NewInt(1)
+	for i, a := range elements {
+		prefix[i] = acc
+		acc = f.Mul(acc, a)
+	}
+	inv, err := f.Inv(acc)
+	if err != nil {
+		return nil, err
+	}
+	for i := n - 1; i >= 0; i-- {
+		res[i] = f.Mul(inv, prefix[i])
+		inv = f.Mul(inv, elements[i])
+	}
+	return res, nil
+}
+
+// Jacobi returns the Jacobi symbol (a/n) for odd n
+func Jacobi(a, n *big.Int) int {
+	return big.Jacobi(a, n)
+}
+
+// CRT returns the x in [0, \prod moduli) with x = residues[i] mod moduli[i],
+// moduli should be pairwise coprime
+func CRT(residues, moduli []*big.Int) (*big.Int, error) {
+	if len(residues) != len(moduli) || len(moduli) == 0 {
+		return nil, errors.New("residues and moduli size not match")
+	}
+	N := big.NewInt(1)
+	for _, n := range moduli {
+		if n.Sign() <= 0 {
+			return nil, errors.New("moduli should be positive")
+		}
+		N.Mul(N, n)
+	}
+	x := new(big.Int)
+	for i, n := range moduli {
+		// Ni = N / n, yi = Ni^{-1} mod n
+		Ni := new(big.Int).Div(N, n)
+		yi := new(big.Int).ModInverse(Ni, n)
+		if yi == nil && n.Cmp(one) != 0 {
+			return nil, errors.New("moduli should be pairwise coprime")
+		}
+		if yi == nil {
+			continue
+		}
+		term := new(big.Int).Mul(residues[i], Ni)
+		x.Add(x, term.Mul(term, yi))
+	}
+	return x.Mod(x, N), nil
+}
@@ -0,0 +1,102 @@
+package smath
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestField_Sqrt(t *testing.T) {
+	// 3 mod 4, 5 mod 8 and a prime with 2^32 | p-1 (Tonelli-Shanks does the work)
+	for _, pStr := range []string{
+		"115792089210356248762697446949407573530086143415290314195533631308867097853951",
+		"57896044618658097711785492504343953926634992332820282019728792003956564819949",
+		"52435875175126190479447740508185965837690552500527637822603658699938581184513",
+	} {
+		p, _ := new(big.Int).SetString(pStr, 10)
+		f, err := NewField(p)
+		if err != nil {
+			panic(err)
+		}
+		for i := 0; i < 20; i++ {
+			a, err := f.RandomElement(rand.Reader)
+			if err != nil {
+				panic(err)
+			}
+			root, ok := f.Sqrt(a)
+			if ok != (f.Legendre(a) >= 0) {
+				t.Error("sqrt existence not match the Legendre symbol")
+			}
+			if ok && f.Mul(root, root).Cmp(a) != 0 {
+				t.Error("wrong square root of", a)
+			}
+		}
+	}
+}
+
+func TestField_Arithmetic(t *testing.T) {
+	f, err := NewField(big.NewInt(101))
+	if err != nil {
+		panic(err)
+	}
+	if f.Neg(big.NewInt(3)).Cmp(big.NewInt(98)) != 0 {
+		t.Error("Neg not reduced")
+	}
+	e, _ := f.Exp(big.NewInt(5), big.NewInt(-1))
+	if f.Mul(e, big.NewInt(5)).Cmp(one) != 0 {
+		t.Error("negative exponent not match the inverse")
+	}
+	if _, err := f.Inv(big.NewInt(202)); err == nil {
+		t.Error("zero inverted")
+	}
+	elements := []*big.Int{big.NewInt(2), big.NewInt(3), big.NewInt(100), big.NewInt(57)}
+	invs, err := f.BatchInverse(elements)
+	if err != nil {
+		panic(err)
+	}
+	for i := range elements {
+		if f.Mul(elements[i], invs[i]).Cmp(one) != 0 {
+			t.Error("batch inverse not match at", i)
+		}
+	}
+	if _, err := NewField(big.NewInt(91)); err == nil {
+		t.Error("composite modulus accepted")
+	}
+}
+
+func TestCRT(t *testing.T) {
+	x, err := CRT(
+		[]*big.Int{big.NewInt(2), big.NewInt(3), big.NewInt(2)},
+		[]*big.Int{big.NewInt(3), big.NewInt(5), big.NewInt(7)})
+	if err != nil {
+		panic(err)
+	}
+	if x.Int64() != 23 {
+		t.Error("CRT not match:", x)
+	}
+	_, err = CRT([]*big.Int{big.NewInt(1), big.NewInt(2)}, []*big.Int{big.NewInt(4), big.NewInt(6)})
+	if err == nil {
+		t.Error("moduli not coprime accepted")
+	}
+}
+
+func TestGenerateSafePrime(t *testing.T) {
+	p, err := GenerateSafePrime(rand.Reader, 256, 20)
+	if err != nil {
+		panic(err)
+	}
+	q := new(big.Int).Rsh(p, 1)
+	if p.BitLen() != 256 || !p.ProbablyPrime(20) || !q.ProbablyPrime(20) {
+		t.Error("not a safe prime:", p)
+	}
+}
+
+func TestGenerateStrongPrime(t *testing.T) {
+	p, err := GenerateStrongPrime(rand.Reader, 512, 20)
+	if err != nil {
+		panic(err)
+	}
+	if p.BitLen() != 512 || !IsProbablePrime(p, 20) {
+		t.Error("not a prime of 512 bits:", p)
+	}
+}
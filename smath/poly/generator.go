@@ -0,0 +1,55 @@
+//go:build ignore
+// +build ignore
+
+// generator writes the polynomial package of every scalar field from the
+// templates of smath/poly/template, run it with go generate in smath/poly
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+const header = "// Code generated by smath/poly/generator.go. DO NOT EDIT.\n\n"
+
+// field is a scalar field with its package of polynomials
+type field struct {
+	Package    string
+	Curve      string // the fr package is scrypto/ecc/<Curve>/fr
+	TwoAdicity int    // r - 1 = q 2^TwoAdicity with q odd
+}
+
+var fields = []field{
+	{"bn256Poly", "bn256", 28},
+	{"bls381Poly", "bls381", 32},
+}
+
+var files = []string{"domain.go", "polynomial.go", "polynomial_test.go"}
+
+func main() {
+	funcs := template.FuncMap{"inc": func(i int) int { return i + 1 }}
+	for _, name := range files {
+		t := template.Must(template.New(name).Funcs(funcs).ParseFiles(filepath.Join("template", name+".tmpl")))
+		for _, f := range fields {
+			var buf bytes.Buffer
+			buf.WriteString(header)
+			if err := t.ExecuteTemplate(&buf, name+".tmpl", f); err != nil {
+				panic(err)
+			}
+			src, err := format.Source(buf.Bytes())
+			if err != nil {
+				panic(err)
+			}
+			if err := os.MkdirAll(f.Package, 0755); err != nil {
+				panic(err)
+			}
+			if err := ioutil.WriteFile(filepath.Join(f.Package, name), src, 0644); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
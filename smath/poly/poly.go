@@ -0,0 +1,32 @@
+// Package poly holds the field independent parts of the polynomial packages:
+// index helpers of the radix-2 NTT. Loops run in parallel with ecc.Execute.
+//
+// Polynomials live in one package per scalar field, since the fields do not
+// share an element type: bn256Poly over ecc/bn256/fr and bls381Poly over
+// ecc/bls381/fr. Both are generated from the templates of smath/poly/template
+// by generator.go, so a fix goes in the template and reaches every field.
+package poly
+
+//go:generate go run generator.go
+
+import (
+	"math/bits"
+)
+
+// NextPowerOfTwo returns the least power of two larger or equal to n
+func NextPowerOfTwo(n uint64) uint64 {
+	if n <= 1 {
+		return 1
+	}
+	return 1 << uint(bits.Len64(n-1))
+}
+
+// Log2 returns log_2(n) for n a power of two
+func Log2(n uint64) int {
+	return bits.TrailingZeros64(n)
+}
+
+// BitReverse returns i with its log2n low bits in reverse order
+func BitReverse(i uint64, log2n int) uint64 {
+	return bits.Reverse64(i) >> uint(64-log2n)
+}
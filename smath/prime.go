@@ -0,0 +1,133 @@
+package smath
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// small odd primes used to sieve candidates before Miller-Rabin
+var smallPrimes = []uint64{
+	3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71, 73,
+	79, 83, 89, 97, 101, 103, 107, 109, 113, 127, 131, 137, 139, 149, 151, 157,
+}
+
+// IsProbablePrime runs rounds Miller-Rabin tests with random bases, followed
+// by a Baillie-PSW test
+func IsProbablePrime(n *big.Int, rounds int) bool {
+	return n.ProbablyPrime(rounds)
+}
+
+// hasSmallFactor reports whether n is divisible by a small prime other than n
+func hasSmallFactor(n *big.Int) bool {
+	m := new(big.Int)
+	for _, p := range smallPrimes {
+		m.SetUint64(p)
+		if n.Cmp(m) == 0 {
+			return false
+		}
+		if m.Mod(n, m).Sign() == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// randomOdd draws an odd integer of exactly bits bits
+func randomOdd(random io.Reader, bits int) (*big.Int, error) {
+	max := new(big.Int).Lsh(one, uint(bits-1))
+	n, err := rand.Int(random, max)
+	if err != nil {
+		return nil, err
+	}
+	n.Add(n, max)
+	n.SetBit(n, 0, 1)
+	return n, nil
+}
+
+// randomPrime draws a prime of exactly bits bits
+func randomPrime(random io.Reader, bits, rounds int) (*big.Int, error) {
+	for {
+		p, err := randomOdd(random, bits)
+		if err != nil {
+			return nil, err
+		}
+		if !hasSmallFactor(p) && p.ProbablyPrime(rounds) {
+			return p, nil
+		}
+	}
+}
+
+// GenerateSafePrime returns a prime p of bits bits such that (p-1)/2 is prime
+// too, both tested with rounds Miller-Rabin rounds
+func GenerateSafePrime(random io.Reader, bits, rounds int) (p *big.Int, err error) {
+	if bits < 3 {
+		return nil, errors.New("prime size too small")
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+	for {
+		q, err := randomOdd(random, bits-1)
+		if err != nil {
+			return nil, err
+		}
+		p = new(big.Int).Lsh(q, 1)
+		p.Add(p, one)
+		if hasSmallFactor(q) || hasSmallFactor(p) {
+			continue
+		}
+		if q.ProbablyPrime(rounds) && p.ProbablyPrime(rounds) {
+			return p, nil
+		}
+	}
+}
+
+// GenerateStrongPrime returns a strong prime p of bits bits with Gordon's
+// algorithm: p-1 has a large prime factor r, p+1 has a large prime factor s
+// and r-1 has a large prime factor t
+func GenerateStrongPrime(random io.Reader, bits, rounds int) (p *big.Int, err error) {
+	if bits < 64 {
+		return nil, errors.New("prime size too small")
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+	for {
+		s, err := randomPrime(random, bits/2-4, rounds)
+		if err != nil {
+			return nil, err
+		}
+		t, err := randomPrime(random, bits/2-8, rounds)
+		if err != nil {
+			return nil, err
+		}
+		// r is the first prime in 2it + 1
+		r := new(big.Int).Lsh(t, 1)
+		r.Add(r, one)
+		twoT := new(big.Int).Lsh(t, 1)
+		for hasSmallFactor(r) || !r.ProbablyPrime(rounds) {
+			r.Add(r, twoT)
+		}
+		// p0 = 2 (s^{r-2} mod r) s - 1
+		p0 := new(big.Int).Exp(s, new(big.Int).Sub(r, two), r)
+		p0.Mul(p0, s).Lsh(p0, 1).Sub(p0, one)
+		// p is the first prime in p0 + 2jrs of bits bits
+		step := new(big.Int).Mul(r, s)
+		step.Lsh(step, 1)
+		low := new(big.Int).Lsh(one, uint(bits-1))
+		p = new(big.Int).Set(p0)
+		if p.Cmp(low) < 0 {
+			j := new(big.Int).Sub(low, p)
+			j.Add(j, step).Sub(j, one).Div(j, step)
+			p.Add(p, j.Mul(j, step))
+		}
+		for p.BitLen() == bits {
+			if !hasSmallFactor(p) && p.ProbablyPrime(rounds) {
+				return p, nil
+			}
+			p.Add(p, step)
+		}
+	}
+}
@@ -25,6 +25,7 @@ func ModInverse(a, N *big.Int) (res *big.Int) {
 	return res
 }
 
+// Neg returns -a, not reduced: use Field.Neg or Sub(0, a, N) modulo N
 func Neg(a *big.Int) (res *big.Int) {
 	return new(big.Int).Neg(a)
 }
@@ -3,7 +3,6 @@ package sutils
 import (
 	"crypto/elliptic"
 	"crypto/rand"
-	"crypto/sha256"
 	"encoding/hex"
 	"hash"
 	"math/big"
@@ -11,15 +10,17 @@ import (
 )
 
 /**
-	计算SHA3哈希值
+	计算SHA3-256哈希值
  */
 func GetSha3HashBytes(m []byte) (hash []byte, err error) {
-	sha := sha256.New()
-	_, err = sha.Write(m)
-	if err != nil {
-		return nil, err
-	}
-	return sha.Sum(nil), nil
+	return HashBytes(SHA3_256, m)
+}
+
+/**
+	计算SHA-256哈希值
+ */
+func GetSha256HashBytes(m []byte) (hash []byte, err error) {
+	return HashBytes(SHA256, m)
 }
 
 /**
@@ -35,19 +36,22 @@ func GetHashValue(m []byte, hFunction func() hash.Hash) ([]byte, error) {
 }
 
 /**
-	计算SHA3哈希值并转换为字符串
+	计算SHA3-256哈希值并转换为字符串
  */
 func GetSha3HashStr(m string) (hash string, err error) {
-	hashBytes, err := GetSha3HashBytes([]byte(m))
-	if err != nil {
-		return "", err
-	}
-	hash = hex.EncodeToString(hashBytes)
-	return hash, nil
+	return HashStr(SHA3_256, m)
+}
+
+/**
+	计算SHA-256哈希值并转换为字符串
+ */
+func GetSha256HashStr(m string) (hash string, err error) {
+	return HashStr(SHA256, m)
 }
 
 /**
 	计算单个值的Pedersen承诺
+	Deprecated: the blinding factor is discarded, use proof/commitment instead
  */
 func ComputeCommitmentBytes(value *big.Int) (c []byte, err error) {
 	// c = g^m h^{\alpha}
@@ -64,6 +68,7 @@ func ComputeCommitmentBytes(value *big.Int) (c []byte, err error) {
 
 /**
 	计算向量的Pedersen承诺
+	Deprecated: the blinding factors are discarded, use proof/commitment instead
  */
 func ComputeVecCommitmentBytes(values []*big.Int) (c []byte, err error) {
 	n := len(values)
@@ -0,0 +1,119 @@
+package sutils
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+	"golang.org/x/crypto/sha3"
+	"hash"
+)
+
+// Hash identifies a hash algorithm. The values are stable: they are written
+// into serialized proofs, capsules and Merkle proofs, so never renumber them.
+type Hash uint8
+
+const (
+	SHA256 Hash = 1 + iota
+	SHA512
+	SHA3_256
+	Keccak256
+	BLAKE2b_256
+	BLAKE2s_256
+	SHA384
+	maxHash
+)
+
+var hashNames = map[Hash]string{
+	SHA256:      "SHA-256",
+	SHA512:      "SHA-512",
+	SHA3_256:    "SHA3-256",
+	Keccak256:   "Keccak-256",
+	BLAKE2b_256: "BLAKE2b-256",
+	BLAKE2s_256: "BLAKE2s-256",
+	SHA384:      "SHA-384",
+}
+
+var errUnknownHash = errors.New("unknown hash algorithm")
+
+// Available reports whether h is a registered hash algorithm
+func (h Hash) Available() bool {
+	return h > 0 && h < maxHash
+}
+
+// New returns a new hash.Hash computing h, it panics if h is not available
+func (h Hash) New() hash.Hash {
+	switch h {
+	case SHA256:
+		return sha256.New()
+	case SHA512:
+		return sha512.New()
+	case SHA3_256:
+		return sha3.New256()
+	case Keccak256:
+		return sha3.NewLegacyKeccak256()
+	case BLAKE2b_256:
+		d, _ := blake2b.New256(nil)
+		return d
+	case BLAKE2s_256:
+		d, _ := blake2s.New256(nil)
+		return d
+	case SHA384:
+		return sha512.New384()
+	}
+	panic("sutils: requested hash function is unavailable")
+}
+
+// Size returns the length in bytes of a digest of h
+func (h Hash) Size() int {
+	switch h {
+	case SHA512:
+		return sha512.Size
+	case SHA384:
+		return sha512.Size384
+	}
+	if h.Available() {
+		return 32
+	}
+	panic("sutils: requested hash function is unavailable")
+}
+
+func (h Hash) String() string {
+	if name, ok := hashNames[h]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseHash returns the hash algorithm named name, as printed by String
+func ParseHash(name string) (Hash, error) {
+	for h, n := range hashNames {
+		if n == name {
+			return h, nil
+		}
+	}
+	return 0, errUnknownHash
+}
+
+/**
+	计算h的哈希值
+ */
+func HashBytes(h Hash, m []byte) ([]byte, error) {
+	if !h.Available() {
+		return nil, errUnknownHash
+	}
+	return GetHashValue(m, h.New)
+}
+
+/**
+	计算h的哈希值并转换为字符串
+ */
+func HashStr(h Hash, m string) (string, error) {
+	hashBytes, err := HashBytes(h, []byte(m))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hashBytes), nil
+}
@@ -0,0 +1,40 @@
+package sutils
+
+import (
+	"testing"
+)
+
+func TestHash_Str(t *testing.T) {
+	// digests of "abc"
+	vectors := map[Hash]string{
+		SHA256:      "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		SHA512:      "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+		SHA3_256:    "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532",
+		Keccak256:   "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45",
+		BLAKE2b_256: "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319",
+		BLAKE2s_256: "508c5e8c327c14e2e1a72ba34eeb452f37458b209ed63a294d999b4c86675982",
+		SHA384:      "cb00753f45a35e8bb5a03d699ac65007272c32ab0eded1631a8b605a43ff5bed8086072ba1e7cc2358baeca134c825a7",
+	}
+	for h, want := range vectors {
+		got, err := HashStr(h, "abc")
+		if err != nil {
+			panic(err)
+		}
+		if got != want {
+			t.Error(h, "not match:", got)
+		}
+		if len(got) != 2*h.Size() {
+			t.Error(h, "size not match:", h.Size())
+		}
+		parsed, err := ParseHash(h.String())
+		if err != nil || parsed != h {
+			t.Error("cannot parse", h)
+		}
+	}
+	if sha3, _ := GetSha3HashStr("abc"); sha3 != vectors[SHA3_256] {
+		t.Error("GetSha3HashStr is not SHA3-256")
+	}
+	if _, err := HashBytes(0, nil); err == nil {
+		t.Error("unknown hash accepted")
+	}
+}
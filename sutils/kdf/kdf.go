@@ -0,0 +1,75 @@
+// Package kdf turns shared secrets, such as marshalled group elements, into
+// symmetric keys with HKDF (RFC 5869).
+//
+// Key schedule: DeriveKeys extracts prk = HKDF-Extract(salt, secret) once,
+// then expands every requested key separately as
+//
+//	key_i = HKDF-Expand(prk, info || i || len_i, len_i)
+//
+// with i and len_i in 4 big-endian bytes, so keys of a derivation (an aes key
+// and its nonce for instance) are independent, and changing the length of one
+// key never changes another.
+package kdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"golang.org/x/crypto/hkdf"
+	"io"
+	"scrypto/sutils"
+)
+
+var errUnavailableHash = errors.New("hash function is unavailable")
+
+// Extract returns the pseudorandom key HKDF-Extract(salt, secret) with hash h
+func Extract(h sutils.Hash, secret, salt []byte) (prk []byte, err error) {
+	if !h.Available() {
+		return nil, errUnavailableHash
+	}
+	return hkdf.Extract(h.New, secret, salt), nil
+}
+
+// Expand returns length bytes of HKDF-Expand(prk, info) with hash h
+func Expand(h sutils.Hash, prk, info []byte, length int) (key []byte, err error) {
+	if !h.Available() {
+		return nil, errUnavailableHash
+	}
+	if length < 0 || length > 255*h.Size() {
+		return nil, errors.New("key length out of range")
+	}
+	key = make([]byte, length)
+	_, err = io.ReadFull(hkdf.Expand(h.New, prk, info), key)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// DeriveKeys derives one key per length from secret with HKDF-SHA-256, info
+// labels the purpose of the keys
+func DeriveKeys(secret, salt, info []byte, lengths ...int) (keys [][]byte, err error) {
+	return DeriveKeysWithHash(sutils.SHA256, secret, salt, info, lengths...)
+}
+
+// DeriveKeysWithHash is DeriveKeys with HKDF on the hash h
+func DeriveKeysWithHash(h sutils.Hash, secret, salt, info []byte, lengths ...int) (keys [][]byte, err error) {
+	if len(lengths) == 0 {
+		return nil, errors.New("lengths should not be empty")
+	}
+	prk, err := Extract(h, secret, salt)
+	if err != nil {
+		return nil, err
+	}
+	for i, length := range lengths {
+		var suffix [8]byte
+		binary.BigEndian.PutUint32(suffix[:4], uint32(i))
+		binary.BigEndian.PutUint32(suffix[4:], uint32(length))
+		keyInfo := append(append([]byte{}, info...), suffix[:]...)
+		key, err := Expand(h, prk, keyInfo, length)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
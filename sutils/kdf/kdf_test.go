@@ -0,0 +1,60 @@
+package kdf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"scrypto/sutils"
+	"testing"
+)
+
+// RFC 5869, test case 1
+func TestExpand(t *testing.T) {
+	secret := bytes.Repeat([]byte{0x0b}, 22)
+	salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+	prk, err := Extract(sutils.SHA256, secret, salt)
+	if err != nil {
+		panic(err)
+	}
+	if hex.EncodeToString(prk) != "077709362c2e32df0ddc3f0dc47bba6390b6c73bb50f9c3122ec844ad7c2b3e5" {
+		t.Error("prk not match:", hex.EncodeToString(prk))
+	}
+	okm, err := Expand(sutils.SHA256, prk, info, 42)
+	if err != nil {
+		panic(err)
+	}
+	if hex.EncodeToString(okm) != "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865" {
+		t.Error("okm not match:", hex.EncodeToString(okm))
+	}
+	if _, err := Expand(sutils.SHA256, prk, info, 255*32+1); err == nil {
+		t.Error("too long key accepted")
+	}
+}
+
+func TestDeriveKeys(t *testing.T) {
+	secret := []byte("shared secret")
+	keys, err := DeriveKeys(secret, []byte("salt"), []byte("info"), 32, 12)
+	if err != nil {
+		panic(err)
+	}
+	if len(keys[0]) != 32 || len(keys[1]) != 12 {
+		t.Error("key sizes not match")
+	}
+	if bytes.Equal(keys[0][:12], keys[1]) {
+		t.Error("nonce is a prefix of the key")
+	}
+	// a key does not depend on the lengths of the others
+	others, err := DeriveKeys(secret, []byte("salt"), []byte("info"), 32, 16)
+	if err != nil {
+		panic(err)
+	}
+	if !bytes.Equal(keys[0], others[0]) {
+		t.Error("key depends on the length of the nonce")
+	}
+	// info and hash separate the derivations
+	otherInfo, _ := DeriveKeys(secret, []byte("salt"), []byte("other"), 32)
+	otherHash, _ := DeriveKeysWithHash(sutils.SHA512, secret, []byte("salt"), []byte("info"), 32)
+	if bytes.Equal(keys[0], otherInfo[0]) || bytes.Equal(keys[0], otherHash[0]) {
+		t.Error("derivations not separated")
+	}
+}
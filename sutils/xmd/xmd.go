@@ -0,0 +1,58 @@
+// Package xmd implements expand_message_xmd of RFC 9380 (section 5.3.1) with
+// SHA-256, the expander under hash_to_field of every curve: the BLS12-381
+// hashes to G1 and G2, the P-256 hash to curve and the hashes to scalars.
+package xmd
+
+import (
+	"crypto/sha256"
+	"errors"
+)
+
+var (
+	errDstTooLong = errors.New("dst longer than 255 bytes")
+	errTooLong    = errors.New("expand_message_xmd output too long")
+)
+
+// Expand is expand_message_xmd with SHA-256, it returns lenInBytes
+// pseudorandom bytes from msg and the domain separation tag dst
+func Expand(msg, dst []byte, lenInBytes int) ([]byte, error) {
+	if len(dst) > 255 {
+		return nil, errDstTooLong
+	}
+	ell := (lenInBytes + sha256.Size - 1) / sha256.Size
+	if ell > 255 || lenInBytes > 65535 || lenInBytes < 0 {
+		return nil, errTooLong
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	// b_0 = H(Z_pad || msg || l_i_b_str || I2OSP(0, 1) || DST_prime)
+	h := sha256.New()
+	h.Write(make([]byte, sha256.BlockSize))
+	h.Write(msg)
+	h.Write([]byte{byte(lenInBytes >> 8), byte(lenInBytes), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	// b_1 = H(b_0 || I2OSP(1, 1) || DST_prime)
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write(dstPrime)
+	bi := h.Sum(nil)
+
+	res := make([]byte, 0, ell*sha256.Size)
+	res = append(res, bi...)
+	for i := 2; i <= ell; i++ {
+		// b_i = H(strxor(b_0, b_(i-1)) || I2OSP(i, 1) || DST_prime)
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+		h.Reset()
+		h.Write(bi)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		res = append(res, bi...)
+	}
+	return res[:lenInBytes], nil
+}